@@ -2,6 +2,9 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"os"
 	"strings"
 	"time"
 
@@ -9,6 +12,8 @@ import (
 	"github.com/ulfhaga/gagent-cli/internal/auth"
 	"github.com/ulfhaga/gagent-cli/internal/calendar"
 	"github.com/ulfhaga/gagent-cli/internal/config"
+	"github.com/ulfhaga/gagent-cli/internal/dateparse"
+	"github.com/ulfhaga/gagent-cli/internal/gmail"
 	"github.com/ulfhaga/gagent-cli/internal/output"
 )
 
@@ -60,6 +65,74 @@ func calendarWriteService(ctx context.Context) (*calendar.Service, error) {
 	return calendar.NewService(ctx, client)
 }
 
+// calendarLocation returns the time zone to resolve date/time flag values
+// in. The calendar's zone is only looked up when a value has no explicit
+// offset, so RFC3339 input needs no extra request.
+func calendarLocation(svc *calendar.Service, calendarID string, values ...string) (*time.Location, error) {
+	if !needsTimeZone(values...) {
+		return time.UTC, nil
+	}
+	return svc.Location(calendarID)
+}
+
+// needsTimeZone reports whether any non-empty value lacks a UTC offset.
+func needsTimeZone(values ...string) bool {
+	for _, v := range values {
+		if v == "" {
+			continue
+		}
+		if _, err := time.Parse(time.RFC3339, strings.TrimSpace(v)); err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTimeFlag parses a date/time flag value such as "tomorrow 14:00",
+// "next tue 9am", "+2h" or an RFC3339 timestamp.
+func parseTimeFlag(flag, value string, now time.Time, loc *time.Location) (time.Time, error) {
+	t, err := dateparse.Parse(value, now, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --%s: %v", flag, err)
+	}
+	return t, nil
+}
+
+// parseEndFlag resolves an end time from either --end or --duration.
+func parseEndFlag(end, duration string, start, now time.Time, loc *time.Location) (time.Time, error) {
+	if duration != "" {
+		end, err := dateparse.AddDuration(start, duration)
+		if err != nil || !end.After(start) {
+			return time.Time{}, fmt.Errorf("invalid --duration %q (use e.g. 30m, 1h30m)", duration)
+		}
+		return end, nil
+	}
+	endTime, err := parseTimeFlag("end", end, now, loc)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !endTime.After(start) {
+		return time.Time{}, fmt.Errorf("--end must be after --start")
+	}
+	return endTime, nil
+}
+
+// calendarWriteError outputs an error from a calendar write, reporting
+// double-bookings as CONFLICT with the overlapping events and suggestions.
+func calendarWriteError(err error) {
+	var conflict *calendar.ConflictError
+	if errors.As(err, &conflict) {
+		output.Failure(output.ErrConflict, err.Error(), conflict)
+		return
+	}
+	output.APIError(err)
+}
+
+// timeFlagHelp describes the accepted date/time flag values.
+const timeFlagHelp = `Times accept RFC3339 or expressions such as "tomorrow 14:00",
+"next tue 9am", "+2h", "oct 20 15:30" or "2026-10-20 15:30 Europe/Berlin".
+Times without an offset or zone are in the calendar's time zone.`
+
 func calendarTodayCmd() *cobra.Command {
 	var calendarID string
 
@@ -163,6 +236,164 @@ func calendarUpcomingCmd() *cobra.Command {
 	return cmd
 }
 
+func calendarAgendaCmd() *cobra.Command {
+	var calendarID, rangeSpec, tz, format string
+
+	cmd := &cobra.Command{
+		Use:   "agenda",
+		Short: "Render an agenda grouped by day",
+		Long: `Renders events as a day-by-day agenda in Markdown or plain text, ready to
+paste into chat or a prompt. Times are local to --tz (default: the
+calendar's time zone). All-day events, your RSVP status, Meet links,
+overlapping events (CONFLICT) and free gaps of 30 minutes or more are shown.
+
+The response includes the rendered agenda and the same data as structured
+days.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if format != calendar.AgendaMarkdown && format != calendar.AgendaText {
+				output.InvalidInputError("Invalid format. Use: md, text")
+				return
+			}
+
+			ctx := context.Background()
+			svc, err := calendarReadService(ctx)
+			if err != nil {
+				output.FailureFromError(output.ErrAuthRequired, err)
+				return
+			}
+
+			var loc *time.Location
+			if tz != "" {
+				if loc, err = time.LoadLocation(tz); err != nil {
+					output.InvalidInputError("Invalid time zone: " + tz)
+					return
+				}
+			} else if loc, err = svc.Location(calendarID); err != nil {
+				output.APIError(err)
+				return
+			}
+
+			from, to, err := calendar.ParseAgendaRange(rangeSpec, time.Now(), loc)
+			if err != nil {
+				output.InvalidInputError(err.Error())
+				return
+			}
+
+			agenda, err := svc.Agenda(calendarID, from, to, loc)
+			if err != nil {
+				output.APIError(err)
+				return
+			}
+
+			rendered, err := agenda.Render(format)
+			if err != nil {
+				output.InvalidInputError(err.Error())
+				return
+			}
+
+			output.Success(map[string]interface{}{
+				"time_zone": agenda.TimeZone,
+				"from":      agenda.From,
+				"to":        agenda.To,
+				"format":    format,
+				"agenda":    rendered,
+				"days":      agenda.Days,
+				"count":     agenda.Count,
+			}, "read")
+		},
+	}
+
+	cmd.Flags().StringVar(&calendarID, "calendar", "", "Calendar ID (default: primary)")
+	cmd.Flags().StringVar(&rangeSpec, "range", "today", "Range: today, tomorrow, week, N days")
+	cmd.Flags().StringVar(&tz, "tz", "", "IANA time zone (default: the calendar's)")
+	cmd.Flags().StringVar(&format, "format", calendar.AgendaMarkdown, "Format: md, text")
+
+	return cmd
+}
+
+func calendarStatsCmd() *cobra.Command {
+	var calendarID, from, to string
+
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Summarize time spent in meetings",
+		Long: `Computes meeting statistics between --from and --to: hours in meetings per
+day and ISO week, meeting counts by size, recurring vs one-off hours, top
+co-attendees, the share of meeting time with other domains, fragmentation
+(free blocks longer than 1h within working hours) and your decline rate.
+
+A meeting is a timed event with at least one other guest that you have not
+declined. Days, weeks and working hours use the calendar's time zone;
+working hours come from the working_hours config setting (default
+09:00-17:00 mon-fri).
+
+` + timeFlagHelp,
+		Run: func(cmd *cobra.Command, args []string) {
+			var spec string
+			if cfg, err := config.Load(); err == nil {
+				spec = cfg.WorkingHours["default"]
+			}
+
+			ctx := context.Background()
+			svc, err := calendarReadService(ctx)
+			if err != nil {
+				output.FailureFromError(output.ErrAuthRequired, err)
+				return
+			}
+
+			loc, err := svc.Location(calendarID)
+			if err != nil {
+				output.APIError(err)
+				return
+			}
+
+			hours, err := calendar.ParseWorkingHours(spec, loc)
+			if err != nil {
+				output.InvalidInputError("Invalid working_hours: " + err.Error())
+				return
+			}
+
+			now := time.Now()
+			fromTime, err := parseTimeFlag("from", from, now, loc)
+			if err != nil {
+				output.InvalidInputError(err.Error())
+				return
+			}
+			toTime, err := parseTimeFlag("to", to, now, loc)
+			if err != nil {
+				output.InvalidInputError(err.Error())
+				return
+			}
+			if !toTime.After(fromTime) {
+				output.InvalidInputError("--to must be after --from")
+				return
+			}
+
+			stats, err := svc.Stats(calendar.StatsOptions{
+				CalendarID: calendarID,
+				From:       fromTime,
+				To:         toTime,
+				Location:   loc,
+				Hours:      hours,
+			})
+			if err != nil {
+				output.APIError(err)
+				return
+			}
+
+			output.Success(stats, "read")
+		},
+	}
+
+	cmd.Flags().StringVar(&calendarID, "calendar", "", "Calendar ID (default: primary)")
+	cmd.Flags().StringVar(&from, "from", "", "Start of the range (required)")
+	cmd.Flags().StringVar(&to, "to", "", "End of the range (required)")
+	cmd.MarkFlagRequired("from")
+	cmd.MarkFlagRequired("to")
+
+	return cmd
+}
+
 func calendarEventCmd() *cobra.Command {
 	var calendarID string
 
@@ -200,7 +431,7 @@ func calendarFindCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "find <query>",
 		Short: "Search events",
-		Long:  "Search events by text.",
+		Long:  "Search events by text.\n\n" + timeFlagHelp,
 		Args:  cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			ctx := context.Background()
@@ -210,16 +441,26 @@ func calendarFindCmd() *cobra.Command {
 				return
 			}
 
-			var fromTime, toTime time.Time
+			loc, err := calendarLocation(svc, "", from, to)
+			if err != nil {
+				output.APIError(err)
+				return
+			}
+
+			now := time.Now()
+			fromTime := now.AddDate(-1, 0, 0) // Default: 1 year ago
 			if from != "" {
-				fromTime, _ = time.Parse(time.RFC3339, from)
-			} else {
-				fromTime = time.Now().AddDate(-1, 0, 0) // Default: 1 year ago
+				if fromTime, err = parseTimeFlag("from", from, now, loc); err != nil {
+					output.InvalidInputError(err.Error())
+					return
+				}
 			}
+			toTime := now.AddDate(1, 0, 0) // Default: 1 year from now
 			if to != "" {
-				toTime, _ = time.Parse(time.RFC3339, to)
-			} else {
-				toTime = time.Now().AddDate(1, 0, 0) // Default: 1 year from now
+				if toTime, err = parseTimeFlag("to", to, now, loc); err != nil {
+					output.InvalidInputError(err.Error())
+					return
+				}
 			}
 
 			events, err := svc.Find(args[0], fromTime, toTime)
@@ -230,25 +471,27 @@ func calendarFindCmd() *cobra.Command {
 
 			output.Success(map[string]interface{}{
 				"query":  args[0],
+				"from":   fromTime.Format(time.RFC3339),
+				"to":     toTime.Format(time.RFC3339),
 				"events": events,
 				"count":  len(events),
 			}, "read")
 		},
 	}
 
-	cmd.Flags().StringVar(&from, "from", "", "Start date (RFC3339)")
-	cmd.Flags().StringVar(&to, "to", "", "End date (RFC3339)")
+	cmd.Flags().StringVar(&from, "from", "", "Start time (default: 1 year ago)")
+	cmd.Flags().StringVar(&to, "to", "", "End time (default: 1 year from now)")
 
 	return cmd
 }
 
 func calendarFreeBusyCmd() *cobra.Command {
-	var start, end, calendars string
+	var start, end, duration, calendars string
 
 	cmd := &cobra.Command{
 		Use:   "free-busy",
 		Short: "Check availability",
-		Long:  "Returns busy/free slots in time range.",
+		Long:  "Returns busy/free slots in time range.\n\n" + timeFlagHelp,
 		Run: func(cmd *cobra.Command, args []string) {
 			ctx := context.Background()
 			svc, err := calendarReadService(ctx)
@@ -257,23 +500,22 @@ func calendarFreeBusyCmd() *cobra.Command {
 				return
 			}
 
-			startTime, err := time.Parse(time.RFC3339, start)
+			loc, err := calendarLocation(svc, "", start, end)
 			if err != nil {
-				// Try simpler format
-				startTime, err = time.Parse("2006-01-02T15:04:05", start)
-				if err != nil {
-					output.InvalidInputError("Invalid start time format. Use RFC3339 (e.g., 2024-01-16T09:00:00Z)")
-					return
-				}
+				output.APIError(err)
+				return
 			}
 
-			endTime, err := time.Parse(time.RFC3339, end)
+			now := time.Now()
+			startTime, err := parseTimeFlag("start", start, now, loc)
 			if err != nil {
-				endTime, err = time.Parse("2006-01-02T15:04:05", end)
-				if err != nil {
-					output.InvalidInputError("Invalid end time format. Use RFC3339 (e.g., 2024-01-16T17:00:00Z)")
-					return
-				}
+				output.InvalidInputError(err.Error())
+				return
+			}
+			endTime, err := parseEndFlag(end, duration, startTime, now, loc)
+			if err != nil {
+				output.InvalidInputError(err.Error())
+				return
 			}
 
 			var calendarIDs []string
@@ -292,41 +534,51 @@ func calendarFreeBusyCmd() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&start, "start", "", "Start datetime (required)")
-	cmd.Flags().StringVar(&end, "end", "", "End datetime (required)")
+	cmd.Flags().StringVar(&end, "end", "", "End datetime")
+	cmd.Flags().StringVar(&duration, "duration", "", "Length of the range instead of --end, e.g. 8h")
 	cmd.Flags().StringVar(&calendars, "calendars", "", "Calendar IDs (comma-separated)")
 
 	cmd.MarkFlagRequired("start")
-	cmd.MarkFlagRequired("end")
+	cmd.MarkFlagsMutuallyExclusive("end", "duration")
+	cmd.MarkFlagsOneRequired("end", "duration")
 
 	return cmd
 }
 
-func calendarScheduleCmd() *cobra.Command {
-	var title, description, location, calendarID, attendees string
-	var start, end string
-	var dryRun bool
+func calendarFindTimeCmd() *cobra.Command {
+	var attendees, duration, within, buffer, step string
+	var limit int
 
 	cmd := &cobra.Command{
-		Use:   "schedule",
-		Short: "Create an event",
-		Long:  "Creates event, sends invites if attendees specified.",
+		Use:   "find-time",
+		Short: "Find meeting times for attendees",
+		Long: `Finds times when you and all attendees are free, within everyone's
+working hours and outside lunch, ranked by how comfortably they fit
+everyone's day and how soon they are.
+
+Your time zone comes from your primary calendar; attendees without their own
+entry use their calendar's zone when it is visible to you. Working hours
+default to 09:00-17:00 mon-fri with lunch 12:00-13:00 and can be configured:
+
+  gagent-cli config set working_hours "09:00-17:00 mon-fri lunch 12:00-13:00"
+  gagent-cli config set working_hours.alice@example.com "America/New_York 08:00-16:00"
+
+Slot start/end can be passed directly to calendar schedule.`,
 		Run: func(cmd *cobra.Command, args []string) {
-			startTime, err := time.Parse(time.RFC3339, start)
-			if err != nil {
-				startTime, err = time.Parse("2006-01-02T15:04:05", start)
-				if err != nil {
-					output.InvalidInputError("Invalid start time format")
-					return
-				}
+			meetingDuration, err := time.ParseDuration(duration)
+			if err != nil || meetingDuration <= 0 {
+				output.InvalidInputError("Invalid duration (use e.g. 30m, 1h30m)")
+				return
 			}
-
-			endTime, err := time.Parse(time.RFC3339, end)
-			if err != nil {
-				endTime, err = time.Parse("2006-01-02T15:04:05", end)
-				if err != nil {
-					output.InvalidInputError("Invalid end time format")
-					return
-				}
+			bufferDuration, err := time.ParseDuration(buffer)
+			if err != nil || bufferDuration < 0 {
+				output.InvalidInputError("Invalid buffer (use e.g. 10m)")
+				return
+			}
+			stepDuration, err := time.ParseDuration(step)
+			if err != nil || stepDuration <= 0 {
+				output.InvalidInputError("Invalid step (use e.g. 15m)")
+				return
 			}
 
 			var attendeeList []string
@@ -334,102 +586,218 @@ func calendarScheduleCmd() *cobra.Command {
 				attendeeList = strings.Split(attendees, ",")
 			}
 
-			if dryRun {
-				output.SuccessNoScope(map[string]interface{}{
-					"dry_run":     true,
-					"title":       title,
-					"start":       startTime.Format(time.RFC3339),
-					"end":         endTime.Format(time.RFC3339),
-					"location":    location,
-					"description": description,
-					"attendees":   attendeeList,
-				})
-				return
+			var hours map[string]string
+			if cfg, err := config.Load(); err == nil {
+				hours = cfg.WorkingHours
 			}
 
 			ctx := context.Background()
-			svc, err := calendarWriteService(ctx)
+			svc, err := calendarReadService(ctx)
 			if err != nil {
-				output.Failure(output.ErrScopeInsufficient, err.Error(), nil)
+				output.FailureFromError(output.ErrAuthRequired, err)
 				return
 			}
 
-			result, err := svc.Create(calendar.CreateEventOptions{
-				CalendarID:  calendarID,
-				Title:       title,
-				Description: description,
-				Location:    location,
-				Start:       startTime,
-				End:         endTime,
-				Attendees:   attendeeList,
+			loc, err := svc.Location("primary")
+			if err != nil {
+				output.APIError(err)
+				return
+			}
+
+			from, to, err := calendar.ParseWithin(within, time.Now(), loc)
+			if err != nil {
+				output.InvalidInputError(err.Error())
+				return
+			}
+
+			result, err := svc.FindTime(calendar.FindTimeOptions{
+				Attendees: attendeeList,
+				Duration:  meetingDuration,
+				From:      from,
+				To:        to,
+				Buffer:    bufferDuration,
+				Step:      stepDuration,
+				Limit:     limit,
+				Location:  loc,
+				Hours:     hours,
 			})
 			if err != nil {
 				output.APIError(err)
 				return
 			}
 
-			output.Success(result, "write")
+			output.Success(map[string]interface{}{
+				"attendees":    attendees,
+				"from":         result.From,
+				"to":           result.To,
+				"duration":     result.Duration,
+				"participants": result.Participants,
+				"slots":        result.Slots,
+				"count":        len(result.Slots),
+			}, "read")
 		},
 	}
 
-	cmd.Flags().StringVar(&title, "title", "", "Event title (required)")
-	cmd.Flags().StringVar(&start, "start", "", "Start datetime (required)")
-	cmd.Flags().StringVar(&end, "end", "", "End datetime (required)")
-	cmd.Flags().StringVar(&calendarID, "calendar", "", "Calendar ID (default: primary)")
-	cmd.Flags().StringVar(&location, "location", "", "Event location")
-	cmd.Flags().StringVar(&description, "description", "", "Event description")
 	cmd.Flags().StringVar(&attendees, "attendees", "", "Attendee emails (comma-separated)")
-	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be created")
+	cmd.Flags().StringVar(&duration, "duration", "", "Meeting length, e.g. 45m (required)")
+	cmd.Flags().StringVar(&within, "within", "next 5 business days", "Search window: today, tomorrow, this week, next week, next N days, next N business days")
+	cmd.Flags().StringVar(&buffer, "buffer", "0m", "Free time required before and after the meeting")
+	cmd.Flags().StringVar(&step, "step", "15m", "Granularity of candidate start times")
+	cmd.Flags().IntVarP(&limit, "limit", "n", 10, "Maximum slots")
 
-	cmd.MarkFlagRequired("title")
-	cmd.MarkFlagRequired("start")
-	cmd.MarkFlagRequired("end")
+	cmd.MarkFlagRequired("duration")
 
 	return cmd
 }
 
-func calendarRescheduleCmd() *cobra.Command {
-	var start, end, calendarID string
+func calendarScheduleCmd() *cobra.Command {
+	var title, description, location, calendarID, attendees, optionalAttendees string
+	var start, end, duration, repeat, color, visibility, transparency, sendUpdates string
+	var reminders []string
+	var meet, guestsCanModify, allowConflicts, checkAttendees, dryRun bool
 
 	cmd := &cobra.Command{
-		Use:   "reschedule <event-id>",
-		Short: "Reschedule an event",
-		Long:  "Updates event time, notifies attendees.",
-		Args:  cobra.ExactArgs(1),
+		Use:   "schedule",
+		Short: "Create an event",
+		Long: `Creates event, sends invites if attendees specified.
+
+The new time is checked against your calendar (and with --check-attendees,
+the attendees' free/busy). Overlaps fail with CONFLICT, listing the
+conflicting events and the nearest free slots, unless --allow-conflicts.
+With --repeat only the first occurrence is checked.
+
+` + timeFlagHelp,
 		Run: func(cmd *cobra.Command, args []string) {
-			startTime, err := time.Parse(time.RFC3339, start)
+			ctx := context.Background()
+
+			// A dry run only needs read access, and only to look up the
+			// calendar's time zone.
+			var svc *calendar.Service
+			var err error
+			if !dryRun {
+				if svc, err = calendarWriteService(ctx); err != nil {
+					output.Failure(output.ErrScopeInsufficient, err.Error(), nil)
+					return
+				}
+			} else if needsTimeZone(start, end) {
+				if svc, err = calendarReadService(ctx); err != nil {
+					output.FailureFromError(output.ErrAuthRequired, err)
+					return
+				}
+			}
+
+			loc, err := calendarLocation(svc, calendarID, start, end)
+			if err != nil {
+				output.APIError(err)
+				return
+			}
+
+			now := time.Now()
+			startTime, err := parseTimeFlag("start", start, now, loc)
 			if err != nil {
-				startTime, err = time.Parse("2006-01-02T15:04:05", start)
+				output.InvalidInputError(err.Error())
+				return
+			}
+			endTime, err := parseEndFlag(end, duration, startTime, now, loc)
+			if err != nil {
+				output.InvalidInputError(err.Error())
+				return
+			}
+
+			var attendeeList []string
+			if attendees != "" {
+				attendeeList = strings.Split(attendees, ",")
+			}
+
+			var recurrence []string
+			if repeat != "" {
+				rule, err := calendar.ParseRepeat(repeat, startTime, false)
 				if err != nil {
-					output.InvalidInputError("Invalid start time format")
+					output.InvalidInputError(err.Error())
 					return
 				}
+				recurrence = []string{rule}
 			}
 
-			endTime, err := time.Parse(time.RFC3339, end)
-			if err != nil {
-				endTime, err = time.Parse("2006-01-02T15:04:05", end)
+			var optionalList []string
+			if optionalAttendees != "" {
+				optionalList = strings.Split(optionalAttendees, ",")
+			}
+
+			var reminderList []calendar.ReminderInfo
+			for _, spec := range reminders {
+				reminder, err := calendar.ParseReminder(spec)
 				if err != nil {
-					output.InvalidInputError("Invalid end time format")
+					output.InvalidInputError(err.Error())
 					return
 				}
+				reminderList = append(reminderList, reminder)
 			}
 
-			ctx := context.Background()
-			svc, err := calendarWriteService(ctx)
-			if err != nil {
-				output.Failure(output.ErrScopeInsufficient, err.Error(), nil)
+			var colorID string
+			if color != "" {
+				if colorID, err = calendar.ParseColor(color); err != nil {
+					output.InvalidInputError(err.Error())
+					return
+				}
+			}
+			if visibility, err = calendar.ParseVisibility(visibility); err != nil {
+				output.InvalidInputError(err.Error())
+				return
+			}
+			if transparency, err = calendar.ParseTransparency(transparency); err != nil {
+				output.InvalidInputError(err.Error())
+				return
+			}
+			if sendUpdates, err = calendar.ParseSendUpdates(sendUpdates); err != nil {
+				output.InvalidInputError(err.Error())
 				return
 			}
 
-			result, err := svc.Reschedule(calendar.RescheduleOptions{
-				CalendarID: calendarID,
-				EventID:    args[0],
-				Start:      startTime,
-				End:        endTime,
+			if dryRun {
+				output.SuccessNoScope(map[string]interface{}{
+					"dry_run":            true,
+					"title":              title,
+					"start":              startTime.Format(time.RFC3339),
+					"end":                endTime.Format(time.RFC3339),
+					"location":           location,
+					"description":        description,
+					"attendees":          attendeeList,
+					"optional_attendees": optionalList,
+					"recurrence":         recurrence,
+					"meet":               meet,
+					"reminders":          reminderList,
+					"color_id":           colorID,
+					"visibility":         visibility,
+					"transparency":       transparency,
+					"guests_can_modify":  guestsCanModify,
+					"send_updates":       sendUpdates,
+				})
+				return
+			}
+
+			result, err := svc.Create(calendar.CreateEventOptions{
+				CalendarID:        calendarID,
+				Title:             title,
+				Description:       description,
+				Location:          location,
+				Start:             startTime,
+				End:               endTime,
+				Attendees:         attendeeList,
+				Recurrence:        recurrence,
+				OptionalAttendees: optionalList,
+				Meet:              meet,
+				Reminders:         reminderList,
+				ColorID:           colorID,
+				Visibility:        visibility,
+				Transparency:      transparency,
+				GuestsCanModify:   guestsCanModify,
+				SendUpdates:       sendUpdates,
+				AllowConflicts:    allowConflicts,
+				CheckAttendees:    checkAttendees,
 			})
 			if err != nil {
-				output.APIError(err)
+				calendarWriteError(err)
 				return
 			}
 
@@ -437,26 +805,840 @@ func calendarRescheduleCmd() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVar(&start, "start", "", "New start datetime (required)")
-	cmd.Flags().StringVar(&end, "end", "", "New end datetime (required)")
+	cmd.Flags().StringVar(&title, "title", "", "Event title (required)")
+	cmd.Flags().StringVar(&start, "start", "", "Start datetime (required)")
+	cmd.Flags().StringVar(&end, "end", "", "End datetime")
+	cmd.Flags().StringVar(&duration, "duration", "", "Event length instead of --end, e.g. 30m or 1h30m")
 	cmd.Flags().StringVar(&calendarID, "calendar", "", "Calendar ID (default: primary)")
+	cmd.Flags().StringVar(&location, "location", "", "Event location")
+	cmd.Flags().StringVar(&description, "description", "", "Event description")
+	cmd.Flags().StringVar(&attendees, "attendees", "", "Attendee emails (comma-separated)")
+	cmd.Flags().StringVar(&optionalAttendees, "optional-attendees", "", "Optional attendee emails (comma-separated)")
+	cmd.Flags().BoolVar(&meet, "meet", false, "Add a Google Meet link")
+	cmd.Flags().StringArrayVar(&reminders, "reminder", nil, "Reminder, e.g. 10m:popup or 1h:email (repeatable)")
+	cmd.Flags().StringVar(&color, "color", "", "Event color: 1-11 or a name (tomato, sage, peacock, ...)")
+	cmd.Flags().StringVar(&visibility, "visibility", "", "Visibility: default, public, private, confidential")
+	cmd.Flags().StringVar(&transparency, "transparency", "", "Show as: busy or free")
+	cmd.Flags().BoolVar(&guestsCanModify, "guests-can-modify", false, "Let guests modify the event")
+	cmd.Flags().StringVar(&sendUpdates, "send-updates", "all", "Send invites: all, external, none")
+	cmd.Flags().BoolVar(&allowConflicts, "allow-conflicts", false, "Create even if the time overlaps busy time")
+	cmd.Flags().BoolVar(&checkAttendees, "check-attendees", false, "Also check attendees' free/busy for conflicts")
+	cmd.Flags().StringVar(&repeat, "repeat", "", "Repeat rule, e.g. \"weekly on mon,wed until 2026-12-31\" or an RRULE")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be created")
 
+	cmd.MarkFlagRequired("title")
 	cmd.MarkFlagRequired("start")
-	cmd.MarkFlagRequired("end")
+	cmd.MarkFlagsMutuallyExclusive("end", "duration")
+	cmd.MarkFlagsOneRequired("end", "duration")
 
 	return cmd
 }
 
-func calendarCancelCmd() *cobra.Command {
-	var calendarID string
-	var notify bool
+func calendarRescheduleCmd() *cobra.Command {
+	var start, end, duration, calendarID string
+	var this, following, all, allowConflicts, checkAttendees bool
 
 	cmd := &cobra.Command{
-		Use:   "cancel <event-id>",
+		Use:   "reschedule <event-id>",
+		Short: "Reschedule an event",
+		Long: `Updates event time, notifies attendees.
+
+For recurring events, --this moves only the given occurrence, --following
+moves it and all later occurrences (splitting the series, or moving the
+whole series from its first occurrence) and --all shifts the whole series by
+the same offset.
+
+The new time is checked for conflicts like calendar schedule; pass
+--allow-conflicts to move it anyway. With --all or --following only the
+moved occurrence is checked, not the rest of the series.
+
+` + timeFlagHelp,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.Background()
+			svc, err := calendarWriteService(ctx)
+			if err != nil {
+				output.Failure(output.ErrScopeInsufficient, err.Error(), nil)
+				return
+			}
+
+			loc, err := calendarLocation(svc, calendarID, start, end)
+			if err != nil {
+				output.APIError(err)
+				return
+			}
+
+			now := time.Now()
+			startTime, err := parseTimeFlag("start", start, now, loc)
+			if err != nil {
+				output.InvalidInputError(err.Error())
+				return
+			}
+			endTime, err := parseEndFlag(end, duration, startTime, now, loc)
+			if err != nil {
+				output.InvalidInputError(err.Error())
+				return
+			}
+
+			result, err := svc.Reschedule(calendar.RescheduleOptions{
+				CalendarID:     calendarID,
+				EventID:        args[0],
+				Start:          startTime,
+				End:            endTime,
+				Scope:          recurrenceScope(this, following, all),
+				AllowConflicts: allowConflicts,
+				CheckAttendees: checkAttendees,
+			})
+			if err != nil {
+				calendarWriteError(err)
+				return
+			}
+
+			output.Success(result, "write")
+		},
+	}
+
+	cmd.Flags().StringVar(&start, "start", "", "New start datetime (required)")
+	cmd.Flags().StringVar(&end, "end", "", "New end datetime")
+	cmd.Flags().StringVar(&duration, "duration", "", "New length instead of --end, e.g. 30m")
+	cmd.Flags().StringVar(&calendarID, "calendar", "", "Calendar ID (default: primary)")
+	cmd.Flags().BoolVar(&allowConflicts, "allow-conflicts", false, "Move even if the new time overlaps busy time")
+	cmd.Flags().BoolVar(&checkAttendees, "check-attendees", false, "Also check attendees' free/busy for conflicts")
+	addRecurrenceScopeFlags(cmd, &this, &following, &all)
+
+	cmd.MarkFlagRequired("start")
+	cmd.MarkFlagsMutuallyExclusive("end", "duration")
+	cmd.MarkFlagsOneRequired("end", "duration")
+
+	return cmd
+}
+
+func calendarCancelCmd() *cobra.Command {
+	var calendarID string
+	var notify, this, following, all bool
+
+	cmd := &cobra.Command{
+		Use:   "cancel <event-id>",
 		Short: "Cancel an event",
-		Long:  "Deletes event, optionally notifies attendees.",
+		Long: `Deletes event, optionally notifies attendees.
+
+For recurring events, --this cancels only the given occurrence, --following
+ends the series before it (deleting the whole series from its first
+occurrence) and --all deletes the whole series.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.Background()
+			svc, err := calendarWriteService(ctx)
+			if err != nil {
+				output.Failure(output.ErrScopeInsufficient, err.Error(), nil)
+				return
+			}
+
+			scope := recurrenceScope(this, following, all)
+			err = svc.Cancel(calendar.CancelOptions{
+				CalendarID: calendarID,
+				EventID:    args[0],
+				Notify:     notify,
+				Scope:      scope,
+			})
+			if err != nil {
+				output.APIError(err)
+				return
+			}
+
+			result := map[string]interface{}{
+				"event_id":  args[0],
+				"cancelled": true,
+				"notified":  notify,
+			}
+			if scope != "" {
+				result["scope"] = scope
+			}
+			output.Success(result, "write")
+		},
+	}
+
+	cmd.Flags().StringVar(&calendarID, "calendar", "", "Calendar ID (default: primary)")
+	cmd.Flags().BoolVar(&notify, "notify", true, "Notify attendees")
+	addRecurrenceScopeFlags(cmd, &this, &following, &all)
+
+	return cmd
+}
+
+// addRecurrenceScopeFlags adds the mutually exclusive --this, --following
+// and --all flags for changes to recurring events.
+func addRecurrenceScopeFlags(cmd *cobra.Command, this, following, all *bool) {
+	cmd.Flags().BoolVar(this, "this", false, "Recurring event: only this occurrence")
+	cmd.Flags().BoolVar(following, "following", false, "Recurring event: this and following occurrences")
+	cmd.Flags().BoolVar(all, "all", false, "Recurring event: all occurrences")
+	cmd.MarkFlagsMutuallyExclusive("this", "following", "all")
+}
+
+// recurrenceScope returns the scope selected by the recurrence scope flags.
+func recurrenceScope(this, following, all bool) string {
+	switch {
+	case this:
+		return calendar.ScopeThis
+	case following:
+		return calendar.ScopeFollowing
+	case all:
+		return calendar.ScopeAll
+	}
+	return ""
+}
+
+func calendarInstancesCmd() *cobra.Command {
+	var calendarID, from, to string
+	var limit int64
+
+	cmd := &cobra.Command{
+		Use:   "instances <event-id>",
+		Short: "List occurrences of a recurring event",
+		Long:  "Expands a recurring event into its occurrences. Occurrence IDs can be used with reschedule and cancel --this/--following.\n\n" + timeFlagHelp,
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.Background()
+			svc, err := calendarReadService(ctx)
+			if err != nil {
+				output.FailureFromError(output.ErrAuthRequired, err)
+				return
+			}
+
+			loc, err := calendarLocation(svc, calendarID, from, to)
+			if err != nil {
+				output.APIError(err)
+				return
+			}
+
+			now := time.Now()
+			fromTime := now
+			if from != "" {
+				if fromTime, err = parseTimeFlag("from", from, now, loc); err != nil {
+					output.InvalidInputError(err.Error())
+					return
+				}
+			}
+			var toTime time.Time
+			if to != "" {
+				if toTime, err = parseTimeFlag("to", to, now, loc); err != nil {
+					output.InvalidInputError(err.Error())
+					return
+				}
+			}
+
+			events, err := svc.Instances(calendarID, args[0], fromTime, toTime, limit)
+			if err != nil {
+				output.APIError(err)
+				return
+			}
+
+			result := map[string]interface{}{
+				"recurring_event_id": args[0],
+				"from":               fromTime.Format(time.RFC3339),
+				"events":             events,
+				"count":              len(events),
+			}
+			if !toTime.IsZero() {
+				result["to"] = toTime.Format(time.RFC3339)
+			}
+			output.Success(result, "read")
+		},
+	}
+
+	cmd.Flags().StringVar(&calendarID, "calendar", "", "Calendar ID (default: primary)")
+	cmd.Flags().StringVar(&from, "from", "", "Start time (default: now)")
+	cmd.Flags().StringVar(&to, "to", "", "End time")
+	cmd.Flags().Int64VarP(&limit, "limit", "n", 25, "Maximum occurrences")
+
+	return cmd
+}
+
+func calendarExportCmd() *cobra.Command {
+	var calendarID, from, to, format, out string
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export events as an iCalendar file",
+		Long: `Writes the events between --from and --to to an .ics file with
+VTIMEZONE definitions, recurrence rules (RRULE/EXDATE) and attendees.
+Recurring events are exported as series.
+
+` + timeFlagHelp,
+		Run: func(cmd *cobra.Command, args []string) {
+			if format != "ics" {
+				output.InvalidInputError("Invalid format. Use: ics")
+				return
+			}
+
+			ctx := context.Background()
+			svc, err := calendarReadService(ctx)
+			if err != nil {
+				output.FailureFromError(output.ErrAuthRequired, err)
+				return
+			}
+
+			loc, err := calendarLocation(svc, calendarID, from, to)
+			if err != nil {
+				output.APIError(err)
+				return
+			}
+
+			now := time.Now()
+			fromTime, err := parseTimeFlag("from", from, now, loc)
+			if err != nil {
+				output.InvalidInputError(err.Error())
+				return
+			}
+			toTime, err := parseTimeFlag("to", to, now, loc)
+			if err != nil {
+				output.InvalidInputError(err.Error())
+				return
+			}
+
+			data, count, err := svc.Export(calendarID, fromTime, toTime)
+			if err != nil {
+				output.APIError(err)
+				return
+			}
+
+			if err := os.WriteFile(out, data, 0644); err != nil {
+				output.Failure(output.ErrInternal, "Failed to write file: "+err.Error(), nil)
+				return
+			}
+
+			output.Success(map[string]interface{}{
+				"out":    out,
+				"format": format,
+				"from":   fromTime.Format(time.RFC3339),
+				"to":     toTime.Format(time.RFC3339),
+				"count":  count,
+				"bytes":  len(data),
+			}, "read")
+		},
+	}
+
+	cmd.Flags().StringVar(&calendarID, "calendar", "", "Calendar ID (default: primary)")
+	cmd.Flags().StringVar(&from, "from", "", "Start time (required)")
+	cmd.Flags().StringVar(&to, "to", "", "End time (required)")
+	cmd.Flags().StringVar(&format, "format", "ics", "Format: ics")
+	cmd.Flags().StringVar(&out, "out", "", "Output file (required)")
+
+	cmd.MarkFlagRequired("from")
+	cmd.MarkFlagRequired("to")
+	cmd.MarkFlagRequired("out")
+
+	return cmd
+}
+
+func calendarImportCmd() *cobra.Command {
+	var calendarID string
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "import <file.ics>",
+		Short: "Import events from an iCalendar file",
+		Long: `Creates or updates the events of an .ics file. Events are matched by
+their UID (iCalUID), so importing the same file again updates instead of
+duplicating. Times without a zone use the calendar's time zone; events in
+unknown time zones fail. Modified occurrences of recurring events are
+imported after their series as exceptions of it.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			data, err := os.ReadFile(args[0])
+			if err != nil {
+				output.InvalidInputError("Failed to read file: " + err.Error())
+				return
+			}
+
+			ctx := context.Background()
+			var svc *calendar.Service
+			if dryRun {
+				svc, err = calendarReadService(ctx)
+				if err != nil {
+					output.FailureFromError(output.ErrAuthRequired, err)
+					return
+				}
+			} else {
+				svc, err = calendarWriteService(ctx)
+				if err != nil {
+					output.Failure(output.ErrScopeInsufficient, err.Error(), nil)
+					return
+				}
+			}
+
+			loc, err := svc.Location(calendarID)
+			if err != nil {
+				output.APIError(err)
+				return
+			}
+
+			events, skipped, err := calendar.ParseICS(data, loc)
+			if err != nil {
+				output.InvalidInputError(err.Error())
+				return
+			}
+
+			if dryRun {
+				planned := make([]calendar.ImportResult, 0, len(events))
+				for _, e := range events {
+					planned = append(planned, calendar.ImportResult{
+						UID:     e.ICalUID,
+						Summary: e.Summary,
+						Start:   e.Start.DateTime + e.Start.Date,
+					})
+				}
+				output.SuccessNoScope(map[string]interface{}{
+					"dry_run": true,
+					"file":    args[0],
+					"events":  planned,
+					"skipped": skipped,
+					"count":   len(planned),
+				})
+				return
+			}
+
+			results := append(svc.Import(calendarID, events), skipped...)
+			counts := map[string]int{}
+			for _, r := range results {
+				counts[r.Status]++
+			}
+
+			output.Success(map[string]interface{}{
+				"file":    args[0],
+				"results": results,
+				"created": counts[calendar.ImportCreated],
+				"updated": counts[calendar.ImportUpdated],
+				"skipped": counts[calendar.ImportSkipped],
+				"failed":  counts[calendar.ImportFailed],
+				"count":   len(results),
+			}, "write")
+		},
+	}
+
+	cmd.Flags().StringVar(&calendarID, "calendar", "", "Calendar ID (default: primary)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be imported")
+
+	return cmd
+}
+
+func calendarChangesCmd() *cobra.Command {
+	var calendarID, syncToken, stateFile string
+	var fullSyncLimit int
+
+	cmd := &cobra.Command{
+		Use:   "changes",
+		Short: "List calendar changes since a sync token",
+		Long: `Returns events created, updated and cancelled since a sync token, plus the
+new sync token to use as the next cursor. Cancelled occurrences of
+recurring events include their recurring_event_id and original_start.
+
+With --state-file the cursor is read from and written back to a file, so
+repeated runs only return new changes. When no cursor is available or the
+sync token has expired, upcoming occurrences are listed instead in start
+order (full_sync: true), up to --full-sync-limit.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if calendarID == "" {
+				calendarID = "primary"
+			}
+
+			token := syncToken
+			var since time.Time
+			if token == "" && stateFile != "" {
+				state, err := calendar.LoadSyncState(stateFile)
+				if err != nil {
+					output.InvalidInputError(err.Error())
+					return
+				}
+				// A cursor for another calendar cannot be used.
+				if state.CalendarID == calendarID {
+					token = state.SyncToken
+					since, _ = time.Parse(time.RFC3339, state.UpdatedAt)
+				}
+			}
+
+			ctx := context.Background()
+			svc, err := calendarReadService(ctx)
+			if err != nil {
+				output.FailureFromError(output.ErrAuthRequired, err)
+				return
+			}
+
+			now := time.Now()
+			result, err := svc.Changes(calendarID, token, since, fullSyncLimit)
+			if err != nil {
+				output.APIError(err)
+				return
+			}
+
+			if stateFile != "" {
+				if err := calendar.SaveSyncState(stateFile, calendarID, result.SyncToken, now); err != nil {
+					output.FailureFromError(output.ErrInternal, err)
+					return
+				}
+			}
+
+			output.Success(result, "read")
+		},
+	}
+
+	cmd.Flags().StringVar(&calendarID, "calendar", "", "Calendar ID (default: primary)")
+	cmd.Flags().StringVar(&syncToken, "sync-token", "", "Sync token to list changes from")
+	cmd.Flags().StringVar(&stateFile, "state-file", "", "File to read and persist the sync token")
+	cmd.Flags().IntVar(&fullSyncLimit, "full-sync-limit", calendar.DefaultFullSyncLimit, "Maximum upcoming events listed on a full sync")
+
+	return cmd
+}
+
+func calendarCalendarsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "calendars",
+		Short: "Manage calendars",
+		Long:  "List, create, update and delete calendars, and subscribe to or unsubscribe from shared calendars.",
+	}
+
+	cmd.AddCommand(calendarCalendarsListCmd())
+	cmd.AddCommand(calendarCalendarsCreateCmd())
+	cmd.AddCommand(calendarCalendarsUpdateCmd())
+	cmd.AddCommand(calendarCalendarsDeleteCmd())
+	cmd.AddCommand(calendarCalendarsSubscribeCmd())
+	cmd.AddCommand(calendarCalendarsUnsubscribeCmd())
+
+	return cmd
+}
+
+func calendarCalendarsListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List calendars in your calendar list",
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.Background()
+			svc, err := calendarReadService(ctx)
+			if err != nil {
+				output.FailureFromError(output.ErrAuthRequired, err)
+				return
+			}
+
+			calendars, err := svc.Calendars()
+			if err != nil {
+				output.APIError(err)
+				return
+			}
+
+			output.Success(map[string]interface{}{
+				"calendars": calendars,
+				"count":     len(calendars),
+			}, "read")
+		},
+	}
+}
+
+func calendarCalendarsCreateCmd() *cobra.Command {
+	var title, description, location, timeZone string
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a secondary calendar",
+		Run: func(cmd *cobra.Command, args []string) {
+			if timeZone != "" {
+				if _, err := time.LoadLocation(timeZone); err != nil {
+					output.InvalidInputError("Invalid time zone: " + timeZone)
+					return
+				}
+			}
+
+			if dryRun {
+				output.SuccessNoScope(map[string]interface{}{
+					"dry_run":     true,
+					"title":       title,
+					"description": description,
+					"location":    location,
+					"time_zone":   timeZone,
+				})
+				return
+			}
+
+			ctx := context.Background()
+			svc, err := calendarWriteService(ctx)
+			if err != nil {
+				output.Failure(output.ErrScopeInsufficient, err.Error(), nil)
+				return
+			}
+
+			info, err := svc.CreateCalendar(calendar.CalendarOptions{
+				Summary:     title,
+				Description: description,
+				Location:    location,
+				TimeZone:    timeZone,
+			})
+			if err != nil {
+				output.APIError(err)
+				return
+			}
+
+			output.Success(info, "write")
+		},
+	}
+
+	cmd.Flags().StringVar(&title, "title", "", "Calendar title (required)")
+	cmd.Flags().StringVar(&description, "description", "", "Calendar description")
+	cmd.Flags().StringVar(&location, "location", "", "Calendar location")
+	cmd.Flags().StringVar(&timeZone, "tz", "", "IANA time zone (default: your primary calendar's)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be created")
+
+	cmd.MarkFlagRequired("title")
+
+	return cmd
+}
+
+func calendarCalendarsUpdateCmd() *cobra.Command {
+	var title, description, location, timeZone string
+
+	cmd := &cobra.Command{
+		Use:   "update <calendar-id>",
+		Short: "Update a calendar's title, description, location or time zone",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if timeZone != "" {
+				if _, err := time.LoadLocation(timeZone); err != nil {
+					output.InvalidInputError("Invalid time zone: " + timeZone)
+					return
+				}
+			}
+
+			ctx := context.Background()
+			svc, err := calendarWriteService(ctx)
+			if err != nil {
+				output.Failure(output.ErrScopeInsufficient, err.Error(), nil)
+				return
+			}
+
+			info, err := svc.UpdateCalendar(args[0], calendar.CalendarOptions{
+				Summary:     title,
+				Description: description,
+				Location:    location,
+				TimeZone:    timeZone,
+			})
+			if err != nil {
+				output.APIError(err)
+				return
+			}
+
+			output.Success(info, "write")
+		},
+	}
+
+	cmd.Flags().StringVar(&title, "title", "", "New title")
+	cmd.Flags().StringVar(&description, "description", "", "New description")
+	cmd.Flags().StringVar(&location, "location", "", "New location")
+	cmd.Flags().StringVar(&timeZone, "tz", "", "New IANA time zone")
+
+	cmd.MarkFlagsOneRequired("title", "description", "location", "tz")
+
+	return cmd
+}
+
+func calendarCalendarsDeleteCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete <calendar-id>",
+		Short: "Permanently delete a secondary calendar and its events",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.Background()
+			svc, err := calendarWriteService(ctx)
+			if err != nil {
+				output.Failure(output.ErrScopeInsufficient, err.Error(), nil)
+				return
+			}
+
+			if err := svc.DeleteCalendar(args[0]); err != nil {
+				output.APIError(err)
+				return
+			}
+
+			output.Success(map[string]interface{}{
+				"calendar_id": args[0],
+				"deleted":     true,
+			}, "write")
+		},
+	}
+}
+
+func calendarCalendarsSubscribeCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "subscribe <calendar-id>",
+		Short: "Add a shared or public calendar to your calendar list",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.Background()
+			svc, err := calendarWriteService(ctx)
+			if err != nil {
+				output.Failure(output.ErrScopeInsufficient, err.Error(), nil)
+				return
+			}
+
+			info, err := svc.Subscribe(args[0])
+			if err != nil {
+				output.APIError(err)
+				return
+			}
+
+			output.Success(info, "write")
+		},
+	}
+}
+
+func calendarCalendarsUnsubscribeCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "unsubscribe <calendar-id>",
+		Short: "Remove a calendar from your calendar list without deleting it",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.Background()
+			svc, err := calendarWriteService(ctx)
+			if err != nil {
+				output.Failure(output.ErrScopeInsufficient, err.Error(), nil)
+				return
+			}
+
+			if err := svc.Unsubscribe(args[0]); err != nil {
+				output.APIError(err)
+				return
+			}
+
+			output.Success(map[string]interface{}{
+				"calendar_id":  args[0],
+				"unsubscribed": true,
+			}, "write")
+		},
+	}
+}
+
+func calendarACLCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "acl",
+		Short: "Manage calendar sharing",
+		Long: `List, grant and revoke calendar sharing rules.
+
+Scopes are an email (user), a domain, user:/group:/domain: prefixed values
+or "default" (public). Roles are freeBusyReader, reader, writer and owner.`,
+	}
+
+	cmd.AddCommand(calendarACLListCmd())
+	cmd.AddCommand(calendarACLGrantCmd())
+	cmd.AddCommand(calendarACLRevokeCmd())
+
+	return cmd
+}
+
+func calendarACLListCmd() *cobra.Command {
+	var calendarID string
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List sharing rules",
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.Background()
+			svc, err := calendarWriteService(ctx)
+			if err != nil {
+				output.Failure(output.ErrScopeInsufficient, err.Error(), nil)
+				return
+			}
+
+			rules, err := svc.ACL(calendarID)
+			if err != nil {
+				output.APIError(err)
+				return
+			}
+
+			output.Success(map[string]interface{}{
+				"rules": rules,
+				"count": len(rules),
+			}, "write")
+		},
+	}
+
+	cmd.Flags().StringVar(&calendarID, "calendar", "", "Calendar ID (default: primary)")
+
+	return cmd
+}
+
+func calendarACLGrantCmd() *cobra.Command {
+	var calendarID, role string
+	var notify, dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "grant <scope>",
+		Short: "Share a calendar",
+		Long: `Shares a calendar with a user, group, domain or the public at a role,
+replacing any existing rule for the same scope.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			scopeType, scopeValue, err := calendar.ParseACLScope(args[0])
+			if err != nil {
+				output.InvalidInputError(err.Error())
+				return
+			}
+			aclRole, err := calendar.ParseACLRole(role)
+			if err != nil {
+				output.InvalidInputError(err.Error())
+				return
+			}
+
+			if dryRun {
+				output.SuccessNoScope(map[string]interface{}{
+					"dry_run":     true,
+					"calendar_id": calendarID,
+					"scope_type":  scopeType,
+					"scope_value": scopeValue,
+					"role":        aclRole,
+					"notify":      notify,
+				})
+				return
+			}
+
+			ctx := context.Background()
+			svc, err := calendarWriteService(ctx)
+			if err != nil {
+				output.Failure(output.ErrScopeInsufficient, err.Error(), nil)
+				return
+			}
+
+			rule, err := svc.Grant(calendarID, scopeType, scopeValue, aclRole, notify)
+			if err != nil {
+				output.APIError(err)
+				return
+			}
+
+			output.Success(rule, "write")
+		},
+	}
+
+	cmd.Flags().StringVar(&calendarID, "calendar", "", "Calendar ID (default: primary)")
+	cmd.Flags().StringVar(&role, "role", "", "Role: freeBusyReader, reader, writer, owner (required)")
+	cmd.Flags().BoolVar(&notify, "notify", true, "Email the grantee about the share")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the rule without creating it")
+
+	cmd.MarkFlagRequired("role")
+
+	return cmd
+}
+
+func calendarACLRevokeCmd() *cobra.Command {
+	var calendarID string
+
+	cmd := &cobra.Command{
+		Use:   "revoke <scope-or-rule-id>",
+		Short: "Remove a sharing rule",
 		Args:  cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
+			scopeType, scopeValue, err := calendar.ParseACLScope(args[0])
+			if err != nil {
+				output.InvalidInputError(err.Error())
+				return
+			}
+			ruleID := calendar.ACLRuleID(scopeType, scopeValue)
+
 			ctx := context.Background()
 			svc, err := calendarWriteService(ctx)
 			if err != nil {
@@ -464,21 +1646,19 @@ func calendarCancelCmd() *cobra.Command {
 				return
 			}
 
-			if err := svc.Cancel(calendarID, args[0], notify); err != nil {
+			if err := svc.Revoke(calendarID, ruleID); err != nil {
 				output.APIError(err)
 				return
 			}
 
 			output.Success(map[string]interface{}{
-				"event_id":  args[0],
-				"cancelled": true,
-				"notified":  notify,
+				"rule_id": ruleID,
+				"revoked": true,
 			}, "write")
 		},
 	}
 
 	cmd.Flags().StringVar(&calendarID, "calendar", "", "Calendar ID (default: primary)")
-	cmd.Flags().BoolVar(&notify, "notify", true, "Notify attendees")
 
 	return cmd
 }
@@ -519,6 +1699,388 @@ func calendarRespondCmd() *cobra.Command {
 	return cmd
 }
 
+func calendarBlockFocusCmd() *cobra.Command {
+	var start, end, duration, title, message string
+	var autoDecline, declineExisting, dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "block-focus",
+		Short: "Block focus time",
+		Long: `Creates a focus time event on your primary calendar. Focus time shows as
+busy and, with --auto-decline, declines new invitations that overlap it
+(--decline-existing also declines meetings already on your calendar).
+
+` + timeFlagHelp,
+		Run: func(cmd *cobra.Command, args []string) {
+			createStatusEvent(calendar.EventTypeFocusTime, start, end, duration, calendar.StatusEventOptions{
+				Title:          title,
+				DeclineMode:    declineMode(autoDecline, declineExisting),
+				DeclineMessage: message,
+			}, dryRun, "start", "end")
+		},
+	}
+
+	cmd.Flags().StringVar(&start, "start", "", "Start datetime (required)")
+	cmd.Flags().StringVar(&end, "end", "", "End datetime")
+	cmd.Flags().StringVar(&duration, "duration", "", "Length instead of --end, e.g. 2h")
+	cmd.Flags().StringVar(&title, "title", "", "Event title (default: Focus time)")
+	cmd.Flags().BoolVar(&autoDecline, "auto-decline", false, "Decline new invitations that overlap")
+	cmd.Flags().BoolVar(&declineExisting, "decline-existing", false, "Also decline overlapping meetings already on the calendar")
+	cmd.Flags().StringVar(&message, "message", "", "Message sent with automatic declines")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be created")
+
+	cmd.MarkFlagRequired("start")
+	cmd.MarkFlagsMutuallyExclusive("end", "duration")
+	cmd.MarkFlagsOneRequired("end", "duration")
+
+	return cmd
+}
+
+func calendarOOOCmd() *cobra.Command {
+	var from, to, title, message string
+	var autoDecline, declineExisting, dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "ooo",
+		Short: "Mark yourself out of office",
+		Long: `Creates an out-of-office event on your primary calendar from --from until
+--to. New invitations that overlap are declined with --message unless
+--auto-decline=false; --decline-existing also declines meetings already on
+your calendar.
+
+A date without a time is midnight at its start, so --to is the day you are
+back: --from 2026-10-26 --to 2026-10-31 covers Monday to Friday.
+
+` + timeFlagHelp,
+		Run: func(cmd *cobra.Command, args []string) {
+			createStatusEvent(calendar.EventTypeOutOfOffice, from, to, "", calendar.StatusEventOptions{
+				Title:          title,
+				DeclineMode:    declineMode(autoDecline, declineExisting),
+				DeclineMessage: message,
+			}, dryRun, "from", "to")
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "Start of the absence (required)")
+	cmd.Flags().StringVar(&to, "to", "", "End of the absence (required)")
+	cmd.Flags().StringVar(&title, "title", "", "Event title (default: Out of office)")
+	cmd.Flags().StringVar(&message, "message", "", "Message sent with automatic declines")
+	cmd.Flags().BoolVar(&autoDecline, "auto-decline", true, "Decline new invitations that overlap")
+	cmd.Flags().BoolVar(&declineExisting, "decline-existing", false, "Also decline overlapping meetings already on the calendar")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be created")
+
+	cmd.MarkFlagRequired("from")
+	cmd.MarkFlagRequired("to")
+
+	return cmd
+}
+
+// declineMode returns the auto-decline mode for the --auto-decline and
+// --decline-existing flags.
+func declineMode(autoDecline, declineExisting bool) string {
+	switch {
+	case declineExisting:
+		return calendar.DeclineExisting
+	case autoDecline:
+		return calendar.DeclineNew
+	}
+	return calendar.DeclineNone
+}
+
+// createStatusEvent resolves the times of a focus time or out-of-office
+// event on the primary calendar and creates it, or shows it on a dry run.
+// startFlag and endFlag name the time flags in error messages.
+func createStatusEvent(eventType, start, end, duration string, opts calendar.StatusEventOptions, dryRun bool, startFlag, endFlag string) {
+	ctx := context.Background()
+
+	var svc *calendar.Service
+	var err error
+	if !dryRun {
+		if svc, err = calendarWriteService(ctx); err != nil {
+			output.Failure(output.ErrScopeInsufficient, err.Error(), nil)
+			return
+		}
+	} else if needsTimeZone(start, end) {
+		if svc, err = calendarReadService(ctx); err != nil {
+			output.FailureFromError(output.ErrAuthRequired, err)
+			return
+		}
+	}
+
+	loc, err := calendarLocation(svc, "primary", start, end)
+	if err != nil {
+		output.APIError(err)
+		return
+	}
+
+	now := time.Now()
+	if opts.Start, err = parseTimeFlag(startFlag, start, now, loc); err != nil {
+		output.InvalidInputError(err.Error())
+		return
+	}
+	if duration != "" {
+		opts.End, err = parseEndFlag("", duration, opts.Start, now, loc)
+	} else {
+		opts.End, err = parseTimeFlag(endFlag, end, now, loc)
+	}
+	if err != nil {
+		output.InvalidInputError(err.Error())
+		return
+	}
+
+	event, err := calendar.BuildStatusEvent(eventType, opts)
+	if err != nil {
+		output.InvalidInputError(err.Error())
+		return
+	}
+
+	if dryRun {
+		output.SuccessNoScope(map[string]interface{}{
+			"dry_run":         true,
+			"event_type":      eventType,
+			"title":           event.Summary,
+			"start":           opts.Start.Format(time.RFC3339),
+			"end":             opts.End.Format(time.RFC3339),
+			"auto_decline":    opts.DeclineMode,
+			"decline_message": opts.DeclineMessage,
+		})
+		return
+	}
+
+	var result *calendar.CreateEventResult
+	if eventType == calendar.EventTypeFocusTime {
+		result, err = svc.BlockFocus(opts)
+	} else {
+		result, err = svc.OutOfOffice(opts)
+	}
+	if err != nil {
+		output.APIError(err)
+		return
+	}
+
+	output.Success(result, "write")
+}
+
+func calendarAttendeesCmd() *cobra.Command {
+	var calendarID, sendUpdates string
+	var optional, dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "attendees <event-id> [list|add|remove] [email...]",
+		Short: "Manage the guests of an event",
+		Long: `Lists an event's guests with a summary of their responses, or invites and
+uninvites guests. An occurrence ID changes only that occurrence of a
+recurring event; the series ID changes all of them.
+
+  list    group guests into accepted, declined, tentative and needs_action
+          (default). Rooms are listed with resource set but not counted.
+  add     invite the given emails (--optional for optional guests)
+  remove  uninvite the given emails`,
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			eventID, action := args[0], "list"
+			if len(args) > 1 {
+				action = args[1]
+			}
+
+			var emails []string
+			for _, arg := range args[min(len(args), 2):] {
+				emails = append(emails, strings.Split(arg, ",")...)
+			}
+
+			switch action {
+			case "list":
+				if len(emails) > 0 || optional || dryRun {
+					output.InvalidInputError("list takes no emails, --optional or --dry-run")
+					return
+				}
+			case "add", "remove":
+				if len(emails) == 0 {
+					output.InvalidInputError(fmt.Sprintf("%s requires at least one email", action))
+					return
+				}
+				if optional && action == "remove" {
+					output.InvalidInputError("--optional only applies to add")
+					return
+				}
+			default:
+				output.InvalidInputError(fmt.Sprintf("Unknown action %q (use: list, add, remove)", action))
+				return
+			}
+
+			ctx := context.Background()
+			if action == "list" {
+				svc, err := calendarReadService(ctx)
+				if err != nil {
+					output.FailureFromError(output.ErrAuthRequired, err)
+					return
+				}
+
+				list, err := svc.Attendees(calendarID, eventID)
+				if err != nil {
+					output.APIError(err)
+					return
+				}
+
+				output.Success(list, "read")
+				return
+			}
+
+			updates, err := calendar.ParseSendUpdates(sendUpdates)
+			if err != nil {
+				output.InvalidInputError(err.Error())
+				return
+			}
+
+			var svc *calendar.Service
+			if dryRun {
+				if svc, err = calendarReadService(ctx); err != nil {
+					output.FailureFromError(output.ErrAuthRequired, err)
+					return
+				}
+			} else if svc, err = calendarWriteService(ctx); err != nil {
+				output.Failure(output.ErrScopeInsufficient, err.Error(), nil)
+				return
+			}
+
+			opts := calendar.UpdateAttendeesOptions{
+				CalendarID:  calendarID,
+				EventID:     eventID,
+				Optional:    optional,
+				SendUpdates: updates,
+				DryRun:      dryRun,
+			}
+			if action == "add" {
+				opts.Add = emails
+			} else {
+				opts.Remove = emails
+			}
+
+			update, err := svc.UpdateAttendees(opts)
+			if err != nil {
+				output.APIError(err)
+				return
+			}
+
+			if dryRun {
+				output.SuccessNoScope(map[string]interface{}{
+					"dry_run":   true,
+					"added":     update.Added,
+					"removed":   update.Removed,
+					"unchanged": update.Unchanged,
+					"event":     update.List,
+				})
+				return
+			}
+
+			output.Success(update, "write")
+		},
+	}
+
+	cmd.Flags().StringVar(&calendarID, "calendar", "", "Calendar ID (default: primary)")
+	cmd.Flags().StringVar(&sendUpdates, "send-updates", "all", "add/remove: notify guests: all, external, none")
+	cmd.Flags().BoolVar(&optional, "optional", false, "add: invite as optional guests")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "add/remove: show the change without updating the event")
+
+	return cmd
+}
+
+func calendarNudgeCmd() *cobra.Command {
+	var calendarID, message string
+	var tentative, force, dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "nudge <event-id>",
+		Short: "Email guests who have not responded",
+		Long: `Sends each guest who has not responded to an event a short reminder
+email from your Gmail account, with a link to the event and an optional
+personal --message. You, the organizer and rooms are never emailed; add
+--include-tentative to also nudge guests who answered maybe.
+
+Only the organizer nudges guests: for events organized by someone else the
+command fails unless --force is given. Use --dry-run to preview the recipients and the message.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.Background()
+			calSvc, err := calendarReadService(ctx)
+			if err != nil {
+				output.FailureFromError(output.ErrAuthRequired, err)
+				return
+			}
+
+			list, err := calSvc.Attendees(calendarID, args[0])
+			if err != nil {
+				output.APIError(err)
+				return
+			}
+
+			if !list.OrganizerSelf && !force && !dryRun {
+				output.InvalidInputError(fmt.Sprintf("You are not the organizer of this event (organizer: %s); use --force to nudge its guests anyway", list.Organizer))
+				return
+			}
+
+			recipients := list.NonResponders(tentative)
+			subject, body := list.NudgeMessage(message)
+
+			if dryRun || len(recipients) == 0 {
+				result := map[string]interface{}{
+					"event_id":       list.EventID,
+					"organizer_self": list.OrganizerSelf,
+					"recipients":     recipients,
+					"subject":        subject,
+					"body":           body,
+					"count":          len(recipients),
+				}
+				if dryRun {
+					result["dry_run"] = true
+				}
+				output.SuccessNoScope(result)
+				return
+			}
+
+			gmailSvc, err := gmailWriteService(ctx)
+			if err != nil {
+				output.Failure(output.ErrScopeInsufficient, err.Error(), nil)
+				return
+			}
+
+			type nudgeResult struct {
+				Email     string `json:"email"`
+				MessageID string `json:"message_id,omitempty"`
+				Error     string `json:"error,omitempty"`
+			}
+			results := make([]nudgeResult, 0, len(recipients))
+			sent := 0
+			for _, email := range recipients {
+				res, err := gmailSvc.Send(gmail.SendOptions{To: []string{email}, Subject: subject, Body: body})
+				if err != nil {
+					results = append(results, nudgeResult{Email: email, Error: err.Error()})
+					continue
+				}
+				results = append(results, nudgeResult{Email: email, MessageID: res.MessageID})
+				sent++
+			}
+
+			output.Success(map[string]interface{}{
+				"event_id": list.EventID,
+				"subject":  subject,
+				"results":  results,
+				"sent":     sent,
+				"failed":   len(recipients) - sent,
+				"count":    len(recipients),
+			}, "write")
+		},
+	}
+
+	cmd.Flags().StringVar(&calendarID, "calendar", "", "Calendar ID (default: primary)")
+	cmd.Flags().StringVar(&message, "message", "", "Personal note added to the reminder")
+	cmd.Flags().BoolVar(&tentative, "include-tentative", false, "Also nudge guests who answered maybe")
+	cmd.Flags().BoolVar(&force, "force", false, "Nudge guests even if you are not the organizer")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show recipients and message without sending")
+
+	return cmd
+}
+
 // Calendar API commands
 func calendarAPICmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -1,7 +1,11 @@
 package main
 
 import (
+	"strings"
+	"time"
+
 	"github.com/spf13/cobra"
+	"github.com/ulfhaga/gagent-cli/internal/calendar"
 	"github.com/ulfhaga/gagent-cli/internal/config"
 	"github.com/ulfhaga/gagent-cli/internal/output"
 )
@@ -14,12 +18,23 @@ func configSetCmd() *cobra.Command {
 
 Available keys:
   default_calendar  - Default calendar ID (default: "primary")
-  audit_log         - Enable audit logging (true/false)`,
+  audit_log         - Enable audit logging (true/false)
+  working_hours     - Your working hours for calendar find-time and stats, e.g.
+                      "Europe/Berlin 09:00-17:00 mon-fri lunch 12:00-13:00"
+  working_hours.<email>
+                    - An attendee's working hours (empty value removes)`,
 		Args: cobra.ExactArgs(2),
 		Run: func(cmd *cobra.Command, args []string) {
 			key := args[0]
 			value := args[1]
 
+			if strings.HasPrefix(key, config.WorkingHoursKey) && value != "" {
+				if _, err := calendar.ParseWorkingHours(value, time.UTC); err != nil {
+					output.InvalidInputError(err.Error())
+					return
+				}
+			}
+
 			if err := config.Set(key, value); err != nil {
 				output.InvalidInputError(err.Error())
 				return
@@ -45,7 +60,10 @@ Available keys:
   client_secret     - OAuth client secret
   default_calendar  - Default calendar ID
   output_format     - Output format
-  audit_log         - Audit logging enabled`,
+  audit_log         - Audit logging enabled
+  working_hours     - Your working hours
+  working_hours.<email>
+                    - An attendee's working hours`,
 		Args: cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			key := args[0]
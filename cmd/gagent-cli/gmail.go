@@ -3,14 +3,18 @@ package main
 import (
 	"context"
 	"encoding/base64"
+	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/ulfhaga/gagent-cli/internal/auth"
+	"github.com/ulfhaga/gagent-cli/internal/calendar"
 	"github.com/ulfhaga/gagent-cli/internal/config"
 	"github.com/ulfhaga/gagent-cli/internal/gmail"
 	"github.com/ulfhaga/gagent-cli/internal/output"
+	"github.com/ulfhaga/gagent-cli/internal/sheets"
 )
 
 // gmailReadService creates a Gmail service with read scope.
@@ -77,15 +81,16 @@ func gmailInboxCmd() *cobra.Command {
 				return
 			}
 
-			messages, err := svc.Inbox(limit, unreadOnly)
+			result, err := svc.Inbox(limit, unreadOnly)
 			if err != nil {
 				output.APIError(err)
 				return
 			}
 
 			output.Success(map[string]interface{}{
-				"messages": messages,
-				"count":    len(messages),
+				"messages": result.Messages,
+				"count":    len(result.Messages),
+				"failed":   result.Failed,
 			}, "read")
 		},
 	}
@@ -100,7 +105,7 @@ func gmailReadCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "read <message-id>",
 		Short: "Read a message",
-		Long:  "Returns full message: headers, body (plain + html), attachments list.",
+		Long:  "Returns full message: headers, body (plain + html), attachments list, and the parsed calendar invitation if any.",
 		Args:  cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			ctx := context.Background()
@@ -110,7 +115,7 @@ func gmailReadCmd() *cobra.Command {
 				return
 			}
 
-			msg, err := svc.Get(args[0])
+			msg, err := svc.Read(args[0])
 			if err != nil {
 				output.NotFoundError("Message", args[0])
 				return
@@ -121,15 +126,49 @@ func gmailReadCmd() *cobra.Command {
 	}
 }
 
+// addQueryFlags registers the structured search flags that compile to a
+// Gmail query string. The --label flag is registered separately because
+// 'gmail api list' already uses it for label IDs.
+func addQueryFlags(cmd *cobra.Command, opts *gmail.QueryOptions) {
+	cmd.Flags().StringSliceVar(&opts.From, "from", nil, "Sender address (repeatable, any match)")
+	cmd.Flags().StringSliceVar(&opts.To, "to", nil, "Recipient address (repeatable, any match)")
+	cmd.Flags().StringVar(&opts.Subject, "subject", "", "Subject contains")
+	cmd.Flags().StringVar(&opts.After, "after", "", "Received after (RFC3339, YYYY-MM-DD or relative like 7d)")
+	cmd.Flags().StringVar(&opts.Before, "before", "", "Received before (RFC3339, YYYY-MM-DD or relative like 7d)")
+	cmd.Flags().BoolVar(&opts.HasAttachment, "has-attachment", false, "Only messages with attachments")
+	cmd.Flags().StringVar(&opts.Larger, "larger", "", "Larger than size (e.g. 5MB)")
+	cmd.Flags().StringSliceVar(&opts.Is, "is", nil, "State: unread, read, starred, important (repeatable)")
+	cmd.Flags().StringVar(&opts.In, "in", "", "Location: inbox, sent, trash, spam, anywhere, ...")
+}
+
 func gmailSearchCmd() *cobra.Command {
 	var limit int64
+	var queryOpts gmail.QueryOptions
 
 	cmd := &cobra.Command{
-		Use:   "search <query>",
+		Use:   "search [query]",
 		Short: "Search messages",
-		Long:  "Search using Gmail query syntax, returns matching messages.",
-		Args:  cobra.ExactArgs(1),
+		Long: `Search using Gmail query syntax and/or structured flags, returns matching messages.
+
+Structured flags (--from, --subject, --after, ...) are compiled into a
+correctly quoted Gmail query, combined with the optional raw query. The
+compiled query is echoed in the response.`,
+		Args: cobra.MaximumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) == 1 {
+				queryOpts.Raw = args[0]
+			}
+			if queryOpts.IsEmpty() {
+				output.InvalidInputError("Provide a query or at least one search flag")
+				return
+			}
+
+			query, err := gmail.BuildQuery(queryOpts, time.Now())
+			if err != nil {
+				output.InvalidInputError(err.Error())
+				return
+			}
+
 			ctx := context.Background()
 			svc, err := gmailReadService(ctx)
 			if err != nil {
@@ -137,21 +176,24 @@ func gmailSearchCmd() *cobra.Command {
 				return
 			}
 
-			messages, err := svc.Search(args[0], limit)
+			result, err := svc.Search(query, limit)
 			if err != nil {
 				output.APIError(err)
 				return
 			}
 
 			output.Success(map[string]interface{}{
-				"query":    args[0],
-				"messages": messages,
-				"count":    len(messages),
+				"query":    query,
+				"messages": result.Messages,
+				"count":    len(result.Messages),
+				"failed":   result.Failed,
 			}, "read")
 		},
 	}
 
 	cmd.Flags().Int64VarP(&limit, "limit", "n", 10, "Maximum number of messages to return")
+	addQueryFlags(cmd, &queryOpts)
+	cmd.Flags().StringSliceVar(&queryOpts.Labels, "label", nil, "Label name (repeatable, all must match)")
 
 	return cmd
 }
@@ -254,13 +296,19 @@ use 'gmail reply' instead, which sets the proper In-Reply-To headers.`,
 func gmailReplyCmd() *cobra.Command {
 	var body string
 	var replyAll bool
+	var from string
+	var noSignature bool
 	var dryRun bool
 
 	cmd := &cobra.Command{
 		Use:   "reply <message-id>",
 		Short: "Reply to a message",
-		Long:  "Fetches original, sets In-Reply-To/References headers, sends reply.",
-		Args:  cobra.ExactArgs(1),
+		Long: `Fetches original, sets In-Reply-To/References headers, sends reply.
+
+The mailbox's own addresses (primary and send-as aliases) are never added as
+recipients. Replies are sent from the alias the original was addressed to,
+or from --from, in which case that alias's signature is appended.`,
+		Args: cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			ctx := context.Background()
 
@@ -278,12 +326,28 @@ func gmailReplyCmd() *cobra.Command {
 					return
 				}
 
+				draft, err := svc.PrepareReply(gmail.ReplyOptions{
+					MessageID:   args[0],
+					Body:        body,
+					ReplyAll:    replyAll,
+					From:        from,
+					NoSignature: noSignature,
+				})
+				if err != nil {
+					output.APIError(err)
+					return
+				}
+
 				output.SuccessNoScope(map[string]interface{}{
 					"dry_run":          true,
 					"original_from":    original.From,
 					"original_subject": original.Subject,
 					"reply_all":        replyAll,
-					"body":             body,
+					"to":               draft.To,
+					"cc":               draft.Cc,
+					"from":             draft.From,
+					"subject":          draft.Subject,
+					"body":             draft.Body,
 				})
 				return
 			}
@@ -295,9 +359,11 @@ func gmailReplyCmd() *cobra.Command {
 			}
 
 			result, err := svc.Reply(gmail.ReplyOptions{
-				MessageID: args[0],
-				Body:      body,
-				ReplyAll:  replyAll,
+				MessageID:   args[0],
+				Body:        body,
+				ReplyAll:    replyAll,
+				From:        from,
+				NoSignature: noSignature,
 			})
 			if err != nil {
 				output.APIError(err)
@@ -310,6 +376,8 @@ func gmailReplyCmd() *cobra.Command {
 
 	cmd.Flags().StringVar(&body, "body", "", "Reply body (required)")
 	cmd.Flags().BoolVar(&replyAll, "reply-all", false, "Reply to all recipients")
+	cmd.Flags().StringVar(&from, "from", "", "Send-as alias to reply from (appends its signature)")
+	cmd.Flags().BoolVar(&noSignature, "no-signature", false, "Do not append the --from alias signature")
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be sent without actually sending")
 
 	cmd.MarkFlagRequired("body")
@@ -441,39 +509,1385 @@ use 'gmail reply' instead, which sets the proper In-Reply-To headers.`,
 	return cmd
 }
 
-// Gmail API commands
-func gmailAPICmd() *cobra.Command {
+func gmailBulkCmd() *cobra.Command {
+	var query string
+	var addLabels, removeLabels []string
+	var archive, markRead, trash bool
+	var max int
+	var dryRun bool
+
 	cmd := &cobra.Command{
-		Use:   "api",
-		Short: "Low-level Gmail API commands",
-		Long:  "Direct access to Gmail API operations.",
+		Use:   "bulk",
+		Short: "Apply an action to all messages matching a query",
+		Long: `Archives, marks read, labels or trashes every message matching a Gmail query.
+
+Messages are modified with BatchModify in chunks of 1000. Use --dry-run to
+preview how many messages match before changing anything.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			add := append([]string{}, addLabels...)
+			remove := append([]string{}, removeLabels...)
+			if archive {
+				remove = append(remove, "INBOX")
+			}
+			if markRead {
+				remove = append(remove, "UNREAD")
+			}
+			if trash {
+				add = append(add, "TRASH")
+			}
+
+			if len(add) == 0 && len(remove) == 0 {
+				output.InvalidInputError("At least one action is required (--archive, --mark-read, --add-label, --remove-label, --trash)")
+				return
+			}
+
+			ctx := context.Background()
+			var svc *gmail.Service
+			var err error
+			scope := "write"
+			if dryRun {
+				scope = "read"
+				svc, err = gmailReadService(ctx)
+				if err != nil {
+					output.FailureFromError(output.ErrAuthRequired, err)
+					return
+				}
+			} else {
+				svc, err = gmailWriteService(ctx)
+				if err != nil {
+					output.Failure(output.ErrScopeInsufficient, err.Error(), nil)
+					return
+				}
+			}
+
+			result, err := svc.Bulk(gmail.BulkOptions{
+				Query:        query,
+				AddLabels:    add,
+				RemoveLabels: remove,
+				Max:          max,
+				DryRun:       dryRun,
+			})
+			if err != nil {
+				if result != nil {
+					output.Failure(output.ErrAPIError, err.Error(), result)
+					return
+				}
+				output.APIError(err)
+				return
+			}
+
+			output.Success(result, scope)
+		},
 	}
 
-	cmd.AddCommand(gmailAPIListCmd())
-	cmd.AddCommand(gmailAPIGetCmd())
-	cmd.AddCommand(gmailAPILabelsCmd())
-	cmd.AddCommand(gmailAPIAttachmentCmd())
-	cmd.AddCommand(gmailAPIModifyCmd())
-	cmd.AddCommand(gmailAPITrashCmd())
-	cmd.AddCommand(gmailAPIUntrashCmd())
-	cmd.AddCommand(gmailAPIDeleteCmd())
-	cmd.AddCommand(gmailAPISendRawCmd())
-	cmd.AddCommand(gmailAPIDraftCreateCmd())
-	cmd.AddCommand(gmailAPIDraftSendCmd())
-	cmd.AddCommand(gmailAPIDraftUpdateCmd())
-	cmd.AddCommand(gmailAPIDraftDeleteCmd())
+	cmd.Flags().StringVar(&query, "query", "", "Gmail search query (required)")
+	cmd.Flags().BoolVar(&archive, "archive", false, "Remove matching messages from the inbox")
+	cmd.Flags().BoolVar(&markRead, "mark-read", false, "Mark matching messages as read")
+	cmd.Flags().StringSliceVar(&addLabels, "add-label", nil, "Label name or ID to add (repeatable)")
+	cmd.Flags().StringSliceVar(&removeLabels, "remove-label", nil, "Label name or ID to remove (repeatable)")
+	cmd.Flags().BoolVar(&trash, "trash", false, "Move matching messages to trash")
+	cmd.Flags().IntVar(&max, "max", 0, "Maximum number of messages to modify (0 = no limit)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Only count matching messages")
+
+	cmd.MarkFlagRequired("query")
 
 	return cmd
 }
 
-func gmailAPIListCmd() *cobra.Command {
-	var label, query, pageToken string
-	var limit int64
-
+func gmailFiltersCmd() *cobra.Command {
 	cmd := &cobra.Command{
+		Use:   "filters",
+		Short: "Manage Gmail filters",
+		Long:  "List, create, delete, export and import Gmail filters (mailFilters.xml format).",
+	}
+
+	cmd.AddCommand(gmailFiltersListCmd())
+	cmd.AddCommand(gmailFiltersCreateCmd())
+	cmd.AddCommand(gmailFiltersDeleteCmd())
+	cmd.AddCommand(gmailFiltersExportCmd())
+	cmd.AddCommand(gmailFiltersImportCmd())
+
+	return cmd
+}
+
+func gmailFiltersListCmd() *cobra.Command {
+	return &cobra.Command{
 		Use:   "list",
-		Short: "List messages",
+		Short: "List filters",
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.Background()
+			svc, err := gmailReadService(ctx)
+			if err != nil {
+				output.FailureFromError(output.ErrAuthRequired, err)
+				return
+			}
+
+			filters, err := svc.Filters()
+			if err != nil {
+				output.APIError(err)
+				return
+			}
+
+			output.Success(map[string]interface{}{
+				"filters": filters,
+				"count":   len(filters),
+			}, "read")
+		},
+	}
+}
+
+func gmailFiltersCreateCmd() *cobra.Command {
+	var filter gmail.FilterInfo
+	var sizeGT, sizeLT string
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a filter",
+		Long: `Creates a filter from structured criteria and actions.
+
+Labels given with --add-label are referred to by name and created if missing.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if sizeGT != "" {
+				size, err := gmail.ParseSize(sizeGT)
+				if err != nil {
+					output.InvalidInputError(err.Error())
+					return
+				}
+				filter.Criteria.SizeGreaterThan = size
+			}
+			if sizeLT != "" {
+				size, err := gmail.ParseSize(sizeLT)
+				if err != nil {
+					output.InvalidInputError(err.Error())
+					return
+				}
+				filter.Criteria.SizeLessThan = size
+			}
+
+			if filter.Criteria == (gmail.FilterCriteria{}) {
+				output.InvalidInputError("At least one criterion is required (--from, --to, --subject, --has-words, --size-gt, ...)")
+				return
+			}
+			if filter.Action.Forward == "" && len(filter.Action.AddLabels) == 0 &&
+				!filter.Action.Archive && !filter.Action.MarkRead && !filter.Action.Star &&
+				!filter.Action.Trash && !filter.Action.NeverSpam && !filter.Action.Important &&
+				!filter.Action.NeverImportant {
+				output.InvalidInputError("At least one action is required (--add-label, --archive, --mark-read, --forward, ...)")
+				return
+			}
+
+			if dryRun {
+				output.SuccessNoScope(map[string]interface{}{
+					"dry_run": true,
+					"filter":  filter,
+				})
+				return
+			}
+
+			ctx := context.Background()
+			svc, err := gmailWriteService(ctx)
+			if err != nil {
+				output.Failure(output.ErrScopeInsufficient, err.Error(), nil)
+				return
+			}
+
+			result, err := svc.CreateFilter(filter)
+			if err != nil {
+				output.APIError(err)
+				return
+			}
+
+			output.Success(result, "write")
+		},
+	}
+
+	cmd.Flags().StringVar(&filter.Criteria.From, "from", "", "Match sender")
+	cmd.Flags().StringVar(&filter.Criteria.To, "to", "", "Match recipient")
+	cmd.Flags().StringVar(&filter.Criteria.Subject, "subject", "", "Match subject")
+	cmd.Flags().StringVar(&filter.Criteria.HasWords, "has-words", "", "Match Gmail query")
+	cmd.Flags().StringVar(&filter.Criteria.DoesNotHaveWords, "does-not-have", "", "Exclude Gmail query")
+	cmd.Flags().BoolVar(&filter.Criteria.HasAttachment, "has-attachment", false, "Match messages with attachments")
+	cmd.Flags().StringVar(&sizeGT, "size-gt", "", "Match messages larger than size (e.g. 5MB)")
+	cmd.Flags().StringVar(&sizeLT, "size-lt", "", "Match messages smaller than size (e.g. 100KB)")
+	cmd.Flags().StringSliceVar(&filter.Action.AddLabels, "add-label", nil, "Label name to apply (repeatable)")
+	cmd.Flags().BoolVar(&filter.Action.Archive, "archive", false, "Skip the inbox")
+	cmd.Flags().BoolVar(&filter.Action.MarkRead, "mark-read", false, "Mark as read")
+	cmd.Flags().BoolVar(&filter.Action.Star, "star", false, "Star the message")
+	cmd.Flags().BoolVar(&filter.Action.Trash, "trash", false, "Delete the message")
+	cmd.Flags().BoolVar(&filter.Action.NeverSpam, "never-spam", false, "Never send to spam")
+	cmd.Flags().BoolVar(&filter.Action.Important, "important", false, "Always mark as important")
+	cmd.Flags().BoolVar(&filter.Action.NeverImportant, "never-important", false, "Never mark as important")
+	cmd.MarkFlagsMutuallyExclusive("important", "never-important")
+	cmd.Flags().StringVar(&filter.Action.Forward, "forward", "", "Forward to a verified address")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the filter without creating it")
+
+	return cmd
+}
+
+func gmailFiltersDeleteCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete <filter-id>",
+		Short: "Delete a filter",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.Background()
+			svc, err := gmailWriteService(ctx)
+			if err != nil {
+				output.Failure(output.ErrScopeInsufficient, err.Error(), nil)
+				return
+			}
+
+			if err := svc.DeleteFilter(args[0]); err != nil {
+				output.APIError(err)
+				return
+			}
+
+			output.Success(map[string]interface{}{
+				"filter_id": args[0],
+				"deleted":   true,
+			}, "write")
+		},
+	}
+}
+
+func gmailFiltersExportCmd() *cobra.Command {
+	var outputPath string
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export filters as mailFilters.xml",
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.Background()
+			svc, err := gmailReadService(ctx)
+			if err != nil {
+				output.FailureFromError(output.ErrAuthRequired, err)
+				return
+			}
+
+			export, err := svc.ExportFilters()
+			if err != nil {
+				output.APIError(err)
+				return
+			}
+
+			if outputPath != "" {
+				if err := os.WriteFile(outputPath, export.XML, 0644); err != nil {
+					output.FailureFromError(output.ErrInternal, err)
+					return
+				}
+				output.Success(map[string]interface{}{
+					"saved_to": outputPath,
+					"count":    export.Count,
+					"skipped":  export.Skipped,
+				}, "read")
+				return
+			}
+
+			output.Success(map[string]interface{}{
+				"xml":     string(export.XML),
+				"count":   export.Count,
+				"skipped": export.Skipped,
+			}, "read")
+		},
+	}
+
+	cmd.Flags().StringVarP(&outputPath, "output", "o", "", "File path to save mailFilters.xml")
+
+	return cmd
+}
+
+func gmailFiltersImportCmd() *cobra.Command {
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "import <mailFilters.xml>",
+		Short: "Import filters from mailFilters.xml",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			data, err := os.ReadFile(args[0])
+			if err != nil {
+				output.InvalidInputError("Failed to read file: " + err.Error())
+				return
+			}
+
+			if dryRun {
+				filters, err := gmail.ParseFilterXML(data)
+				if err != nil {
+					output.InvalidInputError(err.Error())
+					return
+				}
+				output.SuccessNoScope(map[string]interface{}{
+					"dry_run": true,
+					"filters": filters,
+					"count":   len(filters),
+				})
+				return
+			}
+
+			ctx := context.Background()
+			svc, err := gmailWriteService(ctx)
+			if err != nil {
+				output.Failure(output.ErrScopeInsufficient, err.Error(), nil)
+				return
+			}
+
+			result, err := svc.ImportFilters(data)
+			if err != nil {
+				output.InvalidInputError(err.Error())
+				return
+			}
+
+			output.Success(result, "write")
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Parse and show filters without creating them")
+
+	return cmd
+}
+
+func gmailChangesCmd() *cobra.Command {
+	var sinceHistoryID uint64
+	var stateFile string
+	var fullSyncLimit int
+
+	cmd := &cobra.Command{
+		Use:   "changes",
+		Short: "List mailbox changes since a history ID",
+		Long: `Returns messages added, deleted and relabelled since a history ID, plus the
+new history ID to use as the next cursor.
+
+With --state-file the cursor is read from and written back to a file, so
+repeated runs only return new changes. When no cursor is available or the
+history ID has expired, the most recent messages are listed instead
+(full_sync: true).`,
 		Run: func(cmd *cobra.Command, args []string) {
+			startID := sinceHistoryID
+			if startID == 0 && stateFile != "" {
+				state, err := gmail.LoadHistoryState(stateFile)
+				if err != nil {
+					output.InvalidInputError(err.Error())
+					return
+				}
+				startID = state.HistoryID
+			}
+
+			ctx := context.Background()
+			svc, err := gmailReadService(ctx)
+			if err != nil {
+				output.FailureFromError(output.ErrAuthRequired, err)
+				return
+			}
+
+			result, err := svc.Changes(startID, fullSyncLimit)
+			if err != nil {
+				output.APIError(err)
+				return
+			}
+
+			if stateFile != "" {
+				if err := gmail.SaveHistoryState(stateFile, result.HistoryID); err != nil {
+					output.FailureFromError(output.ErrInternal, err)
+					return
+				}
+			}
+
+			output.Success(result, "read")
+		},
+	}
+
+	cmd.Flags().Uint64Var(&sinceHistoryID, "since-history-id", 0, "History ID to list changes from")
+	cmd.Flags().StringVar(&stateFile, "state-file", "", "File to read and persist the history cursor")
+	cmd.Flags().IntVar(&fullSyncLimit, "full-sync-limit", gmail.DefaultFullSyncLimit, "Maximum messages listed on a full sync")
+
+	return cmd
+}
+
+func gmailDigestCmd() *cobra.Command {
+	var since, query string
+	var max, workers int
+
+	cmd := &cobra.Command{
+		Use:   "digest",
+		Short: "Summarize recent mail for triage",
+		Long: `Returns a compact digest of recent mail: threads grouped by label or Gmail
+category, message counts per sender domain, threads awaiting your reply,
+newsletters (with their List-Unsubscribe target), and messages with calendar
+invites or attachments.
+
+--since accepts relative durations (24h, 3d, 1w) or a date.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			start, err := gmail.ParseQueryDate(since, time.Now())
+			if err != nil {
+				output.InvalidInputError("Invalid --since: " + err.Error())
+				return
+			}
+
+			ctx := context.Background()
+			svc, err := gmailReadService(ctx)
+			if err != nil {
+				output.FailureFromError(output.ErrAuthRequired, err)
+				return
+			}
+
+			result, err := svc.Digest(gmail.DigestOptions{
+				Since:   start,
+				Query:   query,
+				Max:     max,
+				Workers: workers,
+			})
+			if err != nil {
+				output.APIError(err)
+				return
+			}
+
+			output.Success(result, "read")
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "24h", "Start of the digest window (24h, 3d, YYYY-MM-DD, ...)")
+	cmd.Flags().StringVar(&query, "query", "", "Gmail query limiting the digest (default: in:inbox)")
+	cmd.Flags().IntVar(&max, "max", gmail.DefaultDigestMax, "Maximum number of messages")
+	cmd.Flags().IntVar(&workers, "workers", gmail.DefaultFetchWorkers, "Concurrent message fetches")
+
+	return cmd
+}
+
+func gmailAttachmentsCmd() *cobra.Command {
+	var query, dir string
+	var extractText bool
+	var max, maxTextBytes int
+
+	cmd := &cobra.Command{
+		Use:   "attachments [message-id]",
+		Short: "Download all attachments of messages",
+		Long: `Saves every attachment of a message, or of all messages matching --query,
+into --dir with safe, de-duplicated filenames and writes a manifest.json
+with SHA-256 checksums.
+
+With --extract-text, the text of text/*, CSV, JSON, ICS and EML attachments
+is parsed locally and returned inline.`,
+		Args: cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if (len(args) == 0) == (query == "") {
+				output.InvalidInputError("Provide either a message ID or --query")
+				return
+			}
+
+			ctx := context.Background()
+			svc, err := gmailReadService(ctx)
+			if err != nil {
+				output.FailureFromError(output.ErrAuthRequired, err)
+				return
+			}
+
+			messageIDs := args
+			if query != "" {
+				messageIDs, _, err = svc.ListIDs(query, max)
+				if err != nil {
+					output.APIError(err)
+					return
+				}
+			}
+
+			result, err := svc.DownloadAttachments(gmail.DownloadOptions{
+				MessageIDs:   messageIDs,
+				Dir:          dir,
+				ExtractText:  extractText,
+				MaxTextBytes: maxTextBytes,
+			})
+			if err != nil {
+				output.FailureFromError(output.ErrInternal, err)
+				return
+			}
+
+			output.Success(result, "read")
+		},
+	}
+
+	cmd.Flags().StringVar(&query, "query", "", "Gmail search query selecting messages")
+	cmd.Flags().StringVar(&dir, "dir", "", "Directory to save attachments (required)")
+	cmd.Flags().BoolVar(&extractText, "extract-text", false, "Return text of text-like attachments inline")
+	cmd.Flags().IntVar(&max, "max", 50, "Maximum messages to process with --query")
+	cmd.Flags().IntVar(&maxTextBytes, "max-text-bytes", gmail.DefaultMaxTextBytes, "Maximum extracted text per attachment")
+
+	cmd.MarkFlagRequired("dir")
+
+	return cmd
+}
+
+func gmailExportCmd() *cobra.Command {
+	var query, format, out string
+	var max int
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export messages to mbox or EML",
+		Long: `Exports all messages matching --query in raw RFC 2822 form.
+
+--format mbox appends to a single mbox file and records exported message IDs
+in <out>.progress. --format eml writes one <message-id>.eml per message into
+the --out directory. Re-running the same export resumes where it stopped and
+skips messages that were already written; a partly written mbox message is
+removed first.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if format != gmail.ExportFormatMbox && format != gmail.ExportFormatEML {
+				output.InvalidInputError("Invalid format. Use: mbox, eml")
+				return
+			}
+
+			ctx := context.Background()
+			svc, err := gmailReadService(ctx)
+			if err != nil {
+				output.FailureFromError(output.ErrAuthRequired, err)
+				return
+			}
+
+			result, err := svc.Export(gmail.ExportOptions{
+				Query:  query,
+				Format: format,
+				Out:    out,
+				Max:    max,
+			})
+			if err != nil {
+				if result != nil {
+					output.Failure(output.ErrInternal, err.Error(), result)
+					return
+				}
+				output.APIError(err)
+				return
+			}
+
+			output.Success(result, "read")
+		},
+	}
+
+	cmd.Flags().StringVar(&query, "query", "", "Gmail search query (required)")
+	cmd.Flags().StringVar(&format, "format", gmail.ExportFormatMbox, "Format: mbox, eml")
+	cmd.Flags().StringVar(&out, "out", "", "mbox file or EML directory (required)")
+	cmd.Flags().IntVar(&max, "max", 0, "Maximum number of messages (0 = no limit)")
+
+	cmd.MarkFlagRequired("query")
+	cmd.MarkFlagRequired("out")
+
+	return cmd
+}
+
+func gmailImportDraftCmd() *cobra.Command {
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "import-draft <file.eml>...",
+		Short: "Create drafts from local EML files",
+		Long:  "Parses local EML files and creates one Gmail draft per file.",
+		Args:  cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			raws := make([][]byte, 0, len(args))
+			infos := make([]*gmail.EMLInfo, 0, len(args))
+			for _, path := range args {
+				raw, err := os.ReadFile(path)
+				if err != nil {
+					output.InvalidInputError("Failed to read file: " + err.Error())
+					return
+				}
+				info, err := gmail.ParseEML(raw)
+				if err != nil {
+					output.InvalidInputError(path + ": " + err.Error())
+					return
+				}
+				info.File = path
+				raws = append(raws, raw)
+				infos = append(infos, info)
+			}
+
+			if dryRun {
+				output.SuccessNoScope(map[string]interface{}{
+					"dry_run": true,
+					"drafts":  infos,
+					"count":   len(infos),
+				})
+				return
+			}
+
+			ctx := context.Background()
+			svc, err := gmailWriteService(ctx)
+			if err != nil {
+				output.Failure(output.ErrScopeInsufficient, err.Error(), nil)
+				return
+			}
+
+			for i, raw := range raws {
+				draft, err := svc.DraftCreateRaw(raw)
+				if err != nil {
+					output.Failure(output.ErrAPIError, err.Error(), map[string]interface{}{
+						"file":    infos[i].File,
+						"created": infos[:i],
+					})
+					return
+				}
+				infos[i].DraftID = draft.ID
+				infos[i].MessageID = draft.MessageID
+			}
+
+			output.Success(map[string]interface{}{
+				"drafts": infos,
+				"count":  len(infos),
+			}, "write")
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Parse files without creating drafts")
+
+	return cmd
+}
+
+func gmailMergeCmd() *cobra.Command {
+	var templatePath, dataSource, subject, attachmentsDir string
+	var send, noStatus, dryRun bool
+	var rate, max int
+
+	cmd := &cobra.Command{
+		Use:   "merge",
+		Short: "Send personalized messages from a Google Sheet or CSV",
+		Long: `Renders one message per data row from a Markdown template and creates a
+draft (default) or sends it (--send).
+
+The template starts with optional "Subject:", "Cc:" and "Bcc:" lines, then a
+blank line and the Markdown body. Placeholders use Go text/template syntax
+with column names, e.g. {{.FirstName}} or {{index . "First Name"}}.
+
+Data is "sheets:<spreadsheet-id>/<sheet>" or a CSV file; the first row holds
+column names. The to, cc, bcc and attachments columns (";" separated) are
+used per row. Attachments are file names relative to --attachments-dir;
+absolute paths and ".." are rejected. For sheets, a status column is written back after each row and
+rows already marked sent or drafted are skipped on re-runs.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			source, err := gmail.ParseMergeSource(dataSource)
+			if err != nil {
+				output.InvalidInputError(err.Error())
+				return
+			}
+			if rate <= 0 {
+				output.InvalidInputError("--rate must be positive")
+				return
+			}
+
+			src, err := os.ReadFile(templatePath)
+			if err != nil {
+				output.InvalidInputError("Failed to read template: " + err.Error())
+				return
+			}
+			tmpl, err := gmail.ParseMergeTemplate(string(src), subject)
+			if err != nil {
+				output.InvalidInputError(err.Error())
+				return
+			}
+
+			ctx := context.Background()
+
+			var data *gmail.MergeData
+			if source.File != "" {
+				f, err := os.Open(source.File)
+				if err != nil {
+					output.InvalidInputError("Failed to read data: " + err.Error())
+					return
+				}
+				data, err = gmail.ReadMergeCSV(f)
+				f.Close()
+				if err != nil {
+					output.InvalidInputError(err.Error())
+					return
+				}
+			} else {
+				sheetsSvc, err := sheetsReadService(ctx)
+				if err != nil {
+					output.FailureFromError(output.ErrAuthRequired, err)
+					return
+				}
+				values, err := sheetsSvc.Read(source.SpreadsheetID, source.Sheet, "")
+				if err != nil {
+					output.APIError(err)
+					return
+				}
+				records := make([][]string, 0, len(values.Values))
+				for _, row := range values.Values {
+					record := make([]string, len(row))
+					for i, v := range row {
+						record[i] = fmt.Sprint(v)
+					}
+					records = append(records, record)
+				}
+				if data, err = gmail.NewMergeData(records); err != nil {
+					output.InvalidInputError(err.Error())
+					return
+				}
+			}
+
+			messages := tmpl.Render(data, attachmentsDir)
+			if max > 0 {
+				pending := 0
+				for i := range messages {
+					if messages[i].Skipped || messages[i].Error != "" {
+						continue
+					}
+					if pending++; pending > max {
+						messages = messages[:i]
+						break
+					}
+				}
+			}
+
+			if dryRun {
+				output.SuccessNoScope(map[string]interface{}{
+					"dry_run":  true,
+					"source":   source,
+					"send":     send,
+					"messages": messages,
+					"count":    len(messages),
+				})
+				return
+			}
+
+			svc, err := gmailWriteService(ctx)
+			if err != nil {
+				output.Failure(output.ErrScopeInsufficient, err.Error(), nil)
+				return
+			}
+
+			// Status write-back is only possible for sheets.
+			var writeStatus func(row int, status string) error
+			if source.SpreadsheetID != "" && !noStatus {
+				sheetsSvc, err := sheetsWriteService(ctx)
+				if err != nil {
+					output.Failure(output.ErrScopeInsufficient, err.Error(), nil)
+					return
+				}
+				col := data.Column("status")
+				if col < 0 {
+					col = len(data.Headers)
+					if _, err := sheetsSvc.Write(source.SpreadsheetID, source.Sheet, sheets.ColumnName(col)+"1", [][]any{{"status"}}); err != nil {
+						output.APIError(err)
+						return
+					}
+				}
+				colName := sheets.ColumnName(col)
+				writeStatus = func(row int, status string) error {
+					_, err := sheetsSvc.Write(source.SpreadsheetID, source.Sheet, fmt.Sprintf("%s%d", colName, row), [][]any{{status}})
+					return err
+				}
+			}
+
+			interval := time.Minute / time.Duration(rate)
+			results := make([]map[string]interface{}, 0, len(messages))
+			counts := map[string]int{}
+			var lastSend time.Time
+
+			for _, m := range messages {
+				result := map[string]interface{}{
+					"row":     m.Row,
+					"to":      m.To,
+					"subject": m.Subject,
+				}
+
+				var status string
+				switch {
+				case m.Skipped:
+					result["status"] = "skipped"
+					counts["skipped"]++
+				case m.Error != "":
+					result["status"] = gmail.MergeStatusError
+					result["error"] = m.Error
+					status = gmail.MergeStatusError + ": " + m.Error
+					counts["failed"]++
+				default:
+					if send && !lastSend.IsZero() {
+						time.Sleep(time.Until(lastSend.Add(interval)))
+					}
+					id, err := svc.SendMerge(m, !send)
+					if send {
+						lastSend = time.Now()
+					}
+					if err != nil {
+						result["status"] = gmail.MergeStatusError
+						result["error"] = err.Error()
+						status = gmail.MergeStatusError + ": " + err.Error()
+						counts["failed"]++
+						break
+					}
+					state := gmail.MergeStatusDrafted
+					if send {
+						state = gmail.MergeStatusSent
+					}
+					result["status"] = state
+					result["id"] = id
+					status = state + " " + time.Now().UTC().Format(time.RFC3339)
+					counts[state]++
+				}
+
+				if writeStatus != nil && status != "" {
+					if err := writeStatus(m.Row, status); err != nil {
+						result["status_error"] = err.Error()
+					}
+				}
+				results = append(results, result)
+			}
+
+			output.Success(map[string]interface{}{
+				"source":  source,
+				"results": results,
+				"count":   len(results),
+				"sent":    counts[gmail.MergeStatusSent],
+				"drafted": counts[gmail.MergeStatusDrafted],
+				"skipped": counts["skipped"],
+				"failed":  counts["failed"],
+			}, "write")
+		},
+	}
+
+	cmd.Flags().StringVar(&templatePath, "template", "", "Markdown template file (required)")
+	cmd.Flags().StringVar(&dataSource, "data", "", "sheets:<spreadsheet-id>/<sheet> or a CSV file (required)")
+	cmd.Flags().StringVar(&subject, "subject", "", "Subject template (overrides the template's Subject line)")
+	cmd.Flags().StringVar(&attachmentsDir, "attachments-dir", "", "Directory the attachments column is resolved against")
+	cmd.Flags().BoolVar(&send, "send", false, "Send messages instead of creating drafts")
+	cmd.Flags().IntVar(&rate, "rate", 20, "Maximum messages sent per minute")
+	cmd.Flags().IntVar(&max, "max", 0, "Maximum number of messages to process (0 = all)")
+	cmd.Flags().BoolVar(&noStatus, "no-status", false, "Do not write the status column back to the sheet")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Render messages without creating drafts or sending")
+
+	cmd.MarkFlagRequired("template")
+	cmd.MarkFlagRequired("data")
+
+	return cmd
+}
+
+func gmailUnsubscribeCmd() *cobra.Command {
+	var query string
+	var max int
+	var filter, archiveAnyway, dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "unsubscribe [message-id]",
+		Short: "Unsubscribe from mailing lists",
+		Long: `Unsubscribes from the senders of a message or of all messages matching
+--query, using their List-Unsubscribe headers. One action is taken per sender:
+
+  one-click  RFC 8058 HTTPS POST (unsubscribed immediately)
+  mailto     unsubscribe email sent from your account (requested)
+  link       web page only; returned as url for you to open (manual)
+
+--filter also creates a filter archiving future mail from each sender that
+offers a way to unsubscribe; other senders are reported with filter_skipped
+unless --archive-anyway is given.`,
+		Args: cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if (len(args) == 0) == (query == "") {
+				output.InvalidInputError("Provide either a message ID or --query")
+				return
+			}
+
+			ctx := context.Background()
+			var svc *gmail.Service
+			var err error
+			if dryRun {
+				svc, err = gmailReadService(ctx)
+				if err != nil {
+					output.FailureFromError(output.ErrAuthRequired, err)
+					return
+				}
+			} else {
+				svc, err = gmailWriteService(ctx)
+				if err != nil {
+					output.Failure(output.ErrScopeInsufficient, err.Error(), nil)
+					return
+				}
+			}
+
+			ids := args
+			truncated := false
+			if query != "" {
+				ids, truncated, err = svc.ListIDs(query, max)
+				if err != nil {
+					output.APIError(err)
+					return
+				}
+			}
+
+			targets, failed := svc.UnsubscribeTargets(ids, gmail.DefaultFetchWorkers)
+
+			if !dryRun {
+				for i := range targets {
+					svc.Unsubscribe(&targets[i], gmail.UnsubscribeOptions{Filter: filter, ArchiveAnyway: archiveAnyway})
+				}
+			}
+
+			result := map[string]interface{}{
+				"senders":   targets,
+				"count":     len(targets),
+				"messages":  len(ids),
+				"truncated": truncated,
+				"failed":    failed,
+			}
+			if dryRun {
+				result["dry_run"] = true
+				result["filter"] = filter
+				output.SuccessNoScope(result)
+				return
+			}
+
+			output.Success(result, "write")
+		},
+	}
+
+	cmd.Flags().StringVar(&query, "query", "", "Unsubscribe from the senders of all matching messages")
+	cmd.Flags().IntVar(&max, "max", 100, "Maximum messages to inspect with --query")
+	cmd.Flags().BoolVar(&filter, "filter", false, "Also create a filter archiving future mail from each sender")
+	cmd.Flags().BoolVar(&archiveAnyway, "archive-anyway", false, "With --filter, also filter senders that offer no way to unsubscribe")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "List the unsubscribe method per sender without acting")
+
+	return cmd
+}
+
+func gmailRSVPCmd() *cobra.Command {
+	var status, calendarID string
+	var dryRun bool
+
+	statuses := map[string]string{
+		"accepted": "accepted", "accept": "accepted", "yes": "accepted",
+		"declined": "declined", "decline": "declined", "no": "declined",
+		"tentative": "tentative", "maybe": "tentative",
+	}
+
+	cmd := &cobra.Command{
+		Use:   "rsvp <message-id>",
+		Short: "Respond to a calendar invitation received by email",
+		Long: `Finds the calendar event of the invitation in a message (by its iCalendar
+UID) and sets your response status on it.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			response, ok := statuses[strings.ToLower(status)]
+			if !ok {
+				output.InvalidInputError("Invalid --status: " + status + " (use: accepted, declined, tentative)")
+				return
+			}
+
+			ctx := context.Background()
+			svc, err := gmailReadService(ctx)
+			if err != nil {
+				output.FailureFromError(output.ErrAuthRequired, err)
+				return
+			}
+
+			invite, err := svc.Invite(args[0])
+			if err != nil {
+				output.APIError(err)
+				return
+			}
+			if invite.Method == "CANCEL" {
+				output.InvalidInputError("The invitation was cancelled by the organizer")
+				return
+			}
+
+			var calSvc *calendar.Service
+			if dryRun {
+				calSvc, err = calendarReadService(ctx)
+				if err != nil {
+					output.FailureFromError(output.ErrAuthRequired, err)
+					return
+				}
+			} else {
+				calSvc, err = calendarWriteService(ctx)
+				if err != nil {
+					output.Failure(output.ErrScopeInsufficient, err.Error(), nil)
+					return
+				}
+			}
+
+			event, err := calSvc.GetByICalUID(calendarID, invite.UID)
+			if err != nil {
+				output.APIError(err)
+				return
+			}
+
+			result := map[string]interface{}{
+				"message_id":      args[0],
+				"event_id":        event.ID,
+				"uid":             invite.UID,
+				"summary":         invite.Summary,
+				"start":           invite.Start,
+				"status":          response,
+				"previous_status": invite.MyStatus,
+			}
+
+			if dryRun {
+				result["dry_run"] = true
+				output.SuccessNoScope(result)
+				return
+			}
+
+			if err := calSvc.Respond(calendarID, event.ID, response); err != nil {
+				output.APIError(err)
+				return
+			}
+
+			output.Success(result, "write")
+		},
+	}
+
+	cmd.Flags().StringVar(&status, "status", "", "Response: accepted, declined or tentative (required)")
+	cmd.Flags().StringVar(&calendarID, "calendar", "primary", "Calendar containing the event")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the event that would be updated")
+
+	cmd.MarkFlagRequired("status")
+
+	return cmd
+}
+
+func gmailSettingsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "settings",
+		Short: "Manage send-as aliases, signatures and the vacation responder",
+	}
+
+	cmd.AddCommand(gmailSettingsSendAsCmd())
+	cmd.AddCommand(gmailSettingsSignatureCmd())
+	cmd.AddCommand(gmailSettingsVacationCmd())
+
+	return cmd
+}
+
+func gmailSettingsSendAsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "sendas",
+		Short: "List send-as aliases",
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.Background()
+			svc, err := gmailReadService(ctx)
+			if err != nil {
+				output.FailureFromError(output.ErrAuthRequired, err)
+				return
+			}
+
+			aliases, err := svc.SendAs()
+			if err != nil {
+				output.APIError(err)
+				return
+			}
+
+			output.Success(map[string]interface{}{
+				"aliases": aliases,
+				"count":   len(aliases),
+			}, "read")
+		},
+	}
+}
+
+func gmailSettingsSignatureCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "signature",
+		Short: "Get or set the signature of a send-as alias",
+	}
+
+	cmd.AddCommand(gmailSettingsSignatureGetCmd())
+	cmd.AddCommand(gmailSettingsSignatureSetCmd())
+
+	return cmd
+}
+
+func gmailSettingsSignatureGetCmd() *cobra.Command {
+	var from string
+
+	cmd := &cobra.Command{
+		Use:   "get",
+		Short: "Show the signature of an alias",
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.Background()
+			svc, err := gmailReadService(ctx)
+			if err != nil {
+				output.FailureFromError(output.ErrAuthRequired, err)
+				return
+			}
+
+			alias, err := svc.Signature(from)
+			if err != nil {
+				output.APIError(err)
+				return
+			}
+
+			output.Success(map[string]interface{}{
+				"email":     alias.Email,
+				"signature": alias.Signature,
+			}, "read")
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "Send-as alias (default: the default alias)")
+
+	return cmd
+}
+
+func gmailSettingsSignatureSetCmd() *cobra.Command {
+	var from, html, markdown, file string
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "set",
+		Short: "Set the signature of an alias",
+		Long: `Replaces the HTML signature of a send-as alias.
+
+Provide the signature with --html, --markdown or --file (Markdown if the file
+ends in .md, HTML otherwise). An empty --html clears the signature.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			sources := 0
+			for _, name := range []string{"html", "markdown", "file"} {
+				if cmd.Flags().Changed(name) {
+					sources++
+				}
+			}
+			if sources != 1 {
+				output.InvalidInputError("Exactly one of --html, --markdown or --file is required")
+				return
+			}
+
+			if file != "" {
+				data, err := os.ReadFile(file)
+				if err != nil {
+					output.InvalidInputError("Failed to read file: " + err.Error())
+					return
+				}
+				if strings.HasSuffix(strings.ToLower(file), ".md") {
+					markdown = string(data)
+				} else {
+					html = string(data)
+				}
+			}
+			if markdown != "" {
+				rendered, err := gmail.MarkdownToHTML(markdown)
+				if err != nil {
+					output.InvalidInputError(err.Error())
+					return
+				}
+				html = rendered
+			}
+
+			if dryRun {
+				output.SuccessNoScope(map[string]interface{}{
+					"dry_run":   true,
+					"from":      from,
+					"signature": html,
+				})
+				return
+			}
+
+			ctx := context.Background()
+			svc, err := gmailWriteService(ctx)
+			if err != nil {
+				output.Failure(output.ErrScopeInsufficient, err.Error(), nil)
+				return
+			}
+
+			alias, err := svc.SetSignature(from, html)
+			if err != nil {
+				output.APIError(err)
+				return
+			}
+
+			output.Success(map[string]interface{}{
+				"email":     alias.Email,
+				"signature": alias.Signature,
+			}, "write")
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "Send-as alias (default: the default alias)")
+	cmd.Flags().StringVar(&html, "html", "", "Signature as HTML")
+	cmd.Flags().StringVar(&markdown, "markdown", "", "Signature as Markdown")
+	cmd.Flags().StringVar(&file, "file", "", "Read the signature from a file (.md for Markdown)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the rendered signature without saving it")
+
+	return cmd
+}
+
+func gmailSettingsVacationCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "vacation",
+		Short: "Manage the vacation responder (out-of-office)",
+	}
+
+	cmd.AddCommand(gmailSettingsVacationGetCmd())
+	cmd.AddCommand(gmailSettingsVacationSetCmd())
+	cmd.AddCommand(gmailSettingsVacationOffCmd())
+
+	return cmd
+}
+
+func gmailSettingsVacationGetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "get",
+		Short: "Show the vacation responder settings",
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.Background()
+			svc, err := gmailReadService(ctx)
+			if err != nil {
+				output.FailureFromError(output.ErrAuthRequired, err)
+				return
+			}
+
+			vacation, err := svc.Vacation()
+			if err != nil {
+				output.APIError(err)
+				return
+			}
+
+			output.Success(vacation, "read")
+		},
+	}
+}
+
+func gmailSettingsVacationSetCmd() *cobra.Command {
+	var opts gmail.VacationOptions
+	var start, end, messageFile string
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "set",
+		Short: "Enable the vacation responder",
+		Long: `Enables the vacation responder with a Markdown message.
+
+--start and --end accept RFC3339 or YYYY-MM-DD (local time); a date-only
+--end includes the whole day. Without --start the responder is active now,
+without --end it stays active until turned off.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if messageFile != "" {
+				data, err := os.ReadFile(messageFile)
+				if err != nil {
+					output.InvalidInputError("Failed to read file: " + err.Error())
+					return
+				}
+				opts.Message = string(data)
+			}
+
+			var err error
+			if start != "" {
+				if opts.Start, err = gmail.ParseVacationTime(start, false); err != nil {
+					output.InvalidInputError("Invalid --start: " + err.Error())
+					return
+				}
+			}
+			if end != "" {
+				if opts.End, err = gmail.ParseVacationTime(end, true); err != nil {
+					output.InvalidInputError("Invalid --end: " + err.Error())
+					return
+				}
+			}
+
+			preview, err := gmail.PreviewVacation(opts)
+			if err != nil {
+				output.InvalidInputError(err.Error())
+				return
+			}
+
+			if dryRun {
+				output.SuccessNoScope(map[string]interface{}{
+					"dry_run":  true,
+					"vacation": preview,
+				})
+				return
+			}
+
+			ctx := context.Background()
+			svc, err := gmailWriteService(ctx)
+			if err != nil {
+				output.Failure(output.ErrScopeInsufficient, err.Error(), nil)
+				return
+			}
+
+			vacation, err := svc.SetVacation(opts)
+			if err != nil {
+				output.APIError(err)
+				return
+			}
+
+			output.Success(vacation, "write")
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Subject, "subject", "", "Auto-reply subject")
+	cmd.Flags().StringVar(&opts.Message, "message", "", "Auto-reply message (Markdown)")
+	cmd.Flags().StringVar(&messageFile, "message-file", "", "Read the Markdown message from a file")
+	cmd.Flags().StringVar(&start, "start", "", "Start time (RFC3339 or YYYY-MM-DD)")
+	cmd.Flags().StringVar(&end, "end", "", "End time (RFC3339 or YYYY-MM-DD, inclusive)")
+	cmd.Flags().BoolVar(&opts.ContactsOnly, "contacts-only", false, "Only reply to people in your contacts")
+	cmd.Flags().BoolVar(&opts.DomainOnly, "domain-only", false, "Only reply to people in your domain (Workspace)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the settings without saving them")
+
+	return cmd
+}
+
+func gmailSettingsVacationOffCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "off",
+		Short: "Disable the vacation responder",
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.Background()
+			svc, err := gmailWriteService(ctx)
+			if err != nil {
+				output.Failure(output.ErrScopeInsufficient, err.Error(), nil)
+				return
+			}
+
+			vacation, err := svc.DisableVacation()
+			if err != nil {
+				output.APIError(err)
+				return
+			}
+
+			output.Success(vacation, "write")
+		},
+	}
+}
+
+// Gmail API commands
+func gmailAPICmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "api",
+		Short: "Low-level Gmail API commands",
+		Long:  "Direct access to Gmail API operations.",
+	}
+
+	cmd.AddCommand(gmailAPIListCmd())
+	cmd.AddCommand(gmailAPIGetCmd())
+	cmd.AddCommand(gmailAPILabelsCmd())
+	cmd.AddCommand(gmailAPIAttachmentCmd())
+	cmd.AddCommand(gmailAPIModifyCmd())
+	cmd.AddCommand(gmailAPITrashCmd())
+	cmd.AddCommand(gmailAPIUntrashCmd())
+	cmd.AddCommand(gmailAPIDeleteCmd())
+	cmd.AddCommand(gmailAPISendRawCmd())
+	cmd.AddCommand(gmailAPIDraftCreateCmd())
+	cmd.AddCommand(gmailAPIDraftSendCmd())
+	cmd.AddCommand(gmailAPIDraftUpdateCmd())
+	cmd.AddCommand(gmailAPIDraftDeleteCmd())
+
+	return cmd
+}
+
+func gmailAPIListCmd() *cobra.Command {
+	var label, query, pageToken string
+	var limit int64
+	var queryOpts gmail.QueryOptions
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List messages",
+		Run: func(cmd *cobra.Command, args []string) {
+			queryOpts.Raw = query
+			compiled, err := gmail.BuildQuery(queryOpts, time.Now())
+			if err != nil {
+				output.InvalidInputError(err.Error())
+				return
+			}
+
 			ctx := context.Background()
 			svc, err := gmailReadService(ctx)
 			if err != nil {
@@ -482,7 +1896,7 @@ func gmailAPIListCmd() *cobra.Command {
 			}
 
 			opts := gmail.ListOptions{
-				Query:      query,
+				Query:      compiled,
 				MaxResults: limit,
 				PageToken:  pageToken,
 			}
@@ -490,22 +1904,25 @@ func gmailAPIListCmd() *cobra.Command {
 				opts.LabelIDs = []string{label}
 			}
 
-			messages, nextPageToken, err := svc.List(opts)
+			result, err := svc.List(opts)
 			if err != nil {
 				output.APIError(err)
 				return
 			}
 
 			output.Success(map[string]interface{}{
-				"messages":        messages,
-				"count":           len(messages),
-				"next_page_token": nextPageToken,
+				"query":           compiled,
+				"messages":        result.Messages,
+				"count":           len(result.Messages),
+				"next_page_token": result.NextPageToken,
+				"failed":          result.Failed,
 			}, "read")
 		},
 	}
 
 	cmd.Flags().StringVar(&label, "label", "", "Label ID to filter by")
 	cmd.Flags().StringVar(&query, "query", "", "Gmail search query")
+	addQueryFlags(cmd, &queryOpts)
 	cmd.Flags().Int64VarP(&limit, "limit", "n", 10, "Maximum number of messages")
 	cmd.Flags().StringVar(&pageToken, "page-token", "", "Page token for pagination")
 
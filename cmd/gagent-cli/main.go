@@ -77,6 +77,17 @@ func gmailCmd() *cobra.Command {
 	cmd.AddCommand(gmailReplyCmd())
 	cmd.AddCommand(gmailForwardCmd())
 	cmd.AddCommand(gmailDraftCmd())
+	cmd.AddCommand(gmailBulkCmd())
+	cmd.AddCommand(gmailFiltersCmd())
+	cmd.AddCommand(gmailChangesCmd())
+	cmd.AddCommand(gmailDigestCmd())
+	cmd.AddCommand(gmailAttachmentsCmd())
+	cmd.AddCommand(gmailExportCmd())
+	cmd.AddCommand(gmailImportDraftCmd())
+	cmd.AddCommand(gmailMergeCmd())
+	cmd.AddCommand(gmailUnsubscribeCmd())
+	cmd.AddCommand(gmailRSVPCmd())
+	cmd.AddCommand(gmailSettingsCmd())
 
 	// API commands
 	cmd.AddCommand(gmailAPICmd())
@@ -96,13 +107,26 @@ func calendarCmd() *cobra.Command {
 	cmd.AddCommand(calendarTodayCmd())
 	cmd.AddCommand(calendarWeekCmd())
 	cmd.AddCommand(calendarUpcomingCmd())
+	cmd.AddCommand(calendarAgendaCmd())
+	cmd.AddCommand(calendarStatsCmd())
 	cmd.AddCommand(calendarEventCmd())
 	cmd.AddCommand(calendarFindCmd())
 	cmd.AddCommand(calendarFreeBusyCmd())
+	cmd.AddCommand(calendarFindTimeCmd())
 	cmd.AddCommand(calendarScheduleCmd())
 	cmd.AddCommand(calendarRescheduleCmd())
 	cmd.AddCommand(calendarCancelCmd())
 	cmd.AddCommand(calendarRespondCmd())
+	cmd.AddCommand(calendarAttendeesCmd())
+	cmd.AddCommand(calendarNudgeCmd())
+	cmd.AddCommand(calendarBlockFocusCmd())
+	cmd.AddCommand(calendarOOOCmd())
+	cmd.AddCommand(calendarInstancesCmd())
+	cmd.AddCommand(calendarExportCmd())
+	cmd.AddCommand(calendarImportCmd())
+	cmd.AddCommand(calendarChangesCmd())
+	cmd.AddCommand(calendarCalendarsCmd())
+	cmd.AddCommand(calendarACLCmd())
 
 	// API commands
 	cmd.AddCommand(calendarAPICmd())
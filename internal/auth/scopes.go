@@ -26,6 +26,7 @@ var ReadScopes = []string{
 var WriteScopes = []string{
 	"https://www.googleapis.com/auth/gmail.send",
 	"https://www.googleapis.com/auth/gmail.modify",
+	"https://www.googleapis.com/auth/gmail.settings.basic",
 	"https://www.googleapis.com/auth/calendar",
 	"https://www.googleapis.com/auth/documents",
 	"https://www.googleapis.com/auth/spreadsheets",
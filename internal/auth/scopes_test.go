@@ -22,6 +22,7 @@ func TestGetScopes_Write(t *testing.T) {
 	assert.NotEmpty(t, scopes)
 	assert.Contains(t, scopes, "https://www.googleapis.com/auth/gmail.send")
 	assert.Contains(t, scopes, "https://www.googleapis.com/auth/gmail.modify")
+	assert.Contains(t, scopes, "https://www.googleapis.com/auth/gmail.settings.basic")
 	assert.Contains(t, scopes, "https://www.googleapis.com/auth/calendar")
 	assert.Contains(t, scopes, "https://www.googleapis.com/auth/documents")
 	assert.Contains(t, scopes, "https://www.googleapis.com/auth/spreadsheets")
@@ -0,0 +1,380 @@
+package calendar
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+// Agenda formats.
+const (
+	AgendaMarkdown = "md"
+	AgendaText     = "text"
+)
+
+// minAgendaGap is the shortest free time between events shown as a gap.
+const minAgendaGap = 30 * time.Minute
+
+// AgendaItem is an event on an agenda day. Times are local clock times;
+// a start or end on another day includes its date.
+type AgendaItem struct {
+	ID              string               `json:"id"`
+	Title           string               `json:"title"`
+	Start           string               `json:"start,omitempty"`
+	End             string               `json:"end,omitempty"`
+	AllDay          bool                 `json:"all_day"`
+	Location        string               `json:"location,omitempty"`
+	RSVP            string               `json:"rsvp,omitempty"`
+	MeetLink        string               `json:"meet_link,omitempty"`
+	Conflict        bool                 `json:"conflict,omitempty"`
+	Free            bool                 `json:"free,omitempty"`
+	EventType       string               `json:"event_type"`
+	WorkingLocation *WorkingLocationInfo `json:"working_location,omitempty"`
+}
+
+// AgendaGap is free time between two events of a day.
+type AgendaGap struct {
+	Start   string `json:"start"`
+	End     string `json:"end"`
+	Minutes int    `json:"minutes"`
+}
+
+// AgendaDay is one day of an agenda.
+type AgendaDay struct {
+	Date   string       `json:"date"`
+	Label  string       `json:"label"`
+	Events []AgendaItem `json:"events"`
+	Gaps   []AgendaGap  `json:"gaps"`
+
+	// Timed, non-free items in start order, for gap detection.
+	timed []agendaSpan
+}
+
+type agendaSpan struct {
+	interval
+	item int
+}
+
+// Agenda is a day-by-day view of events in one time zone.
+type Agenda struct {
+	TimeZone string      `json:"time_zone"`
+	From     string      `json:"from"`
+	To       string      `json:"to"`
+	Days     []AgendaDay `json:"days"`
+	Count    int         `json:"count"`
+}
+
+// ParseAgendaRange parses "today", "tomorrow", "week" (7 days from today)
+// or "N days"/"Nd" into whole days in loc starting today.
+func ParseAgendaRange(spec string, now time.Time, loc *time.Location) (time.Time, time.Time, error) {
+	now = now.In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+
+	s := strings.ToLower(strings.TrimSpace(spec))
+	switch s {
+	case "today", "":
+		return today, today.AddDate(0, 0, 1), nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), today.AddDate(0, 0, 2), nil
+	case "week":
+		return today, today.AddDate(0, 0, 7), nil
+	}
+
+	s = strings.TrimSuffix(strings.TrimSuffix(strings.TrimSuffix(s, "days"), "day"), "d")
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || n < 1 || n > 31 {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid range %q (use: today, tomorrow, week, N days up to 31)", spec)
+	}
+	return today, today.AddDate(0, 0, n), nil
+}
+
+// Agenda returns the events between from and to grouped by day in loc.
+func (s *Service) Agenda(calendarID string, from, to time.Time, loc *time.Location) (*Agenda, error) {
+	if calendarID == "" {
+		calendarID = "primary"
+	}
+
+	events, err := s.listRange(calendarID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return BuildAgenda(events, from, to, loc), nil
+}
+
+// BuildAgenda groups events into days between from and to in loc. Events
+// appear on each day they cover, including events that started before from;
+// a timed event only counts as busy on each day for the part falling on
+// that day. Overlapping busy events are marked as conflicts, and free time of
+// at least 30 minutes between events is listed as gaps.
+func BuildAgenda(events []*calendar.Event, from, to time.Time, loc *time.Location) *Agenda {
+	from, to = from.In(loc), to.In(loc)
+	agenda := &Agenda{
+		TimeZone: loc.String(),
+		From:     from.Format(time.RFC3339),
+		To:       to.Format(time.RFC3339),
+		Days:     []AgendaDay{},
+	}
+
+	dayIndex := make(map[string]int)
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		key := day.Format("2006-01-02")
+		dayIndex[key] = len(agenda.Days)
+		agenda.Days = append(agenda.Days, AgendaDay{
+			Date:   key,
+			Label:  day.Format("Mon, Jan 2"),
+			Events: []AgendaItem{},
+			Gaps:   []AgendaGap{},
+		})
+	}
+
+	sorted := make([]*calendar.Event, 0, len(events))
+	for _, e := range events {
+		if e.Status != "cancelled" && e.Start != nil && e.End != nil {
+			sorted = append(sorted, e)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		si, _, _ := eventInterval(sorted[i], loc)
+		sj, _, _ := eventInterval(sorted[j], loc)
+		// All-day events first on their day.
+		if si.Equal(sj) {
+			return sorted[i].Start.Date != "" && sorted[j].Start.Date == ""
+		}
+		return si.Before(sj)
+	})
+
+	for _, e := range sorted {
+		start, end, ok := eventInterval(e, loc)
+		if !ok {
+			continue
+		}
+		item := agendaItem(e)
+
+		if item.AllDay {
+			for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+				if i, ok := dayIndex[day.Format("2006-01-02")]; ok {
+					agenda.Days[i].Events = append(agenda.Days[i].Events, item)
+					agenda.Count++
+				}
+			}
+			continue
+		}
+
+		start, end = start.In(loc), end.In(loc)
+		first := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, loc)
+		for dayStart := first; dayStart.Before(end) || dayStart.Equal(start); dayStart = dayStart.AddDate(0, 0, 1) {
+			i, ok := dayIndex[dayStart.Format("2006-01-02")]
+			if !ok {
+				continue
+			}
+			dayEnd := dayStart.AddDate(0, 0, 1)
+
+			dayItem := item
+			dayItem.Start = agendaClock(start, dayStart)
+			dayItem.End = agendaClock(end, dayStart)
+
+			day := &agenda.Days[i]
+			day.Events = append(day.Events, dayItem)
+			agenda.Count++
+			if blocksTime(e, nil) {
+				clipped := interval{maxTime(start, dayStart), minTime(end, dayEnd)}
+				day.timed = append(day.timed, agendaSpan{clipped, len(day.Events) - 1})
+			}
+		}
+	}
+
+	for i := range agenda.Days {
+		markConflictsAndGaps(&agenda.Days[i])
+	}
+
+	return agenda
+}
+
+// agendaClock formats t as a clock time, with its date if it is not on the
+// day starting at dayStart.
+func agendaClock(t, dayStart time.Time) string {
+	if t.Format("2006-01-02") != dayStart.Format("2006-01-02") {
+		return t.Format("Jan 2 15:04")
+	}
+	return t.Format("15:04")
+}
+
+// agendaItem converts an event to an agenda item without times.
+func agendaItem(e *calendar.Event) AgendaItem {
+	full := parseEventToFull(e)
+	item := AgendaItem{
+		ID:              full.ID,
+		Title:           full.Title,
+		AllDay:          full.AllDay,
+		Location:        full.Location,
+		Free:            e.Transparency == "transparent",
+		EventType:       full.EventType,
+		WorkingLocation: full.WorkingLocation,
+	}
+	if item.Title == "" {
+		item.Title = "(no title)"
+	}
+	for _, a := range full.Attendees {
+		if a.Self {
+			item.RSVP = a.ResponseStatus
+		}
+	}
+	if full.ConferenceData != nil {
+		for _, ep := range full.ConferenceData.EntryPoints {
+			if ep.Type == "video" {
+				item.MeetLink = ep.URI
+				break
+			}
+		}
+	}
+	if item.MeetLink == "" {
+		item.MeetLink = e.HangoutLink
+	}
+	return item
+}
+
+// markConflictsAndGaps marks overlapping timed events of a day and records
+// the free time between them.
+func markConflictsAndGaps(day *AgendaDay) {
+	var busyUntil time.Time
+	busyItem := -1
+	for _, span := range day.timed {
+		if busyItem >= 0 && span.start.Before(busyUntil) {
+			day.Events[busyItem].Conflict = true
+			day.Events[span.item].Conflict = true
+		} else if busyItem >= 0 && span.start.Sub(busyUntil) >= minAgendaGap {
+			day.Gaps = append(day.Gaps, AgendaGap{
+				Start:   busyUntil.Format("15:04"),
+				End:     span.start.Format("15:04"),
+				Minutes: int(span.start.Sub(busyUntil).Minutes()),
+			})
+		}
+		if busyItem < 0 || span.end.After(busyUntil) {
+			busyUntil, busyItem = span.end, span.item
+		}
+	}
+}
+
+// Render formats an agenda as Markdown (AgendaMarkdown) or plain text
+// (AgendaText).
+func (a *Agenda) Render(format string) (string, error) {
+	var markdown bool
+	switch format {
+	case AgendaMarkdown:
+		markdown = true
+	case AgendaText:
+	default:
+		return "", fmt.Errorf("invalid format %q (use: md, text)", format)
+	}
+
+	var b strings.Builder
+	for i, day := range a.Days {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		if markdown {
+			fmt.Fprintf(&b, "## %s\n\n", day.Label)
+		} else {
+			fmt.Fprintf(&b, "%s\n", day.Label)
+		}
+
+		if len(day.Events) == 0 {
+			if markdown {
+				b.WriteString("_No events_\n")
+			} else {
+				b.WriteString("  No events\n")
+			}
+			continue
+		}
+
+		// Interleave gaps after the event they follow. A start with a date
+		// is on an earlier day, so no gap precedes it.
+		gaps := day.Gaps
+		for _, item := range day.Events {
+			for len(gaps) > 0 && !item.AllDay && len(item.Start) == len("15:04") && gaps[0].End <= item.Start {
+				writeGap(&b, gaps[0], markdown)
+				gaps = gaps[1:]
+			}
+			writeItem(&b, item, markdown)
+		}
+		for _, gap := range gaps {
+			writeGap(&b, gap, markdown)
+		}
+	}
+
+	return b.String(), nil
+}
+
+func writeItem(b *strings.Builder, item AgendaItem, markdown bool) {
+	when := "All day"
+	if !item.AllDay {
+		when = item.Start + "-" + item.End
+	}
+
+	var notes []string
+	switch item.EventType {
+	case EventTypeFocusTime:
+		notes = append(notes, "focus time")
+	case EventTypeOutOfOffice:
+		notes = append(notes, "out of office")
+	case EventTypeWorkingLocation:
+		if item.WorkingLocation != nil {
+			notes = append(notes, "working from "+describeWorkingLocation(item.WorkingLocation))
+		}
+	}
+	if item.RSVP != "" && item.RSVP != "accepted" {
+		notes = append(notes, item.RSVP)
+	}
+	if item.Free {
+		notes = append(notes, "free")
+	}
+	if item.Conflict {
+		notes = append(notes, "CONFLICT")
+	}
+	if item.Location != "" {
+		notes = append(notes, item.Location)
+	}
+
+	if markdown {
+		fmt.Fprintf(b, "- **%s** %s", when, item.Title)
+		if len(notes) > 0 {
+			fmt.Fprintf(b, " _(%s)_", strings.Join(notes, ", "))
+		}
+		if item.MeetLink != "" {
+			fmt.Fprintf(b, " [Meet](%s)", item.MeetLink)
+		}
+	} else {
+		fmt.Fprintf(b, "  %-11s  %s", when, item.Title)
+		if len(notes) > 0 {
+			fmt.Fprintf(b, " (%s)", strings.Join(notes, ", "))
+		}
+		if item.MeetLink != "" {
+			fmt.Fprintf(b, " %s", item.MeetLink)
+		}
+	}
+	b.WriteString("\n")
+}
+
+func writeGap(b *strings.Builder, gap AgendaGap, markdown bool) {
+	if markdown {
+		fmt.Fprintf(b, "- _%s-%s free (%s)_\n", gap.Start, gap.End, formatMinutes(gap.Minutes))
+	} else {
+		fmt.Fprintf(b, "  %-11s  free (%s)\n", gap.Start+"-"+gap.End, formatMinutes(gap.Minutes))
+	}
+}
+
+// formatMinutes formats a duration in minutes as "45m", "2h" or "1h30m".
+func formatMinutes(minutes int) string {
+	h, m := minutes/60, minutes%60
+	switch {
+	case h == 0:
+		return fmt.Sprintf("%dm", m)
+	case m == 0:
+		return fmt.Sprintf("%dh", h)
+	}
+	return fmt.Sprintf("%dh%02dm", h, m)
+}
@@ -0,0 +1,156 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/calendar/v3"
+)
+
+func TestParseAgendaRange(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	// 23:30 UTC is already Monday in Berlin.
+	now := time.Date(2026, 10, 18, 23, 30, 0, 0, time.UTC)
+
+	tests := map[string][2]string{
+		"today":    {"2026-10-19T00:00:00+02:00", "2026-10-20T00:00:00+02:00"},
+		"tomorrow": {"2026-10-20T00:00:00+02:00", "2026-10-21T00:00:00+02:00"},
+		"week":     {"2026-10-19T00:00:00+02:00", "2026-10-26T00:00:00+01:00"},
+		"3 days":   {"2026-10-19T00:00:00+02:00", "2026-10-22T00:00:00+02:00"},
+		"2d":       {"2026-10-19T00:00:00+02:00", "2026-10-21T00:00:00+02:00"},
+	}
+	for spec, want := range tests {
+		from, to, err := ParseAgendaRange(spec, now, berlin)
+		require.NoError(t, err, spec)
+		assert.Equal(t, want[0], from.Format(time.RFC3339), spec)
+		assert.Equal(t, want[1], to.Format(time.RFC3339), spec)
+	}
+
+	for _, spec := range []string{"fortnight", "0 days", "90 days"} {
+		_, _, err := ParseAgendaRange(spec, now, berlin)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestBuildAgenda(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	from := time.Date(2026, 10, 19, 0, 0, 0, 0, berlin)
+
+	timed := func(id, title, start, end string) *calendar.Event {
+		return &calendar.Event{
+			Id: id, Summary: title, Status: "confirmed",
+			Start: &calendar.EventDateTime{DateTime: start},
+			End:   &calendar.EventDateTime{DateTime: end},
+		}
+	}
+
+	standup := timed("standup", "Standup", "2026-10-19T07:00:00Z", "2026-10-19T07:15:00Z")
+	standup.ConferenceData = &calendar.ConferenceData{EntryPoints: []*calendar.EntryPoint{
+		{EntryPointType: "phone", Uri: "tel:+1-555"},
+		{EntryPointType: "video", Uri: "https://meet.google.com/abc-defg-hij"},
+	}}
+	review := timed("review", "Review", "2026-10-19T11:00:00+02:00", "2026-10-19T12:00:00+02:00")
+	review.Attendees = []*calendar.EventAttendee{{Email: "me@example.com", Self: true, ResponseStatus: "tentative"}}
+	overlap := timed("overlap", "Vendor call", "2026-10-19T11:30:00+02:00", "2026-10-19T12:30:00+02:00")
+	declined := timed("declined", "All hands", "2026-10-19T12:00:00+02:00", "2026-10-19T13:00:00+02:00")
+	declined.Attendees = []*calendar.EventAttendee{{Email: "me@example.com", Self: true, ResponseStatus: "declined"}}
+	offsite := &calendar.Event{
+		Id: "offsite", Summary: "Offsite", Status: "confirmed",
+		Start: &calendar.EventDateTime{Date: "2026-10-20"},
+		End:   &calendar.EventDateTime{Date: "2026-10-22"},
+	}
+	cancelled := timed("gone", "Gone", "2026-10-19T15:00:00Z", "2026-10-19T16:00:00Z")
+	cancelled.Status = "cancelled"
+
+	agenda := BuildAgenda([]*calendar.Event{overlap, review, declined, offsite, cancelled, standup}, from, from.AddDate(0, 0, 2), berlin)
+	assert.Equal(t, "Europe/Berlin", agenda.TimeZone)
+	require.Len(t, agenda.Days, 2)
+	assert.Equal(t, 5, agenda.Count)
+
+	monday := agenda.Days[0]
+	assert.Equal(t, "Mon, Oct 19", monday.Label)
+	require.Len(t, monday.Events, 4)
+	assert.Equal(t, "Standup", monday.Events[0].Title)
+	assert.Equal(t, "09:00", monday.Events[0].Start)
+	assert.Equal(t, "https://meet.google.com/abc-defg-hij", monday.Events[0].MeetLink)
+	assert.Equal(t, "tentative", monday.Events[1].RSVP)
+	assert.True(t, monday.Events[1].Conflict)
+	assert.True(t, monday.Events[2].Conflict)
+	assert.False(t, monday.Events[3].Conflict, "declined events do not conflict")
+	assert.Equal(t, []AgendaGap{{Start: "09:15", End: "11:00", Minutes: 105}}, monday.Gaps)
+
+	tuesday := agenda.Days[1]
+	require.Len(t, tuesday.Events, 1)
+	assert.True(t, tuesday.Events[0].AllDay)
+
+	md, err := agenda.Render(AgendaMarkdown)
+	require.NoError(t, err)
+	assert.Equal(t, `## Mon, Oct 19
+
+- **09:00-09:15** Standup [Meet](https://meet.google.com/abc-defg-hij)
+- _09:15-11:00 free (1h45m)_
+- **11:00-12:00** Review _(tentative, CONFLICT)_
+- **11:30-12:30** Vendor call _(CONFLICT)_
+- **12:00-13:00** All hands _(declined)_
+
+## Tue, Oct 20
+
+- **All day** Offsite
+`, md)
+
+	text, err := agenda.Render(AgendaText)
+	require.NoError(t, err)
+	assert.Contains(t, text, "Tue, Oct 20\n  All day      Offsite\n")
+	assert.Contains(t, text, "  09:15-11:00  free (1h45m)\n")
+
+	_, err = agenda.Render("html")
+	assert.Error(t, err)
+}
+
+func TestBuildAgenda_Overnight(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	from := time.Date(2026, 10, 19, 0, 0, 0, 0, berlin)
+
+	timed := func(id, title, start, end string) *calendar.Event {
+		return &calendar.Event{
+			Id: id, Summary: title, Status: "confirmed",
+			Start: &calendar.EventDateTime{DateTime: start},
+			End:   &calendar.EventDateTime{DateTime: end},
+		}
+	}
+
+	// Overnight events appear on every day they cover, clipped to the day
+	// for conflicts and gaps, even when they start before the range.
+	flight := timed("flight", "Flight", "2026-10-18T22:00:00+02:00", "2026-10-19T10:00:00+02:00")
+	late := timed("late", "Late call", "2026-10-19T09:30:00+02:00", "2026-10-19T10:00:00+02:00")
+	night := timed("night", "Night shift", "2026-10-19T22:00:00+02:00", "2026-10-20T08:00:00+02:00")
+	early := timed("early", "Early sync", "2026-10-20T09:00:00+02:00", "2026-10-20T09:30:00+02:00")
+	agenda := BuildAgenda([]*calendar.Event{flight, late, night, early}, from, from.AddDate(0, 0, 2), berlin)
+	assert.Equal(t, 5, agenda.Count)
+
+	monday := agenda.Days[0]
+	require.Len(t, monday.Events, 3)
+	assert.Equal(t, "Oct 18 22:00", monday.Events[0].Start)
+	assert.Equal(t, "10:00", monday.Events[0].End)
+	assert.True(t, monday.Events[0].Conflict)
+	assert.True(t, monday.Events[1].Conflict)
+	assert.Equal(t, "22:00", monday.Events[2].Start)
+	assert.Equal(t, "Oct 20 08:00", monday.Events[2].End)
+	assert.Equal(t, []AgendaGap{{Start: "10:00", End: "22:00", Minutes: 720}}, monday.Gaps)
+
+	tuesday := agenda.Days[1]
+	require.Len(t, tuesday.Events, 2)
+	assert.Equal(t, "Night shift", tuesday.Events[0].Title)
+	assert.Equal(t, "Oct 19 22:00", tuesday.Events[0].Start)
+	assert.False(t, tuesday.Events[0].Conflict)
+	assert.Equal(t, []AgendaGap{{Start: "08:00", End: "09:00", Minutes: 60}}, tuesday.Gaps)
+
+	text, err := agenda.Render(AgendaText)
+	require.NoError(t, err)
+	assert.Contains(t, text, "Mon, Oct 19\n  Oct 18 22:00-10:00  Flight (CONFLICT)\n  09:30-10:00  Late call (CONFLICT)\n  10:00-22:00  free (12h)\n")
+}
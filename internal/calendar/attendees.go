@@ -0,0 +1,269 @@
+package calendar
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+// ResponseSummary groups guests by response status. Rooms and other
+// resources are not included.
+type ResponseSummary struct {
+	Accepted    []string `json:"accepted"`
+	Declined    []string `json:"declined"`
+	Tentative   []string `json:"tentative"`
+	NeedsAction []string `json:"needs_action"`
+}
+
+// ResponseCounts counts guests by response status.
+type ResponseCounts struct {
+	Accepted    int `json:"accepted"`
+	Declined    int `json:"declined"`
+	Tentative   int `json:"tentative"`
+	NeedsAction int `json:"needs_action"`
+	Total       int `json:"total"`
+}
+
+// AttendeeList is the guest list of an event with a summary of responses.
+type AttendeeList struct {
+	EventID       string          `json:"event_id"`
+	Title         string          `json:"title"`
+	Start         string          `json:"start"`
+	End           string          `json:"end"`
+	HTMLLink      string          `json:"html_link"`
+	Organizer     string          `json:"organizer,omitempty"`
+	OrganizerSelf bool            `json:"organizer_self"` // You organize the event
+	Attendees     []AttendeeInfo  `json:"attendees"`
+	Responses     ResponseSummary `json:"responses"`
+	Counts        ResponseCounts  `json:"counts"`
+}
+
+// AttendeeUpdate is the result of adding or removing guests.
+type AttendeeUpdate struct {
+	Added     []string      `json:"added,omitempty"`
+	Removed   []string      `json:"removed,omitempty"`
+	Unchanged []string      `json:"unchanged,omitempty"` // Already invited, or not a guest
+	List      *AttendeeList `json:"event"`
+}
+
+// UpdateAttendeesOptions contains options for changing an event's guests.
+type UpdateAttendeesOptions struct {
+	CalendarID  string
+	EventID     string   // An occurrence ID changes only that occurrence
+	Add         []string // Emails to invite
+	Remove      []string // Emails to uninvite
+	Optional    bool     // Invite Add as optional guests
+	SendUpdates string   // all, externalOnly, none (default: all)
+	DryRun      bool     // Compute the change without updating the event
+}
+
+// Attendees returns the guests of an event and their responses.
+func (s *Service) Attendees(calendarID, eventID string) (*AttendeeList, error) {
+	if calendarID == "" {
+		calendarID = "primary"
+	}
+
+	event, err := s.svc.Events.Get(calendarID, eventID).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+
+	return summarizeAttendees(event), nil
+}
+
+// UpdateAttendees invites and uninvites guests of an existing event.
+// Emails already invited (or, when removing, not invited) are reported as
+// unchanged; the event is only updated if something changes. On a dry run
+// the returned list shows the guests as they would be.
+func (s *Service) UpdateAttendees(opts UpdateAttendeesOptions) (*AttendeeUpdate, error) {
+	calendarID := opts.CalendarID
+	if calendarID == "" {
+		calendarID = "primary"
+	}
+	sendUpdates := opts.SendUpdates
+	if sendUpdates == "" {
+		sendUpdates = "all"
+	}
+
+	event, err := s.svc.Events.Get(calendarID, opts.EventID).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+
+	attendees, update := changeAttendees(event.Attendees, opts.Add, opts.Remove, opts.Optional)
+	if opts.DryRun || (len(update.Added) == 0 && len(update.Removed) == 0) {
+		event.Attendees = attendees
+		update.List = summarizeAttendees(event)
+		return update, nil
+	}
+
+	// Attendees is sent even when empty, so removing the last guest works.
+	patch := &calendar.Event{Attendees: attendees, ForceSendFields: []string{"Attendees"}}
+	updated, err := s.svc.Events.Patch(calendarID, opts.EventID, patch).SendUpdates(sendUpdates).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to update attendees: %w", err)
+	}
+
+	update.List = summarizeAttendees(updated)
+	return update, nil
+}
+
+// changeAttendees returns the attendee list with add invited and remove
+// uninvited, matching emails case-insensitively.
+func changeAttendees(current []*calendar.EventAttendee, add, remove []string, optional bool) ([]*calendar.EventAttendee, *AttendeeUpdate) {
+	update := &AttendeeUpdate{}
+
+	removeSet := map[string]bool{}
+	for _, email := range remove {
+		if email = normalizeEmail(email); email != "" {
+			removeSet[email] = true
+		}
+	}
+
+	attendees := []*calendar.EventAttendee{}
+	invited := map[string]bool{}
+	for _, a := range current {
+		email := normalizeEmail(a.Email)
+		if removeSet[email] {
+			update.Removed = append(update.Removed, a.Email)
+			delete(removeSet, email)
+			continue
+		}
+		invited[email] = true
+		attendees = append(attendees, a)
+	}
+	for _, email := range remove {
+		if removeSet[normalizeEmail(email)] {
+			update.Unchanged = append(update.Unchanged, strings.TrimSpace(email))
+			delete(removeSet, normalizeEmail(email))
+		}
+	}
+
+	for _, email := range add {
+		email = strings.TrimSpace(email)
+		key := normalizeEmail(email)
+		if key == "" {
+			continue
+		}
+		if invited[key] {
+			update.Unchanged = append(update.Unchanged, email)
+			continue
+		}
+		invited[key] = true
+		attendees = append(attendees, &calendar.EventAttendee{Email: email, Optional: optional})
+		update.Added = append(update.Added, email)
+	}
+
+	return attendees, update
+}
+
+// summarizeAttendees lists an event's guests and groups them by response.
+func summarizeAttendees(e *calendar.Event) *AttendeeList {
+	full := parseEventToFull(e)
+	list := &AttendeeList{
+		EventID:   full.ID,
+		Title:     full.Title,
+		Start:     full.Start,
+		End:       full.End,
+		HTMLLink:  full.HTMLLink,
+		Organizer: full.Organizer,
+		Attendees: full.Attendees,
+		Responses: ResponseSummary{
+			Accepted:    []string{},
+			Declined:    []string{},
+			Tentative:   []string{},
+			NeedsAction: []string{},
+		},
+	}
+	if list.Attendees == nil {
+		list.Attendees = []AttendeeInfo{}
+	}
+	if e.Organizer != nil {
+		list.OrganizerSelf = e.Organizer.Self
+	}
+
+	for _, a := range full.Attendees {
+		if a.Resource {
+			continue
+		}
+		switch a.ResponseStatus {
+		case "accepted":
+			list.Responses.Accepted = append(list.Responses.Accepted, a.Email)
+		case "declined":
+			list.Responses.Declined = append(list.Responses.Declined, a.Email)
+		case "tentative":
+			list.Responses.Tentative = append(list.Responses.Tentative, a.Email)
+		default:
+			list.Responses.NeedsAction = append(list.Responses.NeedsAction, a.Email)
+		}
+	}
+
+	list.Counts = ResponseCounts{
+		Accepted:    len(list.Responses.Accepted),
+		Declined:    len(list.Responses.Declined),
+		Tentative:   len(list.Responses.Tentative),
+		NeedsAction: len(list.Responses.NeedsAction),
+	}
+	list.Counts.Total = list.Counts.Accepted + list.Counts.Declined + list.Counts.Tentative + list.Counts.NeedsAction
+	return list
+}
+
+// NonResponders returns the guests who have not responded, and with
+// tentative also those who answered maybe. You, the organizer and
+// resources are never included.
+func (l *AttendeeList) NonResponders(tentative bool) []string {
+	emails := []string{}
+	for _, a := range l.Attendees {
+		if a.Self || a.Organizer || a.Resource {
+			continue
+		}
+		if a.ResponseStatus == "needsAction" || a.ResponseStatus == "" || (tentative && a.ResponseStatus == "tentative") {
+			emails = append(emails, a.Email)
+		}
+	}
+	sort.Strings(emails)
+	return emails
+}
+
+// NudgeMessage returns the subject and plain-text body of a reminder to
+// respond to the event, with an optional personal note. Line breaks in the
+// event title become spaces so the subject stays a single header line.
+func (l *AttendeeList) NudgeMessage(note string) (subject, body string) {
+	title := strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ").Replace(l.Title)
+	if title == "" {
+		title = "(no title)"
+	}
+	subject = "Reminder: please respond to " + title
+
+	var b strings.Builder
+	b.WriteString("Hi,\n\n")
+	if note = strings.TrimSpace(note); note != "" {
+		b.WriteString(note + "\n\n")
+	}
+	fmt.Fprintf(&b, "You're invited to %q on %s, and I haven't seen your response yet. ", title, formatEventTime(l.Start))
+	b.WriteString("Could you accept or decline the invitation so I can plan accordingly?\n")
+	if l.HTMLLink != "" {
+		fmt.Fprintf(&b, "\n%s\n", l.HTMLLink)
+	}
+	b.WriteString("\nThanks!\n")
+	return subject, b.String()
+}
+
+// formatEventTime formats an event start as "Tue, Oct 20 2026 at 15:00
+// (UTC+02:00)" or, for all-day events, "Tue, Oct 20 2026".
+func formatEventTime(start string) string {
+	if t, err := time.Parse(time.RFC3339, start); err == nil {
+		return t.Format("Mon, Jan 2 2006 at 15:04 (UTC-07:00)")
+	}
+	if t, err := time.Parse("2006-01-02", start); err == nil {
+		return t.Format("Mon, Jan 2 2006")
+	}
+	return start
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,84 @@
+package calendar
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/api/calendar/v3"
+)
+
+func TestChangeAttendees(t *testing.T) {
+	current := []*calendar.EventAttendee{
+		{Email: "me@example.com", Self: true, Organizer: true, ResponseStatus: "accepted"},
+		{Email: "Alice@example.com", ResponseStatus: "accepted"},
+		{Email: "bob@example.com", ResponseStatus: "needsAction"},
+	}
+
+	attendees, update := changeAttendees(current, []string{"carol@example.com", " alice@example.com", ""}, []string{"BOB@example.com", "dave@example.com"}, true)
+
+	assert.Equal(t, []string{"carol@example.com"}, update.Added)
+	assert.Equal(t, []string{"bob@example.com"}, update.Removed)
+	assert.Equal(t, []string{"dave@example.com", "alice@example.com"}, update.Unchanged)
+
+	var emails []string
+	for _, a := range attendees {
+		emails = append(emails, a.Email)
+	}
+	assert.Equal(t, []string{"me@example.com", "Alice@example.com", "carol@example.com"}, emails)
+	assert.True(t, attendees[2].Optional)
+	assert.Equal(t, "accepted", attendees[1].ResponseStatus, "existing guests keep their response")
+
+	attendees, update = changeAttendees(current[:1], nil, []string{"me@example.com"}, false)
+	assert.Empty(t, attendees)
+	assert.NotNil(t, attendees)
+	assert.Equal(t, []string{"me@example.com"}, update.Removed)
+}
+
+func TestSummarizeAttendees(t *testing.T) {
+	event := &calendar.Event{
+		Id: "abc", Summary: "Design review", HtmlLink: "https://calendar.google.com/event?eid=abc",
+		Start:     &calendar.EventDateTime{DateTime: "2026-10-20T15:00:00+02:00"},
+		End:       &calendar.EventDateTime{DateTime: "2026-10-20T16:00:00+02:00"},
+		Organizer: &calendar.EventOrganizer{Email: "me@example.com", Self: true},
+		Attendees: []*calendar.EventAttendee{
+			{Email: "me@example.com", Self: true, Organizer: true, ResponseStatus: "accepted"},
+			{Email: "alice@example.com", ResponseStatus: "declined"},
+			{Email: "bob@example.com", ResponseStatus: "tentative"},
+			{Email: "carol@example.com", ResponseStatus: "needsAction", Optional: true},
+			{Email: "dave@example.com", ResponseStatus: "needsAction"},
+			{Email: "room@resource.calendar.google.com", Resource: true, ResponseStatus: "needsAction"},
+		},
+	}
+
+	list := summarizeAttendees(event)
+	assert.Equal(t, ResponseSummary{
+		Accepted:    []string{"me@example.com"},
+		Declined:    []string{"alice@example.com"},
+		Tentative:   []string{"bob@example.com"},
+		NeedsAction: []string{"carol@example.com", "dave@example.com"},
+	}, list.Responses)
+	assert.Equal(t, ResponseCounts{Accepted: 1, Declined: 1, Tentative: 1, NeedsAction: 2, Total: 5}, list.Counts)
+	assert.Len(t, list.Attendees, 6)
+	assert.True(t, list.Attendees[3].Optional)
+	assert.True(t, list.OrganizerSelf)
+
+	assert.Equal(t, []string{"carol@example.com", "dave@example.com"}, list.NonResponders(false))
+	assert.Equal(t, []string{"bob@example.com", "carol@example.com", "dave@example.com"}, list.NonResponders(true))
+
+	subject, body := list.NudgeMessage("We need a decision on the API.")
+	assert.Equal(t, "Reminder: please respond to Design review", subject)
+	assert.Contains(t, body, "We need a decision on the API.\n")
+	assert.Contains(t, body, `"Design review" on Tue, Oct 20 2026 at 15:00 (UTC+02:00)`)
+	assert.Contains(t, body, "https://calendar.google.com/event?eid=abc")
+
+	empty := summarizeAttendees(&calendar.Event{Id: "solo", Start: &calendar.EventDateTime{Date: "2026-10-20"}, End: &calendar.EventDateTime{Date: "2026-10-21"}})
+	assert.NotNil(t, empty.Attendees)
+	assert.False(t, empty.OrganizerSelf)
+	assert.Empty(t, empty.NonResponders(true))
+	_, body = empty.NudgeMessage("")
+	assert.Contains(t, body, `"(no title)" on Tue, Oct 20 2026`)
+
+	multiline := &AttendeeList{Title: "Sync\r\nBcc: victim@example.com", Start: "2026-10-20"}
+	subject, _ = multiline.NudgeMessage("")
+	assert.Equal(t, "Reminder: please respond to Sync Bcc: victim@example.com", subject)
+}
@@ -0,0 +1,262 @@
+package calendar
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/googleapi"
+)
+
+// DefaultFullSyncLimit is the number of upcoming events returned when the
+// sync token is missing or has expired.
+const DefaultFullSyncLimit = 500
+
+// CancelledEvent is an event, or an occurrence of a recurring event, that
+// was deleted. Cancelled occurrences carry their series ID and original
+// start time.
+type CancelledEvent struct {
+	ID            string `json:"id"`
+	RecurringID   string `json:"recurring_event_id,omitempty"`
+	OriginalStart string `json:"original_start,omitempty"`
+}
+
+// ChangesResult represents the calendar changes since a sync token.
+//
+// Events are not expanded: a new or edited series appears once with its
+// first start time, and changed or deleted occurrences appear with their
+// recurring_event_id.
+type ChangesResult struct {
+	CalendarID     string           `json:"calendar_id"`
+	SyncToken      string           `json:"sync_token"`
+	Created        []EventSummary   `json:"created"`
+	Updated        []EventSummary   `json:"updated"`
+	Cancelled      []CancelledEvent `json:"cancelled"`
+	FullSync       bool             `json:"full_sync"`
+	FullSyncReason string           `json:"full_sync_reason,omitempty"`
+	Events         []EventSummary   `json:"events,omitempty"`
+	Truncated      bool             `json:"truncated,omitempty"`
+}
+
+// SyncState is the cursor persisted between runs of a change feed.
+type SyncState struct {
+	CalendarID string `json:"calendar_id"`
+	SyncToken  string `json:"sync_token"`
+	UpdatedAt  string `json:"updated_at"`
+}
+
+// Changes returns the events created, updated and cancelled since
+// syncToken. Events created after since are reported as created; with a
+// zero since, events whose creation and last update coincide are. If
+// syncToken is empty or has expired, it falls back to a full sync that
+// returns up to fullSyncLimit upcoming events and a new sync token.
+func (s *Service) Changes(calendarID, syncToken string, since time.Time, fullSyncLimit int) (*ChangesResult, error) {
+	if calendarID == "" {
+		calendarID = "primary"
+	}
+	if syncToken == "" {
+		return s.fullCalendarSync(calendarID, fullSyncLimit, "no sync token provided")
+	}
+
+	var items []*calendar.Event
+	pageToken := ""
+	for {
+		call := s.svc.Events.List(calendarID).SyncToken(syncToken).MaxResults(250)
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+
+		resp, err := call.Do()
+		if err != nil {
+			if isSyncTokenExpired(err) {
+				return s.fullCalendarSync(calendarID, fullSyncLimit, "sync token expired")
+			}
+			return nil, fmt.Errorf("failed to list changes: %w", err)
+		}
+
+		items = append(items, resp.Items...)
+		if resp.NextPageToken == "" {
+			result := collectChanges(items, since)
+			result.CalendarID = calendarID
+			result.SyncToken = resp.NextSyncToken
+			return result, nil
+		}
+		pageToken = resp.NextPageToken
+	}
+}
+
+// fullCalendarSync returns up to limit upcoming occurrences, in start
+// order, and a new sync token. The API only issues a sync token at the end
+// of an unfiltered listing, so that walk still covers the whole calendar,
+// but it requests only the page and sync tokens.
+func (s *Service) fullCalendarSync(calendarID string, limit int, reason string) (*ChangesResult, error) {
+	if limit <= 0 {
+		limit = DefaultFullSyncLimit
+	}
+
+	result := &ChangesResult{
+		CalendarID:     calendarID,
+		Created:        []EventSummary{},
+		Updated:        []EventSummary{},
+		Cancelled:      []CancelledEvent{},
+		FullSync:       true,
+		FullSyncReason: reason,
+		Events:         []EventSummary{},
+	}
+
+	pageToken := ""
+	for len(result.Events) < limit {
+		call := s.svc.Events.List(calendarID).
+			TimeMin(time.Now().Format(time.RFC3339)).
+			SingleEvents(true).
+			OrderBy("startTime").
+			MaxResults(int64(min(limit-len(result.Events), 2500)))
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+
+		resp, err := call.Do()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list events: %w", err)
+		}
+		for _, item := range resp.Items {
+			result.Events = append(result.Events, parseEventToSummary(item))
+		}
+
+		if resp.NextPageToken == "" {
+			break
+		}
+		pageToken = resp.NextPageToken
+		result.Truncated = len(result.Events) >= limit
+	}
+
+	token, err := s.syncToken(calendarID)
+	if err != nil {
+		return nil, err
+	}
+	result.SyncToken = token
+	return result, nil
+}
+
+// syncToken pages through the calendar's events, fetching only page
+// tokens, to obtain a sync token for the current state.
+func (s *Service) syncToken(calendarID string) (string, error) {
+	pageToken := ""
+	for {
+		call := s.svc.Events.List(calendarID).
+			MaxResults(2500).
+			Fields("nextPageToken", "nextSyncToken")
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+
+		resp, err := call.Do()
+		if err != nil {
+			return "", fmt.Errorf("failed to list events: %w", err)
+		}
+		if resp.NextPageToken == "" {
+			return resp.NextSyncToken, nil
+		}
+		pageToken = resp.NextPageToken
+	}
+}
+
+// collectChanges sorts changed events into created, updated and cancelled.
+func collectChanges(items []*calendar.Event, since time.Time) *ChangesResult {
+	result := &ChangesResult{
+		Created:   []EventSummary{},
+		Updated:   []EventSummary{},
+		Cancelled: []CancelledEvent{},
+	}
+
+	for _, item := range items {
+		if item.Status == "cancelled" {
+			cancelled := CancelledEvent{ID: item.Id, RecurringID: item.RecurringEventId}
+			if ost := item.OriginalStartTime; ost != nil {
+				cancelled.OriginalStart = ost.DateTime
+				if ost.Date != "" {
+					cancelled.OriginalStart = ost.Date
+				}
+			}
+			result.Cancelled = append(result.Cancelled, cancelled)
+			continue
+		}
+
+		if isNewEvent(item, since) {
+			result.Created = append(result.Created, parseEventToSummary(item))
+		} else {
+			result.Updated = append(result.Updated, parseEventToSummary(item))
+		}
+	}
+
+	return result
+}
+
+// isNewEvent reports whether an event was created since the last sync.
+// Changed occurrences of a recurring event are updates to the series.
+func isNewEvent(e *calendar.Event, since time.Time) bool {
+	if e.RecurringEventId != "" {
+		return false
+	}
+	created, err := time.Parse(time.RFC3339, e.Created)
+	if err != nil {
+		return false
+	}
+	if !since.IsZero() {
+		return !created.Before(since)
+	}
+	updated, err := time.Parse(time.RFC3339, e.Updated)
+	return err == nil && updated.Sub(created) < time.Second
+}
+
+// isSyncTokenExpired reports whether err indicates the sync token is no
+// longer valid and a full sync is required.
+func isSyncTokenExpired(err error) bool {
+	var apiErr *googleapi.Error
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusGone
+}
+
+// LoadSyncState reads a sync cursor from path. A missing file returns a
+// zero state without error.
+func LoadSyncState(path string) (*SyncState, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &SyncState{}, nil
+		}
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	var state SyncState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse state file: %w", err)
+	}
+	return &state, nil
+}
+
+// SaveSyncState writes a sync cursor to path, recording now as the sync
+// time.
+func SaveSyncState(path, calendarID, syncToken string, now time.Time) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(SyncState{
+		CalendarID: calendarID,
+		SyncToken:  syncToken,
+		UpdatedAt:  now.UTC().Format(time.RFC3339),
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	return nil
+}
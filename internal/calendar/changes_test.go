@@ -0,0 +1,137 @@
+package calendar
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+)
+
+func TestCollectChanges(t *testing.T) {
+	since := time.Date(2026, 10, 18, 8, 0, 0, 0, time.UTC)
+	items := []*calendar.Event{
+		{
+			Id: "new", Summary: "Planning", Status: "confirmed",
+			Created: "2026-10-18T09:00:00.000Z", Updated: "2026-10-18T09:30:00.000Z",
+			Start: &calendar.EventDateTime{DateTime: "2026-10-20T14:00:00Z"},
+			End:   &calendar.EventDateTime{DateTime: "2026-10-20T15:00:00Z"},
+		},
+		{
+			Id: "old", Summary: "1:1", Status: "confirmed",
+			Created: "2026-09-01T09:00:00.000Z", Updated: "2026-10-18T09:00:00.000Z",
+			Start: &calendar.EventDateTime{DateTime: "2026-10-21T10:00:00Z"},
+			End:   &calendar.EventDateTime{DateTime: "2026-10-21T10:30:00Z"},
+		},
+		{
+			// Moved occurrence of a series created today.
+			Id: "series_20261022", RecurringEventId: "series", Status: "confirmed",
+			Created: "2026-10-18T09:00:00.000Z", Updated: "2026-10-18T10:00:00.000Z",
+			Start: &calendar.EventDateTime{DateTime: "2026-10-22T11:00:00Z"},
+			End:   &calendar.EventDateTime{DateTime: "2026-10-22T11:15:00Z"},
+		},
+		{Id: "gone", Status: "cancelled"},
+		{
+			Id: "series_20261023", RecurringEventId: "series", Status: "cancelled",
+			OriginalStartTime: &calendar.EventDateTime{DateTime: "2026-10-23T09:00:00Z"},
+		},
+	}
+
+	result := collectChanges(items, since)
+	require.Len(t, result.Created, 1)
+	assert.Equal(t, "new", result.Created[0].ID)
+	assert.Equal(t, "2026-10-20T14:00:00Z", result.Created[0].Start)
+	require.Len(t, result.Updated, 2)
+	assert.Equal(t, "old", result.Updated[0].ID)
+	assert.Equal(t, "series", result.Updated[1].RecurringID)
+	assert.Equal(t, []CancelledEvent{
+		{ID: "gone"},
+		{ID: "series_20261023", RecurringID: "series", OriginalStart: "2026-10-23T09:00:00Z"},
+	}, result.Cancelled)
+
+	// Without a previous sync time, unedited events count as created.
+	result = collectChanges(items[:2], time.Time{})
+	assert.Empty(t, result.Created)
+	result = collectChanges([]*calendar.Event{{
+		Id: "fresh", Created: "2026-10-18T09:00:00.000Z", Updated: "2026-10-18T09:00:00.200Z",
+	}}, time.Time{})
+	assert.Len(t, result.Created, 1)
+}
+
+func TestFullCalendarSync(t *testing.T) {
+	var walked []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("timeMin") != "" {
+			// Upcoming occurrences, in start order.
+			assert.Equal(t, "true", q.Get("singleEvents"))
+			assert.Equal(t, "startTime", q.Get("orderBy"))
+			assert.Equal(t, "2", q.Get("maxResults"))
+			json.NewEncoder(w).Encode(calendar.Events{
+				Items: []*calendar.Event{
+					{Id: "next", Start: &calendar.EventDateTime{DateTime: "2026-10-19T09:00:00Z"}, End: &calendar.EventDateTime{DateTime: "2026-10-19T10:00:00Z"}},
+					{Id: "later", Start: &calendar.EventDateTime{DateTime: "2026-10-20T09:00:00Z"}, End: &calendar.EventDateTime{DateTime: "2026-10-20T10:00:00Z"}},
+				},
+				NextPageToken: "more",
+			})
+			return
+		}
+
+		// Token walk: unfiltered, and only the tokens are requested.
+		assert.Contains(t, q.Get("fields"), "nextSyncToken")
+		walked = append(walked, q.Get("pageToken"))
+		if q.Get("pageToken") == "" {
+			json.NewEncoder(w).Encode(calendar.Events{NextPageToken: "p2"})
+			return
+		}
+		json.NewEncoder(w).Encode(calendar.Events{NextSyncToken: "sync-2"})
+	}))
+	defer server.Close()
+
+	api, err := calendar.NewService(context.Background(),
+		option.WithEndpoint(server.URL), option.WithHTTPClient(server.Client()))
+	require.NoError(t, err)
+	svc := &Service{svc: api}
+
+	result, err := svc.fullCalendarSync("primary", 2, "sync token expired")
+	require.NoError(t, err)
+	assert.True(t, result.FullSync)
+	assert.Equal(t, "sync-2", result.SyncToken)
+	assert.Equal(t, []string{"", "p2"}, walked)
+	require.Len(t, result.Events, 2)
+	assert.Equal(t, "next", result.Events[0].ID)
+	assert.True(t, result.Truncated)
+}
+
+func TestIsSyncTokenExpired(t *testing.T) {
+	assert.True(t, isSyncTokenExpired(fmt.Errorf("wrapped: %w", &googleapi.Error{Code: 410})))
+	assert.False(t, isSyncTokenExpired(&googleapi.Error{Code: 404}))
+	assert.False(t, isSyncTokenExpired(errors.New("boom")))
+}
+
+func TestSyncStateRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "calendar.json")
+
+	state, err := LoadSyncState(path)
+	require.NoError(t, err)
+	assert.Empty(t, state.SyncToken)
+
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, SaveSyncState(path, "primary", "tok123", now))
+
+	state, err = LoadSyncState(path)
+	require.NoError(t, err)
+	assert.Equal(t, "primary", state.CalendarID)
+	assert.Equal(t, "tok123", state.SyncToken)
+	assert.Equal(t, "2026-10-18T12:00:00Z", state.UpdatedAt)
+}
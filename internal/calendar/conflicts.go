@@ -0,0 +1,272 @@
+package calendar
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+// maxSuggestions is the number of free slots suggested for a conflict.
+const maxSuggestions = 3
+
+// suggestionWindow is how far around a conflicting time free slots are
+// searched.
+const suggestionWindow = 24 * time.Hour
+
+// Conflict is busy time overlapping a proposed event. Events on the target
+// calendar include their ID and title; attendees only expose busy blocks.
+type Conflict struct {
+	Calendar string `json:"calendar"`
+	EventID  string `json:"event_id,omitempty"`
+	Summary  string `json:"summary,omitempty"`
+	Start    string `json:"start"`
+	End      string `json:"end"`
+}
+
+// seriesCheckNote explains that a recurring event's other occurrences were
+// not checked for conflicts.
+const seriesCheckNote = "only this occurrence of the recurring event was checked; later occurrences may still conflict"
+
+// ConflictError is returned by Create and Reschedule when the new time
+// overlaps busy time. Suggestions are the nearest free slots of the same
+// length. For recurring events only one occurrence is checked, which Note
+// points out.
+type ConflictError struct {
+	Start       string         `json:"start"`
+	End         string         `json:"end"`
+	Conflicts   []Conflict     `json:"conflicts"`
+	Suggestions []FreeBusySlot `json:"suggestions"`
+	Note        string         `json:"note,omitempty"`
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%s - %s conflicts with %d busy slot(s); pick a suggested slot or allow conflicts", e.Start, e.End, len(e.Conflicts))
+}
+
+// conflictCheck describes a proposed event time to check.
+type conflictCheck struct {
+	calendarID string
+	attendees  []string // Also checked through FreeBusy
+	start, end time.Time
+	ignore     []string // IDs of the event (or series) being moved
+	// Current time of the event being moved, ignored in attendees' busy
+	// time since FreeBusy cannot tell it apart.
+	ignoreStart, ignoreEnd time.Time
+	recurring              bool // Only one occurrence of a series is checked
+}
+
+// checkConflicts returns a *ConflictError if the proposed time overlaps
+// busy time on the calendar or, if given, the attendees' calendars.
+func (s *Service) checkConflicts(c conflictCheck) error {
+	loc := c.start.Location()
+	from := c.start.Add(-suggestionWindow)
+	if now := time.Now(); from.Before(now) && now.Before(c.start) {
+		from = now
+	}
+	to := c.end.Add(suggestionWindow)
+
+	var busy []interval
+	var conflicts []Conflict
+
+	events, err := s.listRange(c.calendarID, from, to)
+	if err != nil {
+		return err
+	}
+	for _, e := range events {
+		if !blocksTime(e, c.ignore) {
+			continue
+		}
+		start, end, ok := eventInterval(e, loc)
+		if !ok {
+			continue
+		}
+		busy = append(busy, interval{start, end})
+		if start.Before(c.end) && c.start.Before(end) {
+			conflicts = append(conflicts, Conflict{
+				Calendar: c.calendarID,
+				EventID:  e.Id,
+				Summary:  e.Summary,
+				Start:    start.In(loc).Format(time.RFC3339),
+				End:      end.In(loc).Format(time.RFC3339),
+			})
+		}
+	}
+
+	if len(c.attendees) > 0 {
+		items := make([]*calendar.FreeBusyRequestItem, 0, len(c.attendees))
+		for _, email := range c.attendees {
+			items = append(items, &calendar.FreeBusyRequestItem{Id: email})
+		}
+		resp, err := s.svc.Freebusy.Query(&calendar.FreeBusyRequest{
+			TimeMin: from.Format(time.RFC3339),
+			TimeMax: to.Format(time.RFC3339),
+			Items:   items,
+		}).Do()
+		if err != nil {
+			return fmt.Errorf("failed to get free/busy info: %w", err)
+		}
+
+		for _, email := range c.attendees {
+			cal, ok := resp.Calendars[email]
+			if !ok || len(cal.Errors) > 0 {
+				// Unknown availability is not a conflict.
+				continue
+			}
+			for _, b := range cal.Busy {
+				start, err1 := time.Parse(time.RFC3339, b.Start)
+				end, err2 := time.Parse(time.RFC3339, b.End)
+				if err1 != nil || err2 != nil {
+					continue
+				}
+				for _, part := range subtractInterval(interval{start, end}, interval{c.ignoreStart, c.ignoreEnd}) {
+					busy = append(busy, part)
+					if part.start.Before(c.end) && c.start.Before(part.end) {
+						conflicts = append(conflicts, Conflict{
+							Calendar: email,
+							Start:    part.start.In(loc).Format(time.RFC3339),
+							End:      part.end.In(loc).Format(time.RFC3339),
+						})
+					}
+				}
+			}
+		}
+	}
+
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	conflict := &ConflictError{
+		Start:       c.start.Format(time.RFC3339),
+		End:         c.end.Format(time.RFC3339),
+		Conflicts:   conflicts,
+		Suggestions: nearestSlots(from, to, busy, c.start, c.end.Sub(c.start), maxSuggestions),
+	}
+	if c.recurring {
+		conflict.Note = seriesCheckNote
+	}
+	return conflict
+}
+
+// listRange returns the single events of a calendar overlapping a range.
+func (s *Service) listRange(calendarID string, from, to time.Time) ([]*calendar.Event, error) {
+	var events []*calendar.Event
+	pageToken := ""
+	for {
+		call := s.svc.Events.List(calendarID).
+			TimeMin(from.Format(time.RFC3339)).
+			TimeMax(to.Format(time.RFC3339)).
+			SingleEvents(true).
+			MaxResults(250)
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+		resp, err := call.Do()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list events: %w", err)
+		}
+		events = append(events, resp.Items...)
+		if resp.NextPageToken == "" {
+			return events, nil
+		}
+		pageToken = resp.NextPageToken
+	}
+}
+
+// blocksTime reports whether an event makes its time busy: it is not
+// cancelled, shown as free, declined by you or one of the ignored events.
+func blocksTime(e *calendar.Event, ignore []string) bool {
+	if e.Status == "cancelled" || e.Transparency == "transparent" || e.EventType == "workingLocation" {
+		return false
+	}
+	for _, id := range ignore {
+		if id != "" && (e.Id == id || e.RecurringEventId == id) {
+			return false
+		}
+	}
+	for _, a := range e.Attendees {
+		if a.Self && a.ResponseStatus == "declined" {
+			return false
+		}
+	}
+	return true
+}
+
+// eventInterval returns the start and end of an event. All-day dates are
+// taken in loc.
+func eventInterval(e *calendar.Event, loc *time.Location) (time.Time, time.Time, bool) {
+	if e.Start == nil || e.End == nil {
+		return time.Time{}, time.Time{}, false
+	}
+	if e.Start.Date != "" {
+		start, err1 := time.ParseInLocation("2006-01-02", e.Start.Date, loc)
+		end, err2 := time.ParseInLocation("2006-01-02", e.End.Date, loc)
+		return start, end, err1 == nil && err2 == nil
+	}
+	start, err1 := time.Parse(time.RFC3339, e.Start.DateTime)
+	end, err2 := time.Parse(time.RFC3339, e.End.DateTime)
+	return start, end, err1 == nil && err2 == nil
+}
+
+// subtractInterval returns the parts of b outside cut.
+func subtractInterval(b, cut interval) []interval {
+	if cut.start.IsZero() || !cut.start.Before(b.end) || !b.start.Before(cut.end) {
+		return []interval{b}
+	}
+	var parts []interval
+	if b.start.Before(cut.start) {
+		parts = append(parts, interval{b.start, cut.start})
+	}
+	if cut.end.Before(b.end) {
+		parts = append(parts, interval{cut.end, b.end})
+	}
+	return parts
+}
+
+// nearestSlots returns up to n free slots of length d between from and to,
+// ordered by distance from start, earlier first on ties. Each free gap
+// yields the placement closest to start.
+func nearestSlots(from, to time.Time, busy []interval, start time.Time, d time.Duration, n int) []FreeBusySlot {
+	sort.Slice(busy, func(i, j int) bool { return busy[i].start.Before(busy[j].start) })
+	slots := make([]FreeBusySlot, 0, len(busy))
+	for _, b := range busy {
+		slots = append(slots, FreeBusySlot{Start: b.start.Format(time.RFC3339), End: b.end.Format(time.RFC3339)})
+	}
+
+	type candidate struct {
+		start    time.Time
+		distance time.Duration
+	}
+	var candidates []candidate
+	for _, free := range calculateFreeSlots(from, to, slots) {
+		freeStart, _ := time.Parse(time.RFC3339, free.Start)
+		freeEnd, _ := time.Parse(time.RFC3339, free.End)
+		if freeEnd.Sub(freeStart) < d {
+			continue
+		}
+		t := start
+		if t.Before(freeStart) {
+			t = freeStart
+		}
+		if latest := freeEnd.Add(-d); t.After(latest) {
+			t = latest
+		}
+		distance := t.Sub(start)
+		if distance < 0 {
+			distance = -distance
+		}
+		candidates = append(candidates, candidate{t, distance})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].distance < candidates[j].distance })
+	suggestions := []FreeBusySlot{}
+	for _, c := range candidates[:min(n, len(candidates))] {
+		suggestions = append(suggestions, FreeBusySlot{
+			Start: c.start.In(start.Location()).Format(time.RFC3339),
+			End:   c.start.Add(d).In(start.Location()).Format(time.RFC3339),
+		})
+	}
+	return suggestions
+}
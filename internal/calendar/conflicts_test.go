@@ -0,0 +1,57 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/api/calendar/v3"
+)
+
+func TestBlocksTime(t *testing.T) {
+	assert.True(t, blocksTime(&calendar.Event{Id: "a"}, nil))
+	assert.False(t, blocksTime(&calendar.Event{Id: "a", Status: "cancelled"}, nil))
+	assert.False(t, blocksTime(&calendar.Event{Id: "a", Transparency: "transparent"}, nil))
+	assert.False(t, blocksTime(&calendar.Event{Id: "a"}, []string{"a"}))
+	assert.False(t, blocksTime(&calendar.Event{Id: "a_1", RecurringEventId: "a"}, []string{"b", "a"}))
+	assert.False(t, blocksTime(&calendar.Event{Id: "a", Attendees: []*calendar.EventAttendee{
+		{Email: "me@example.com", Self: true, ResponseStatus: "declined"},
+	}}, nil))
+	assert.True(t, blocksTime(&calendar.Event{Id: "a", Attendees: []*calendar.EventAttendee{
+		{Email: "other@example.com", ResponseStatus: "declined"},
+	}}, nil))
+}
+
+func TestSubtractInterval(t *testing.T) {
+	at := func(h int) time.Time { return time.Date(2026, 10, 20, h, 0, 0, 0, time.UTC) }
+
+	assert.Equal(t, []interval{{at(9), at(10)}}, subtractInterval(interval{at(9), at(10)}, interval{}))
+	assert.Equal(t, []interval{{at(9), at(10)}}, subtractInterval(interval{at(9), at(10)}, interval{at(10), at(11)}))
+	assert.Empty(t, subtractInterval(interval{at(9), at(10)}, interval{at(9), at(10)}))
+	assert.Equal(t, []interval{{at(9), at(10)}, {at(11), at(12)}}, subtractInterval(interval{at(9), at(12)}, interval{at(10), at(11)}))
+}
+
+func TestNearestSlots(t *testing.T) {
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	at := func(h, m int) time.Time { return time.Date(2026, 10, 20, h, m, 0, 0, berlin) }
+
+	busy := []interval{
+		{at(13, 0), at(14, 0)},
+		{at(9, 0), at(10, 30)},
+		{at(14, 0), at(14, 30)},
+	}
+
+	// Wanted 13:30-14:00: the nearest 30 minute slots are right before and
+	// after the busy block (ties in time order), then before the morning
+	// meeting.
+	got := nearestSlots(at(8, 0), at(18, 0), busy, at(13, 30), 30*time.Minute, 3)
+	assert.Equal(t, []FreeBusySlot{
+		{Start: "2026-10-20T12:30:00+02:00", End: "2026-10-20T13:00:00+02:00"},
+		{Start: "2026-10-20T14:30:00+02:00", End: "2026-10-20T15:00:00+02:00"},
+		{Start: "2026-10-20T08:30:00+02:00", End: "2026-10-20T09:00:00+02:00"},
+	}, got)
+
+	// Gaps shorter than the meeting are skipped.
+	got = nearestSlots(at(9, 0), at(14, 30), busy, at(13, 0), 3*time.Hour, 3)
+	assert.Empty(t, got)
+}
@@ -0,0 +1,512 @@
+package calendar
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+// DefaultWorkingHours is used for anyone without configured working hours.
+const DefaultWorkingHours = "09:00-17:00 mon-fri lunch 12:00-13:00"
+
+// WorkingHours describes when a person is available for meetings. Times
+// are minutes after local midnight.
+type WorkingHours struct {
+	TimeZone   string  `json:"time_zone"`
+	Start      int     `json:"-"`
+	End        int     `json:"-"`
+	LunchStart int     `json:"-"`
+	LunchEnd   int     `json:"-"`
+	Days       [7]bool `json:"-"` // Indexed by time.Weekday
+	Spec       string  `json:"hours"`
+	loc        *time.Location
+}
+
+var dayIndex = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// ParseWorkingHours parses a spec such as "Europe/Berlin 09:00-17:00
+// mon-fri lunch 12:00-13:00". Omitted parts default to DefaultWorkingHours;
+// "lunch none" disables the lunch window. Without a zone, loc is used.
+func ParseWorkingHours(spec string, loc *time.Location) (WorkingHours, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+	wh := WorkingHours{Start: 9 * 60, End: 17 * 60, LunchStart: 12 * 60, LunchEnd: 13 * 60, Spec: spec, loc: loc}
+	for d := time.Monday; d <= time.Friday; d++ {
+		wh.Days[d] = true
+	}
+
+	tokens := strings.Fields(strings.ToLower(spec))
+	daysSet := false
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		switch {
+		case tok == "lunch":
+			i++
+			if i >= len(tokens) {
+				return wh, fmt.Errorf("missing lunch window in %q", spec)
+			}
+			if tokens[i] == "none" {
+				wh.LunchStart, wh.LunchEnd = 0, 0
+				continue
+			}
+			start, end, err := parseClockRange(tokens[i])
+			if err != nil {
+				return wh, err
+			}
+			wh.LunchStart, wh.LunchEnd = start, end
+		case strings.Contains(tok, ":"):
+			start, end, err := parseClockRange(tok)
+			if err != nil {
+				return wh, err
+			}
+			wh.Start, wh.End = start, end
+		case strings.Contains(tok, "/") || tok == "utc":
+			// Zone names are case-sensitive; take them from the original spec.
+			name := strings.Fields(spec)[i]
+			if tok == "utc" {
+				name = "UTC"
+			}
+			l, err := time.LoadLocation(name)
+			if err != nil {
+				return wh, fmt.Errorf("unknown time zone %q", name)
+			}
+			wh.loc = l
+		default:
+			days, err := parseDays(tok)
+			if err != nil {
+				return wh, fmt.Errorf("unexpected %q in working hours %q", tok, spec)
+			}
+			if !daysSet {
+				wh.Days = [7]bool{}
+				daysSet = true
+			}
+			for d, on := range days {
+				wh.Days[d] = wh.Days[d] || on
+			}
+		}
+	}
+
+	if wh.End <= wh.Start {
+		return wh, fmt.Errorf("working hours must end after they start in %q", spec)
+	}
+	wh.TimeZone = wh.loc.String()
+	if wh.Spec == "" {
+		wh.Spec = DefaultWorkingHours
+	}
+	return wh, nil
+}
+
+// parseClockRange parses "HH:MM-HH:MM" into minutes after midnight.
+func parseClockRange(s string) (int, int, error) {
+	from, to, ok := strings.Cut(s, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid time range %q (use HH:MM-HH:MM)", s)
+	}
+	start, err := parseClock(from)
+	if err != nil {
+		return 0, 0, err
+	}
+	end, err := parseClock(to)
+	if err != nil {
+		return 0, 0, err
+	}
+	if end <= start {
+		return 0, 0, fmt.Errorf("invalid time range %q", s)
+	}
+	return start, end, nil
+}
+
+func parseClock(s string) (int, error) {
+	h, m, ok := strings.Cut(s, ":")
+	hour, herr := strconv.Atoi(h)
+	minute, merr := strconv.Atoi(m)
+	if !ok || herr != nil || merr != nil || hour < 0 || hour > 24 || minute < 0 || minute > 59 || hour*60+minute > 24*60 {
+		return 0, fmt.Errorf("invalid time %q (use HH:MM)", s)
+	}
+	return hour*60 + minute, nil
+}
+
+// parseDays parses "mon-fri", "sun-thu" or "mon,wed,fri".
+func parseDays(s string) ([7]bool, error) {
+	var days [7]bool
+	for _, part := range strings.Split(s, ",") {
+		from, to, isRange := strings.Cut(part, "-")
+		start, ok := dayIndex[truncateDay(from)]
+		if !ok {
+			return days, fmt.Errorf("invalid day %q", from)
+		}
+		end := start
+		if isRange {
+			if end, ok = dayIndex[truncateDay(to)]; !ok {
+				return days, fmt.Errorf("invalid day %q", to)
+			}
+		}
+		for d := start; ; d = (d + 1) % 7 {
+			days[d] = true
+			if d == end {
+				break
+			}
+		}
+	}
+	return days, nil
+}
+
+func truncateDay(s string) string {
+	if len(s) > 3 {
+		return s[:3]
+	}
+	return s
+}
+
+// Location returns the time zone of the working hours.
+func (wh WorkingHours) Location() *time.Location {
+	if wh.loc == nil {
+		return time.Local
+	}
+	return wh.loc
+}
+
+// at returns the given minute of t's local day.
+func (wh WorkingHours) at(t time.Time, minutes int) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), minutes/60, minutes%60, 0, 0, wh.Location())
+}
+
+// comfort reports whether [start, end) lies within the working hours and
+// outside lunch, and how far (0-1) it is from the edges of the working day.
+func (wh WorkingHours) comfort(start, end time.Time) (float64, bool) {
+	local := start.In(wh.Location())
+	if !wh.Days[local.Weekday()] {
+		return 0, false
+	}
+	dayStart, dayEnd := wh.at(local, wh.Start), wh.at(local, wh.End)
+	if start.Before(dayStart) || end.After(dayEnd) {
+		return 0, false
+	}
+	if wh.LunchEnd > wh.LunchStart && start.Before(wh.at(local, wh.LunchEnd)) && end.After(wh.at(local, wh.LunchStart)) {
+		return 0, false
+	}
+
+	margin := min(start.Sub(dayStart), dayEnd.Sub(end))
+	return math.Min(margin.Hours()/2, 1), true
+}
+
+// interval is a time range.
+type interval struct {
+	start, end time.Time
+}
+
+// FindTimeParticipant is one person taking part in a meeting search.
+type FindTimeParticipant struct {
+	Email        string       `json:"email"`
+	Hours        WorkingHours `json:"working_hours"`
+	AssumedHours bool         `json:"assumed_hours,omitempty"`
+	Unavailable  string       `json:"free_busy_error,omitempty"`
+	busy         []interval
+}
+
+// FindTimeOptions contains options for finding meeting times.
+type FindTimeOptions struct {
+	Attendees []string
+	Duration  time.Duration
+	From      time.Time
+	To        time.Time
+	Buffer    time.Duration  // Free time required before and after the meeting
+	Step      time.Duration  // Granularity of candidate start times (default 15m)
+	Limit     int            // Maximum slots returned (default 10)
+	Location  *time.Location // Your time zone (default: the primary calendar's)
+	// Hours maps lowercased emails to working-hours specs; "default"
+	// applies to yourself and anyone without an entry.
+	Hours map[string]string
+}
+
+// MeetingSlot is a candidate meeting time.
+type MeetingSlot struct {
+	Start      string            `json:"start"`
+	End        string            `json:"end"`
+	Score      float64           `json:"score"`
+	LocalTimes map[string]string `json:"local_times"`
+}
+
+// FindTimeResult contains ranked meeting slots.
+type FindTimeResult struct {
+	From         string                `json:"from"`
+	To           string                `json:"to"`
+	Duration     string                `json:"duration"`
+	Participants []FindTimeParticipant `json:"participants"`
+	Slots        []MeetingSlot         `json:"slots"`
+}
+
+// maxSlotsPerDay keeps results spread over the search window.
+const maxSlotsPerDay = 3
+
+// FindTime finds meeting times when you and all attendees are free and
+// within everyone's working hours. Your time zone comes from your primary
+// calendar; others use the configured zone, else their calendar's zone if
+// it is visible to you, else your own. Attendees
+// whose free/busy cannot be read are flagged and only constrained by their
+// working hours.
+func (s *Service) FindTime(opts FindTimeOptions) (*FindTimeResult, error) {
+	if opts.Duration <= 0 {
+		return nil, fmt.Errorf("duration must be positive")
+	}
+	if !opts.To.After(opts.From) {
+		return nil, fmt.Errorf("search window is empty")
+	}
+
+	selfLoc := opts.Location
+	if selfLoc == nil {
+		var err error
+		if selfLoc, err = s.Location("primary"); err != nil {
+			return nil, err
+		}
+	}
+
+	participants, err := resolveParticipants(opts.Attendees, opts.Hours, selfLoc, s.attendeeLocation)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*calendar.FreeBusyRequestItem, 0, len(participants))
+	for _, p := range participants {
+		items = append(items, &calendar.FreeBusyRequestItem{Id: p.Email})
+	}
+	resp, err := s.svc.Freebusy.Query(&calendar.FreeBusyRequest{
+		TimeMin: opts.From.Format(time.RFC3339),
+		TimeMax: opts.To.Format(time.RFC3339),
+		Items:   items,
+	}).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get free/busy info: %w", err)
+	}
+
+	for i := range participants {
+		cal, ok := resp.Calendars[participants[i].Email]
+		if !ok {
+			participants[i].Unavailable = "notFound"
+			continue
+		}
+		if len(cal.Errors) > 0 {
+			participants[i].Unavailable = cal.Errors[0].Reason
+			continue
+		}
+		for _, b := range cal.Busy {
+			start, err1 := time.Parse(time.RFC3339, b.Start)
+			end, err2 := time.Parse(time.RFC3339, b.End)
+			if err1 == nil && err2 == nil {
+				participants[i].busy = append(participants[i].busy, interval{start, end})
+			}
+		}
+	}
+
+	return &FindTimeResult{
+		From:         opts.From.In(selfLoc).Format(time.RFC3339),
+		To:           opts.To.In(selfLoc).Format(time.RFC3339),
+		Duration:     opts.Duration.String(),
+		Participants: participants,
+		Slots:        findSlots(participants, opts, selfLoc),
+	}, nil
+}
+
+// attendeeLocation returns the time zone of an attendee's calendar, or nil
+// if their calendar is not visible to you.
+func (s *Service) attendeeLocation(email string) *time.Location {
+	tz, err := s.timeZone(email)
+	if err != nil || tz == "" {
+		return nil
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil
+	}
+	return loc
+}
+
+// resolveParticipants returns yourself ("primary") followed by the
+// attendees, each with their working hours. Attendees without configured
+// hours get the default hours in the zone returned by zoneOf, and are only
+// marked as assumed if it returns nil.
+func resolveParticipants(attendees []string, hours map[string]string, selfLoc *time.Location, zoneOf func(email string) *time.Location) ([]FindTimeParticipant, error) {
+	defaultSpec := hours["default"]
+	defaultHours, err := ParseWorkingHours(defaultSpec, selfLoc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid default working hours: %w", err)
+	}
+
+	participants := []FindTimeParticipant{{Email: "primary", Hours: defaultHours, AssumedHours: defaultSpec == ""}}
+	seen := map[string]bool{}
+	for _, email := range attendees {
+		email = strings.ToLower(strings.TrimSpace(email))
+		if email == "" || seen[email] {
+			continue
+		}
+		seen[email] = true
+
+		p := FindTimeParticipant{Email: email, Hours: defaultHours, AssumedHours: true}
+		if spec, ok := hours[email]; ok {
+			// Attendee entries default to the zone of the default entry.
+			wh, err := ParseWorkingHours(spec, defaultHours.Location())
+			if err != nil {
+				return nil, fmt.Errorf("invalid working hours for %s: %w", email, err)
+			}
+			p.Hours, p.AssumedHours = wh, false
+		} else if loc := zoneOf(email); loc != nil {
+			p.Hours.loc, p.Hours.TimeZone = loc, loc.String()
+			p.AssumedHours = false
+		}
+		participants = append(participants, p)
+	}
+	return participants, nil
+}
+
+// findSlots returns the best non-overlapping slots in the window. A slot
+// must be within everyone's working hours, outside lunch and at least the
+// buffer away from anyone's busy time. Slots are scored by how far they are
+// from the edges of everyone's working day (60%) and how soon they are
+// (40%).
+func findSlots(participants []FindTimeParticipant, opts FindTimeOptions, loc *time.Location) []MeetingSlot {
+	step := opts.Step
+	if step <= 0 {
+		step = 15 * time.Minute
+	}
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = 10
+	}
+	window := opts.To.Sub(opts.From)
+
+	type candidate struct {
+		start time.Time
+		score float64
+	}
+	var candidates []candidate
+
+	for t := opts.From.Truncate(step); !t.Add(opts.Duration).After(opts.To); t = t.Add(step) {
+		if t.Before(opts.From) {
+			continue
+		}
+		end := t.Add(opts.Duration)
+
+		total, ok := 0.0, true
+		for _, p := range participants {
+			c, fits := p.Hours.comfort(t, end)
+			if !fits || overlapsBusy(p.busy, t.Add(-opts.Buffer), end.Add(opts.Buffer)) {
+				ok = false
+				break
+			}
+			total += c
+		}
+		if !ok {
+			continue
+		}
+
+		comfort := total / float64(len(participants))
+		soon := 1 - float64(t.Sub(opts.From))/float64(window)
+		candidates = append(candidates, candidate{t, math.Round((0.6*comfort+0.4*soon)*100) / 100})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].score > candidates[j].score
+	})
+
+	var chosen []candidate
+	perDay := map[string]int{}
+	for _, c := range candidates {
+		if len(chosen) == limit {
+			break
+		}
+		day := c.start.In(loc).Format("2006-01-02")
+		if perDay[day] == maxSlotsPerDay {
+			continue
+		}
+		overlap := false
+		for _, o := range chosen {
+			if c.start.Before(o.start.Add(opts.Duration)) && o.start.Before(c.start.Add(opts.Duration)) {
+				overlap = true
+				break
+			}
+		}
+		if overlap {
+			continue
+		}
+		perDay[day]++
+		chosen = append(chosen, c)
+	}
+
+	slots := make([]MeetingSlot, 0, len(chosen))
+	for _, c := range chosen {
+		end := c.start.Add(opts.Duration)
+		local := map[string]string{}
+		for _, p := range participants {
+			l := p.Hours.Location()
+			local[p.Email] = c.start.In(l).Format("Mon 15:04") + "-" + end.In(l).Format("15:04 MST")
+		}
+		slots = append(slots, MeetingSlot{
+			Start:      c.start.In(loc).Format(time.RFC3339),
+			End:        end.In(loc).Format(time.RFC3339),
+			Score:      c.score,
+			LocalTimes: local,
+		})
+	}
+	return slots
+}
+
+func overlapsBusy(busy []interval, start, end time.Time) bool {
+	for _, b := range busy {
+		if start.Before(b.end) && b.start.Before(end) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseWithin parses a search window relative to now: "today", "tomorrow",
+// "this week", "next week", "next N days" or "next N business days". Days
+// are calendar days in loc; windows starting today start at now.
+func ParseWithin(spec string, now time.Time, loc *time.Location) (time.Time, time.Time, error) {
+	now = now.In(loc)
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	fields := strings.Fields(strings.ToLower(spec))
+
+	switch strings.Join(fields, " ") {
+	case "today":
+		return now, midnight.AddDate(0, 0, 1), nil
+	case "tomorrow":
+		return midnight.AddDate(0, 0, 1), midnight.AddDate(0, 0, 2), nil
+	case "this week":
+		return now, midnight.AddDate(0, 0, 7-int(now.Weekday()-time.Monday+7)%7), nil
+	case "next week":
+		monday := midnight.AddDate(0, 0, 7-int(now.Weekday()-time.Monday+7)%7)
+		return monday, monday.AddDate(0, 0, 7), nil
+	}
+
+	if len(fields) >= 3 && fields[0] == "next" {
+		n, err := strconv.Atoi(fields[1])
+		if err == nil && n > 0 {
+			rest := strings.Join(fields[2:], " ")
+			switch rest {
+			case "days", "day":
+				return now, midnight.AddDate(0, 0, n), nil
+			case "business days", "business day", "working days", "weekdays":
+				end := midnight
+				for counted := 0; counted < n; {
+					if wd := end.Weekday(); wd != time.Saturday && wd != time.Sunday {
+						counted++
+					}
+					end = end.AddDate(0, 0, 1)
+				}
+				return now, end, nil
+			}
+		}
+	}
+
+	return time.Time{}, time.Time{}, fmt.Errorf("invalid window %q (use today, tomorrow, this week, next week, next N days or next N business days)", spec)
+}
@@ -0,0 +1,165 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWorkingHours(t *testing.T) {
+	wh, err := ParseWorkingHours("", time.UTC)
+	require.NoError(t, err)
+	assert.Equal(t, 9*60, wh.Start)
+	assert.Equal(t, 17*60, wh.End)
+	assert.Equal(t, 12*60, wh.LunchStart)
+	assert.Equal(t, [7]bool{false, true, true, true, true, true, false}, wh.Days)
+	assert.Equal(t, "UTC", wh.TimeZone)
+
+	wh, err = ParseWorkingHours("America/New_York 08:30-16:00 sun-thu lunch none", time.UTC)
+	require.NoError(t, err)
+	assert.Equal(t, "America/New_York", wh.TimeZone)
+	assert.Equal(t, 8*60+30, wh.Start)
+	assert.Equal(t, 0, wh.LunchEnd)
+	assert.Equal(t, [7]bool{true, true, true, true, true, false, false}, wh.Days)
+
+	wh, err = ParseWorkingHours("mon,wed,friday lunch 11:30-12:15", time.UTC)
+	require.NoError(t, err)
+	assert.Equal(t, [7]bool{false, true, false, true, false, true, false}, wh.Days)
+	assert.Equal(t, 11*60+30, wh.LunchStart)
+
+	for _, spec := range []string{"Mars/Olympus", "17:00-09:00", "9-17", "someday", "lunch"} {
+		_, err := ParseWorkingHours(spec, time.UTC)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestResolveParticipants(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+
+	zoneOf := func(email string) *time.Location {
+		if email == "kenji@example.com" {
+			return tokyo
+		}
+		return nil
+	}
+	participants, err := resolveParticipants(
+		[]string{"Alice@example.com", "kenji@example.com", "eve@example.com", "alice@example.com"},
+		map[string]string{"alice@example.com": "America/New_York 08:00-16:00"},
+		berlin, zoneOf)
+	require.NoError(t, err)
+	require.Len(t, participants, 4)
+
+	assert.Equal(t, "primary", participants[0].Email)
+	assert.Equal(t, "America/New_York", participants[1].Hours.TimeZone)
+	assert.False(t, participants[1].AssumedHours)
+	assert.Equal(t, "Asia/Tokyo", participants[2].Hours.TimeZone)
+	assert.Equal(t, 9*60, participants[2].Hours.Start)
+	assert.False(t, participants[2].AssumedHours)
+	assert.Equal(t, "Europe/Berlin", participants[3].Hours.TimeZone)
+	assert.True(t, participants[3].AssumedHours)
+}
+
+func TestFindSlots(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	self, err := ParseWorkingHours("", berlin)
+	require.NoError(t, err)
+	other, err := ParseWorkingHours("America/New_York 09:00-17:00 lunch none", berlin)
+	require.NoError(t, err)
+
+	// Tuesday 2026-10-20: Berlin 09-17 (lunch 12-13) overlaps New York
+	// 09-17 only between 15:00 and 17:00 Berlin time.
+	from := time.Date(2026, 10, 20, 0, 0, 0, 0, berlin)
+	participants := []FindTimeParticipant{
+		{Email: "primary", Hours: self},
+		{Email: "bob@example.com", Hours: other, busy: []interval{{
+			time.Date(2026, 10, 20, 9, 0, 0, 0, newYork),
+			time.Date(2026, 10, 20, 9, 30, 0, 0, newYork),
+		}}},
+	}
+
+	slots := findSlots(participants, FindTimeOptions{
+		Duration: 30 * time.Minute,
+		From:     from,
+		To:       from.AddDate(0, 0, 1),
+		Buffer:   10 * time.Minute,
+	}, berlin)
+
+	require.NotEmpty(t, slots)
+	for _, s := range slots {
+		start, err := time.Parse(time.RFC3339, s.Start)
+		require.NoError(t, err)
+		local := start.In(berlin)
+		// Bob is busy 15:00-15:30 Berlin; with the buffer the first
+		// possible start is 15:45, the last 16:30.
+		assert.True(t, local.Hour()*60+local.Minute() >= 15*60+45, s.Start)
+		assert.True(t, local.Hour()*60+local.Minute() <= 16*60+30, s.Start)
+	}
+	assert.Equal(t, "Tue 15:45-16:15 CEST", slots[0].LocalTimes["primary"])
+	assert.Equal(t, "Tue 09:45-10:15 EDT", slots[0].LocalTimes["bob@example.com"])
+
+	// No overlap on a weekend.
+	saturday := time.Date(2026, 10, 24, 0, 0, 0, 0, berlin)
+	assert.Empty(t, findSlots(participants, FindTimeOptions{
+		Duration: 30 * time.Minute,
+		From:     saturday,
+		To:       saturday.AddDate(0, 0, 2),
+	}, berlin))
+}
+
+func TestFindSlotsRanking(t *testing.T) {
+	hours, err := ParseWorkingHours("UTC 09:00-17:00 lunch none", time.UTC)
+	require.NoError(t, err)
+	from := time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)
+
+	slots := findSlots([]FindTimeParticipant{{Email: "primary", Hours: hours}}, FindTimeOptions{
+		Duration: time.Hour,
+		From:     from,
+		To:       from.AddDate(0, 0, 2),
+		Limit:    4,
+	}, time.UTC)
+
+	require.Len(t, slots, 4)
+	// Mid-day slots rank first, at most three per day, never overlapping.
+	assert.Equal(t, "2026-10-20T11:00:00Z", slots[0].Start)
+	perDay := map[string]int{}
+	for _, s := range slots {
+		perDay[s.Start[:10]]++
+	}
+	assert.Equal(t, map[string]int{"2026-10-20": 3, "2026-10-21": 1}, perDay)
+}
+
+func TestParseWithin(t *testing.T) {
+	// Thursday afternoon.
+	now := time.Date(2026, 10, 22, 14, 0, 0, 0, time.UTC)
+
+	from, to, err := ParseWithin("next 5 business days", now, time.UTC)
+	require.NoError(t, err)
+	assert.Equal(t, now, from)
+	assert.Equal(t, time.Date(2026, 10, 29, 0, 0, 0, 0, time.UTC), to)
+
+	from, to, err = ParseWithin("tomorrow", now, time.UTC)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 10, 23, 0, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, time.Date(2026, 10, 24, 0, 0, 0, 0, time.UTC), to)
+
+	from, to, err = ParseWithin("next week", now, time.UTC)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 10, 26, 0, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, time.Date(2026, 11, 2, 0, 0, 0, 0, time.UTC), to)
+
+	_, to, err = ParseWithin("this week", now, time.UTC)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 10, 26, 0, 0, 0, 0, time.UTC), to)
+
+	_, _, err = ParseWithin("soonish", now, time.UTC)
+	assert.Error(t, err)
+}
@@ -0,0 +1,540 @@
+package calendar
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ulfhaga/gagent-cli/internal/ical"
+	"google.golang.org/api/calendar/v3"
+)
+
+// Import statuses.
+const (
+	ImportCreated = "created"
+	ImportUpdated = "updated"
+	ImportSkipped = "skipped"
+	ImportFailed  = "failed"
+)
+
+// icsStatus maps Calendar API event statuses to iCalendar STATUS values.
+var icsStatus = map[string]string{
+	"confirmed": "CONFIRMED",
+	"tentative": "TENTATIVE",
+	"cancelled": "CANCELLED",
+}
+
+// icsPartstat maps Calendar API response statuses to iCalendar PARTSTAT
+// values.
+var icsPartstat = map[string]string{
+	"needsAction": "NEEDS-ACTION",
+	"accepted":    "ACCEPTED",
+	"declined":    "DECLINED",
+	"tentative":   "TENTATIVE",
+}
+
+// Export returns the events of a calendar between from and to as iCalendar
+// data, and the number of events written. Recurring events are exported as
+// series with their RRULE; modified occurrences get a RECURRENCE-ID and
+// cancelled ones become EXDATEs.
+func (s *Service) Export(calendarID string, from, to time.Time) ([]byte, int, error) {
+	if calendarID == "" {
+		calendarID = "primary"
+	}
+
+	cal, err := s.svc.Calendars.Get(calendarID).Do()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get calendar: %w", err)
+	}
+
+	var events []*calendar.Event
+	pageToken := ""
+	for {
+		call := s.svc.Events.List(calendarID).SingleEvents(false).MaxResults(2500).
+			TimeMin(from.Format(time.RFC3339)).TimeMax(to.Format(time.RFC3339))
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+		resp, err := call.Do()
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to list events: %w", err)
+		}
+		events = append(events, resp.Items...)
+		if pageToken = resp.NextPageToken; pageToken == "" {
+			break
+		}
+	}
+
+	return EncodeICS(events, cal.Summary, to)
+}
+
+// EncodeICS encodes events as an iCalendar file named name. Time zones used
+// by the events are described by VTIMEZONE components covering the events
+// and one year past until.
+func EncodeICS(events []*calendar.Event, name string, until time.Time) ([]byte, int, error) {
+	vcal := &ical.Component{Name: "VCALENDAR"}
+	vcal.Add("VERSION", "2.0", nil)
+	vcal.Add("PRODID", "-//gagent-cli//Calendar Export//EN", nil)
+	vcal.Add("CALSCALE", "GREGORIAN", nil)
+	vcal.Add("METHOD", "PUBLISH", nil)
+	if name != "" {
+		vcal.Add("X-WR-CALNAME", ical.Escape(name), nil)
+	}
+
+	// Cancelled occurrences are exported as EXDATEs of their series.
+	exdates := map[string][]*calendar.EventDateTime{}
+	for _, e := range events {
+		if e.RecurringEventId != "" && e.Status == "cancelled" && e.OriginalStartTime != nil {
+			exdates[e.RecurringEventId] = append(exdates[e.RecurringEventId], e.OriginalStartTime)
+		}
+	}
+
+	zones := map[string]time.Time{}
+	addZone := func(edt *calendar.EventDateTime) {
+		if edt == nil || edt.TimeZone == "" || edt.Date != "" {
+			return
+		}
+		t, err := time.Parse(time.RFC3339, edt.DateTime)
+		if err != nil {
+			return
+		}
+		if first, ok := zones[edt.TimeZone]; !ok || t.Before(first) {
+			zones[edt.TimeZone] = t
+		}
+	}
+
+	var vevents []*ical.Component
+	for _, e := range events {
+		if e.Status == "cancelled" || e.Start == nil {
+			continue
+		}
+		ev, err := eventToVEvent(e, exdates[e.Id])
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to export event %s: %w", e.Id, err)
+		}
+		vevents = append(vevents, ev)
+		addZone(e.Start)
+		addZone(e.End)
+	}
+
+	tzids := make([]string, 0, len(zones))
+	for tzid := range zones {
+		tzids = append(tzids, tzid)
+	}
+	sort.Strings(tzids)
+	for _, tzid := range tzids {
+		loc, err := time.LoadLocation(tzid)
+		if err != nil {
+			continue
+		}
+		from := zones[tzid].AddDate(0, 0, -1)
+		vcal.Components = append(vcal.Components, ical.VTimezone(loc, from, until.AddDate(1, 0, 0)))
+	}
+	vcal.Components = append(vcal.Components, vevents...)
+
+	var buf bytes.Buffer
+	if err := ical.Encode(&buf, vcal); err != nil {
+		return nil, 0, err
+	}
+	return buf.Bytes(), len(vevents), nil
+}
+
+// eventToVEvent converts an event to a VEVENT component.
+func eventToVEvent(e *calendar.Event, exdates []*calendar.EventDateTime) (*ical.Component, error) {
+	ev := &ical.Component{Name: "VEVENT"}
+
+	uid := e.ICalUID
+	if uid == "" {
+		uid = e.Id + "@google.com"
+	}
+	ev.Add("UID", uid, nil)
+
+	stamp := time.Now().UTC()
+	if t, err := time.Parse(time.RFC3339, e.Updated); err == nil {
+		stamp = t.UTC()
+		ev.Add("LAST-MODIFIED", ical.FormatDateTime(stamp), nil)
+	}
+	ev.Add("DTSTAMP", ical.FormatDateTime(stamp), nil)
+	if t, err := time.Parse(time.RFC3339, e.Created); err == nil {
+		ev.Add("CREATED", ical.FormatDateTime(t.UTC()), nil)
+	}
+
+	for _, p := range []struct {
+		name string
+		edt  *calendar.EventDateTime
+	}{{"DTSTART", e.Start}, {"DTEND", e.End}, {"RECURRENCE-ID", e.OriginalStartTime}} {
+		if p.edt == nil || p.name == "RECURRENCE-ID" && e.RecurringEventId == "" {
+			continue
+		}
+		value, params, err := icsDateTime(p.edt)
+		if err != nil {
+			return nil, err
+		}
+		ev.Add(p.name, value, params)
+	}
+
+	for _, line := range e.Recurrence {
+		prop, err := ical.ParseProperty(line)
+		if err != nil {
+			return nil, fmt.Errorf("invalid recurrence %q: %w", line, err)
+		}
+		ev.Properties = append(ev.Properties, prop)
+	}
+	for _, edt := range exdates {
+		value, params, err := icsDateTime(edt)
+		if err != nil {
+			return nil, err
+		}
+		ev.Add("EXDATE", value, params)
+	}
+
+	if e.Summary != "" {
+		ev.Add("SUMMARY", ical.Escape(e.Summary), nil)
+	}
+	if e.Description != "" {
+		ev.Add("DESCRIPTION", ical.Escape(e.Description), nil)
+	}
+	if e.Location != "" {
+		ev.Add("LOCATION", ical.Escape(e.Location), nil)
+	}
+	if status := icsStatus[e.Status]; status != "" {
+		ev.Add("STATUS", status, nil)
+	}
+	if e.Sequence > 0 {
+		ev.Add("SEQUENCE", strconv.FormatInt(e.Sequence, 10), nil)
+	}
+	if e.Transparency == "transparent" {
+		ev.Add("TRANSP", "TRANSPARENT", nil)
+	} else {
+		ev.Add("TRANSP", "OPAQUE", nil)
+	}
+	if e.Visibility != "" && e.Visibility != "default" {
+		ev.Add("CLASS", strings.ToUpper(e.Visibility), nil)
+	}
+	if e.HtmlLink != "" {
+		ev.Add("URL", e.HtmlLink, nil)
+	}
+
+	if e.Organizer != nil && e.Organizer.Email != "" {
+		params := map[string]string{}
+		if e.Organizer.DisplayName != "" {
+			params["CN"] = e.Organizer.DisplayName
+		}
+		ev.Add("ORGANIZER", "mailto:"+e.Organizer.Email, params)
+	}
+	for _, a := range e.Attendees {
+		params := map[string]string{"ROLE": "REQ-PARTICIPANT", "CUTYPE": "INDIVIDUAL"}
+		if a.Optional {
+			params["ROLE"] = "OPT-PARTICIPANT"
+		}
+		if a.Resource {
+			params["CUTYPE"] = "RESOURCE"
+		}
+		if a.DisplayName != "" {
+			params["CN"] = a.DisplayName
+		}
+		if partstat := icsPartstat[a.ResponseStatus]; partstat != "" {
+			params["PARTSTAT"] = partstat
+		}
+		ev.Add("ATTENDEE", "mailto:"+a.Email, params)
+	}
+
+	return ev, nil
+}
+
+// icsDateTime converts an event time to a DATE or DATE-TIME value. Times
+// with a known zone are written as local time with TZID, others in UTC.
+func icsDateTime(edt *calendar.EventDateTime) (string, map[string]string, error) {
+	if edt.Date != "" {
+		d, err := time.Parse("2006-01-02", edt.Date)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid date %q", edt.Date)
+		}
+		return d.Format("20060102"), map[string]string{"VALUE": "DATE"}, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, edt.DateTime)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid time %q", edt.DateTime)
+	}
+	if edt.TimeZone != "" {
+		if loc, err := time.LoadLocation(edt.TimeZone); err == nil {
+			return ical.FormatDateTime(t.In(loc)), map[string]string{"TZID": edt.TimeZone}, nil
+		}
+	}
+	return ical.FormatDateTime(t.UTC()), nil, nil
+}
+
+// ImportResult is the outcome of importing one event.
+type ImportResult struct {
+	UID     string `json:"uid"`
+	Summary string `json:"summary,omitempty"`
+	Start   string `json:"start,omitempty"`
+	Status  string `json:"status"`
+	EventID string `json:"event_id,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// ParseICS converts the VEVENTs of iCalendar data to events ready for
+// Events.Import. Floating times are interpreted in loc; TZIDs are resolved
+// from zone names or the file's VTIMEZONE definitions. Events without a
+// UID get one derived from their summary and start, so importing the same
+// file again updates instead of duplicating. Modified occurrences of
+// recurring events (RECURRENCE-ID) carry OriginalStartTime and follow all
+// series; events that cannot be converted are returned as failed.
+func ParseICS(data []byte, loc *time.Location) ([]*calendar.Event, []ImportResult, error) {
+	cal, err := ical.Parse(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse iCalendar data: %w", err)
+	}
+
+	var events, occurrences []*calendar.Event
+	var skipped []ImportResult
+	for _, ev := range cal.Find("VEVENT") {
+		modified := ev.Prop("RECURRENCE-ID") != nil
+		event, err := veventToEvent(cal, ev, loc)
+		if err == nil && modified {
+			err = setOriginalStart(cal, ev, event, loc)
+		}
+		if err != nil {
+			skipped = append(skipped, ImportResult{
+				UID:     ev.Text("UID"),
+				Summary: ev.Text("SUMMARY"),
+				Status:  ImportFailed,
+				Error:   err.Error(),
+			})
+			continue
+		}
+		if modified {
+			occurrences = append(occurrences, event)
+		} else {
+			events = append(events, event)
+		}
+	}
+
+	// Series are imported before their modified occurrences.
+	return append(events, occurrences...), skipped, nil
+}
+
+// setOriginalStart sets the OriginalStartTime of a modified occurrence
+// from its RECURRENCE-ID.
+func setOriginalStart(cal, ev *ical.Component, event *calendar.Event, loc *time.Location) error {
+	if ev.Prop("UID") == nil {
+		return fmt.Errorf("modified occurrence without UID")
+	}
+	rid := ev.Prop("RECURRENCE-ID")
+	t, allDay, err := cal.Time(rid, loc)
+	if err != nil {
+		return fmt.Errorf("invalid RECURRENCE-ID: %w", err)
+	}
+	if allDay {
+		event.OriginalStartTime = &calendar.EventDateTime{Date: t.Format("2006-01-02")}
+	} else {
+		event.OriginalStartTime = &calendar.EventDateTime{DateTime: t.Format(time.RFC3339), TimeZone: zoneName(t.Location())}
+	}
+	return nil
+}
+
+// zoneName returns the IANA name of a location, or "" for fixed offsets
+// and the local zone.
+func zoneName(loc *time.Location) string {
+	name := loc.String()
+	if name == "" || name == "Local" {
+		return ""
+	}
+	if _, err := time.LoadLocation(name); err != nil {
+		return ""
+	}
+	return name
+}
+
+// veventToEvent converts a VEVENT component of calendar cal to an API event.
+func veventToEvent(cal, ev *ical.Component, loc *time.Location) (*calendar.Event, error) {
+	dtstart := ev.Prop("DTSTART")
+	if dtstart == nil {
+		return nil, fmt.Errorf("missing DTSTART")
+	}
+	start, allDay, err := cal.Time(dtstart, loc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DTSTART: %w", err)
+	}
+
+	var end time.Time
+	switch {
+	case ev.Prop("DTEND") != nil:
+		if end, _, err = cal.Time(ev.Prop("DTEND"), loc); err != nil {
+			return nil, fmt.Errorf("invalid DTEND: %w", err)
+		}
+	case ev.Prop("DURATION") != nil:
+		d, err := ical.ParseDuration(ev.Prop("DURATION").Value)
+		if err != nil {
+			return nil, err
+		}
+		end = start.Add(d)
+	case allDay:
+		end = start.AddDate(0, 0, 1)
+	default:
+		end = start
+	}
+
+	event := &calendar.Event{
+		ICalUID:     ev.Text("UID"),
+		Summary:     ev.Text("SUMMARY"),
+		Description: ev.Text("DESCRIPTION"),
+		Location:    ev.Text("LOCATION"),
+	}
+	if event.ICalUID == "" {
+		sum := sha1.Sum([]byte(event.Summary + "\x00" + dtstart.Value))
+		event.ICalUID = hex.EncodeToString(sum[:]) + "@gagent-cli"
+	}
+
+	// UTC times carry no zone; zones defined only by the file's VTIMEZONE
+	// resolve to a fixed offset, which has no name to give the API.
+	tzid := ""
+	if !strings.HasSuffix(dtstart.Value, "Z") {
+		tzid = zoneName(start.Location())
+	}
+	if allDay {
+		event.Start = &calendar.EventDateTime{Date: start.Format("2006-01-02")}
+		event.End = &calendar.EventDateTime{Date: end.Format("2006-01-02")}
+	} else {
+		event.Start = &calendar.EventDateTime{DateTime: start.Format(time.RFC3339), TimeZone: tzid}
+		event.End = &calendar.EventDateTime{DateTime: end.Format(time.RFC3339), TimeZone: tzid}
+	}
+
+	for _, name := range []string{"RRULE", "EXDATE", "RDATE"} {
+		for _, p := range ev.Props(name) {
+			event.Recurrence = append(event.Recurrence, p.String())
+		}
+	}
+	if len(event.Recurrence) > 0 && !allDay && event.Start.TimeZone == "" {
+		if _, ok := dtstart.Params["TZID"]; ok {
+			return nil, fmt.Errorf("recurring event in time zone %q, which has no IANA name", dtstart.Params["TZID"])
+		}
+		event.Start.TimeZone, event.End.TimeZone = "UTC", "UTC"
+	}
+
+	switch strings.ToUpper(ev.Text("STATUS")) {
+	case "TENTATIVE":
+		event.Status = "tentative"
+	case "CANCELLED":
+		event.Status = "cancelled"
+	default:
+		event.Status = "confirmed"
+	}
+	if seq, err := strconv.ParseInt(ev.Text("SEQUENCE"), 10, 64); err == nil {
+		event.Sequence = seq
+	}
+	if strings.EqualFold(ev.Text("TRANSP"), "TRANSPARENT") {
+		event.Transparency = "transparent"
+	}
+	switch class := strings.ToLower(ev.Text("CLASS")); class {
+	case "public", "private", "confidential":
+		event.Visibility = class
+	}
+
+	if p := ev.Prop("ORGANIZER"); p != nil {
+		event.Organizer = &calendar.EventOrganizer{
+			Email:       ical.CalAddress(p.Value),
+			DisplayName: p.Params["CN"],
+		}
+	}
+	for _, p := range ev.Props("ATTENDEE") {
+		attendee := &calendar.EventAttendee{
+			Email:       ical.CalAddress(p.Value),
+			DisplayName: p.Params["CN"],
+			Optional:    strings.EqualFold(p.Params["ROLE"], "OPT-PARTICIPANT"),
+			Resource:    strings.EqualFold(p.Params["CUTYPE"], "RESOURCE"),
+		}
+		attendee.ResponseStatus = "needsAction"
+		for status, partstat := range icsPartstat {
+			if strings.EqualFold(p.Params["PARTSTAT"], partstat) {
+				attendee.ResponseStatus = status
+			}
+		}
+		event.Attendees = append(event.Attendees, attendee)
+	}
+
+	return event, nil
+}
+
+// Import creates or updates events through Events.Import, which matches
+// existing events by iCalUID, so importing the same file twice is safe.
+// Modified occurrences (events with OriginalStartTime) are imported as
+// exceptions of their series, which must be imported before them or
+// already exist in the calendar.
+func (s *Service) Import(calendarID string, events []*calendar.Event) []ImportResult {
+	if calendarID == "" {
+		calendarID = "primary"
+	}
+
+	series := map[string]string{} // iCalUID -> imported series ID
+	results := make([]ImportResult, 0, len(events))
+	for _, event := range events {
+		r := ImportResult{
+			UID:     event.ICalUID,
+			Summary: event.Summary,
+			Start:   event.Start.DateTime + event.Start.Date,
+			Status:  ImportCreated,
+		}
+
+		var existing []*calendar.Event
+		if resp, err := s.svc.Events.List(calendarID).ICalUID(event.ICalUID).ShowDeleted(true).Do(); err == nil {
+			existing = resp.Items
+		}
+
+		if event.OriginalStartTime != nil {
+			parent := series[event.ICalUID]
+			for _, e := range existing {
+				if parent == "" && e.RecurringEventId == "" && len(e.Recurrence) > 0 {
+					parent = e.Id
+				}
+			}
+			if parent == "" {
+				r.Status = ImportFailed
+				r.Error = "recurring event of modified occurrence not found"
+				results = append(results, r)
+				continue
+			}
+			event.RecurringEventId = parent
+		}
+
+		for _, e := range existing {
+			if e.RecurringEventId == event.RecurringEventId && sameOriginalStart(e.OriginalStartTime, event.OriginalStartTime) {
+				r.Status = ImportUpdated
+			}
+		}
+
+		imported, err := s.svc.Events.Import(calendarID, event).Do()
+		if err != nil {
+			r.Status = ImportFailed
+			r.Error = fmt.Sprintf("failed to import event: %v", err)
+		} else {
+			r.EventID = imported.Id
+			if event.OriginalStartTime == nil && len(event.Recurrence) > 0 {
+				series[event.ICalUID] = imported.Id
+			}
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+// sameOriginalStart reports whether two original start times are equal;
+// both nil means neither event is a modified occurrence.
+func sameOriginalStart(a, b *calendar.EventDateTime) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if a.Date != "" || b.Date != "" {
+		return a.Date == b.Date
+	}
+	ta, errA := time.Parse(time.RFC3339, a.DateTime)
+	tb, errB := time.Parse(time.RFC3339, b.DateTime)
+	return errA == nil && errB == nil && ta.Equal(tb)
+}
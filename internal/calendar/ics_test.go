@@ -0,0 +1,187 @@
+package calendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/calendar/v3"
+)
+
+func testEvents() []*calendar.Event {
+	return []*calendar.Event{
+		{
+			Id:          "series1",
+			ICalUID:     "series1@google.com",
+			Summary:     "Standup, daily",
+			Description: "Notes:\nbring coffee",
+			Status:      "confirmed",
+			Start:       &calendar.EventDateTime{DateTime: "2026-10-19T09:00:00+02:00", TimeZone: "Europe/Berlin"},
+			End:         &calendar.EventDateTime{DateTime: "2026-10-19T09:15:00+02:00", TimeZone: "Europe/Berlin"},
+			Recurrence:  []string{"RRULE:FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR"},
+			Organizer:   &calendar.EventOrganizer{Email: "me@example.com", DisplayName: "Me"},
+			Attendees: []*calendar.EventAttendee{
+				{Email: "jane@example.com", DisplayName: "Doe, Jane", ResponseStatus: "accepted"},
+				{Email: "bob@example.com", ResponseStatus: "needsAction", Optional: true},
+			},
+			Updated: "2026-10-01T10:00:00.000Z",
+		},
+		{
+			// Cancelled occurrence of the series.
+			Id:                "series1_20261021T070000Z",
+			RecurringEventId:  "series1",
+			Status:            "cancelled",
+			OriginalStartTime: &calendar.EventDateTime{DateTime: "2026-10-21T09:00:00+02:00", TimeZone: "Europe/Berlin"},
+		},
+		{
+			// Moved occurrence of the series.
+			Id:                "series1_20261022T070000Z",
+			ICalUID:           "series1@google.com",
+			RecurringEventId:  "series1",
+			Summary:           "Standup, daily",
+			Status:            "confirmed",
+			Start:             &calendar.EventDateTime{DateTime: "2026-10-22T10:00:00+02:00", TimeZone: "Europe/Berlin"},
+			End:               &calendar.EventDateTime{DateTime: "2026-10-22T10:15:00+02:00", TimeZone: "Europe/Berlin"},
+			OriginalStartTime: &calendar.EventDateTime{DateTime: "2026-10-22T09:00:00+02:00", TimeZone: "Europe/Berlin"},
+		},
+		{
+			Id:           "offsite",
+			ICalUID:      "offsite@google.com",
+			Summary:      "Offsite",
+			Status:       "tentative",
+			Transparency: "transparent",
+			Visibility:   "private",
+			Start:        &calendar.EventDateTime{Date: "2026-10-26"},
+			End:          &calendar.EventDateTime{Date: "2026-10-28"},
+		},
+	}
+}
+
+func TestEncodeICS(t *testing.T) {
+	data, n, err := EncodeICS(testEvents(), "Work", time.Date(2026, 10, 31, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+
+	ics := strings.ReplaceAll(string(data), "\r\n ", "") // unfold
+	assert.True(t, strings.HasPrefix(ics, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.Contains(t, ics, "X-WR-CALNAME:Work\r\n")
+	assert.Contains(t, ics, "BEGIN:VTIMEZONE\r\nTZID:Europe/Berlin\r\n")
+	assert.Contains(t, ics, "DTSTART;TZID=Europe/Berlin:20261019T090000\r\n")
+	assert.Contains(t, ics, "RRULE:FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR\r\n")
+	assert.Contains(t, ics, "EXDATE;TZID=Europe/Berlin:20261021T090000\r\n")
+	assert.Contains(t, ics, "RECURRENCE-ID;TZID=Europe/Berlin:20261022T090000\r\n")
+	assert.Contains(t, ics, "SUMMARY:Standup\\, daily\r\n")
+	assert.Contains(t, ics, "ATTENDEE;CN=\"Doe, Jane\";CUTYPE=INDIVIDUAL;PARTSTAT=ACCEPTED;ROLE=REQ-PARTICIPANT:mailto:jane@example.com\r\n")
+	assert.Contains(t, ics, "DTSTART;VALUE=DATE:20261026\r\n")
+	assert.Contains(t, ics, "CLASS:PRIVATE\r\n")
+	assert.Equal(t, 1, strings.Count(ics, "BEGIN:VTIMEZONE"))
+}
+
+func TestParseICS_RoundTrip(t *testing.T) {
+	data, _, err := EncodeICS(testEvents(), "Work", time.Date(2026, 10, 31, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	events, skipped, err := ParseICS(data, time.UTC)
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	assert.Empty(t, skipped)
+
+	// The moved occurrence follows the series it belongs to.
+	moved := events[2]
+	assert.Equal(t, "series1@google.com", moved.ICalUID)
+	assert.Equal(t, "2026-10-22T10:00:00+02:00", moved.Start.DateTime)
+	require.NotNil(t, moved.OriginalStartTime)
+	assert.Equal(t, "2026-10-22T09:00:00+02:00", moved.OriginalStartTime.DateTime)
+	assert.Equal(t, "Europe/Berlin", moved.OriginalStartTime.TimeZone)
+
+	series := events[0]
+	assert.Equal(t, "series1@google.com", series.ICalUID)
+	assert.Equal(t, "Standup, daily", series.Summary)
+	assert.Equal(t, "Notes:\nbring coffee", series.Description)
+	assert.Equal(t, "2026-10-19T09:00:00+02:00", series.Start.DateTime)
+	assert.Equal(t, "Europe/Berlin", series.Start.TimeZone)
+	assert.Equal(t, []string{
+		"RRULE:FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR",
+		"EXDATE;TZID=Europe/Berlin:20261021T090000",
+	}, series.Recurrence)
+	assert.Equal(t, "me@example.com", series.Organizer.Email)
+	require.Len(t, series.Attendees, 2)
+	assert.Equal(t, "Doe, Jane", series.Attendees[0].DisplayName)
+	assert.Equal(t, "accepted", series.Attendees[0].ResponseStatus)
+	assert.True(t, series.Attendees[1].Optional)
+
+	offsite := events[1]
+	assert.Equal(t, "2026-10-26", offsite.Start.Date)
+	assert.Equal(t, "2026-10-28", offsite.End.Date)
+	assert.Equal(t, "tentative", offsite.Status)
+	assert.Equal(t, "transparent", offsite.Transparency)
+	assert.Equal(t, "private", offsite.Visibility)
+}
+
+func TestParseICS_Defaults(t *testing.T) {
+	data := "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\n" +
+		"SUMMARY:Lunch\r\nDTSTART:20261020T120000\r\nDURATION:PT45M\r\n" +
+		"END:VEVENT\r\nBEGIN:VEVENT\r\nSUMMARY:Broken\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	events, skipped, err := ParseICS([]byte(data), berlin)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "2026-10-20T12:00:00+02:00", events[0].Start.DateTime)
+	assert.Equal(t, "2026-10-20T12:45:00+02:00", events[0].End.DateTime)
+	assert.Equal(t, "Europe/Berlin", events[0].Start.TimeZone)
+	assert.True(t, strings.HasSuffix(events[0].ICalUID, "@gagent-cli"))
+
+	// The derived UID is stable across imports.
+	again, _, err := ParseICS([]byte(data), berlin)
+	require.NoError(t, err)
+	assert.Equal(t, events[0].ICalUID, again[0].ICalUID)
+
+	require.Len(t, skipped, 1)
+	assert.Equal(t, ImportFailed, skipped[0].Status)
+}
+
+func TestParseICS_Zones(t *testing.T) {
+	data := "BEGIN:VCALENDAR\r\n" +
+		"BEGIN:VTIMEZONE\r\nTZID:Custom\r\n" +
+		"BEGIN:STANDARD\r\nDTSTART:16010101T030000\r\nTZOFFSETFROM:+0200\r\nTZOFFSETTO:+0100\r\n" +
+		"RRULE:FREQ=YEARLY;BYDAY=-1SU;BYMONTH=10\r\nEND:STANDARD\r\n" +
+		"BEGIN:DAYLIGHT\r\nDTSTART:16010101T020000\r\nTZOFFSETFROM:+0100\r\nTZOFFSETTO:+0200\r\n" +
+		"RRULE:FREQ=YEARLY;BYDAY=-1SU;BYMONTH=3\r\nEND:DAYLIGHT\r\nEND:VTIMEZONE\r\n" +
+		"BEGIN:VEVENT\r\nUID:a\r\nSUMMARY:Outlook\r\n" +
+		"DTSTART;TZID=\"W. Europe Standard Time\":20261020T090000\r\nDTEND;TZID=\"W. Europe Standard Time\":20261020T100000\r\nEND:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\nUID:b\r\nSUMMARY:Custom\r\n" +
+		"DTSTART;TZID=Custom:20261020T090000\r\nDTEND;TZID=Custom:20261020T100000\r\nEND:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\nUID:c\r\nSUMMARY:Custom weekly\r\nRRULE:FREQ=WEEKLY\r\n" +
+		"DTSTART;TZID=Custom:20261020T090000\r\nDTEND;TZID=Custom:20261020T100000\r\nEND:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\nUID:d\r\nSUMMARY:Unknown\r\n" +
+		"DTSTART;TZID=Nowhere:20261020T090000\r\nEND:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+
+	events, skipped, err := ParseICS([]byte(data), time.UTC)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, "2026-10-20T09:00:00+02:00", events[0].Start.DateTime)
+	assert.Equal(t, "Europe/Berlin", events[0].Start.TimeZone)
+	assert.Equal(t, "2026-10-20T09:00:00+02:00", events[1].Start.DateTime)
+	assert.Empty(t, events[1].Start.TimeZone)
+
+	require.Len(t, skipped, 2)
+	assert.Equal(t, "c", skipped[0].UID)
+	assert.Equal(t, ImportFailed, skipped[0].Status)
+	assert.Equal(t, "d", skipped[1].UID)
+	assert.Contains(t, skipped[1].Error, "unknown time zone")
+}
+
+func TestSameOriginalStart(t *testing.T) {
+	a := &calendar.EventDateTime{DateTime: "2026-10-22T09:00:00+02:00"}
+	b := &calendar.EventDateTime{DateTime: "2026-10-22T07:00:00Z"}
+	assert.True(t, sameOriginalStart(a, b))
+	assert.True(t, sameOriginalStart(nil, nil))
+	assert.False(t, sameOriginalStart(a, nil))
+	assert.False(t, sameOriginalStart(&calendar.EventDateTime{Date: "2026-10-22"}, &calendar.EventDateTime{Date: "2026-10-23"}))
+}
@@ -0,0 +1,249 @@
+package calendar
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+// ACL roles, from least to most access.
+const (
+	RoleFreeBusyReader = "freeBusyReader"
+	RoleReader         = "reader"
+	RoleWriter         = "writer"
+	RoleOwner          = "owner"
+)
+
+// CalendarOptions contains options for creating or updating a calendar.
+// Empty fields are left unchanged on update.
+type CalendarOptions struct {
+	Summary     string
+	Description string
+	Location    string
+	TimeZone    string // IANA zone; new calendars default to the primary calendar's
+}
+
+// ACLRule is a calendar sharing rule.
+type ACLRule struct {
+	ID         string `json:"id"`
+	Role       string `json:"role"`
+	ScopeType  string `json:"scope_type"`
+	ScopeValue string `json:"scope_value,omitempty"`
+}
+
+// CreateCalendar creates a secondary calendar.
+func (s *Service) CreateCalendar(opts CalendarOptions) (*CalendarInfo, error) {
+	if opts.Summary == "" {
+		return nil, fmt.Errorf("calendar title is required")
+	}
+
+	timeZone := opts.TimeZone
+	if timeZone == "" {
+		var err error
+		if timeZone, err = s.timeZone("primary"); err != nil {
+			return nil, err
+		}
+	}
+
+	created, err := s.svc.Calendars.Insert(&calendar.Calendar{
+		Summary:     opts.Summary,
+		Description: opts.Description,
+		Location:    opts.Location,
+		TimeZone:    timeZone,
+	}).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create calendar: %w", err)
+	}
+
+	return calendarToInfo(created), nil
+}
+
+// UpdateCalendar changes a calendar's title, description, location or
+// time zone.
+func (s *Service) UpdateCalendar(calendarID string, opts CalendarOptions) (*CalendarInfo, error) {
+	if opts == (CalendarOptions{}) {
+		return nil, fmt.Errorf("nothing to update")
+	}
+
+	patch := &calendar.Calendar{
+		Summary:     opts.Summary,
+		Description: opts.Description,
+		Location:    opts.Location,
+		TimeZone:    opts.TimeZone,
+	}
+
+	updated, err := s.svc.Calendars.Patch(calendarID, patch).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to update calendar: %w", err)
+	}
+
+	return calendarToInfo(updated), nil
+}
+
+// DeleteCalendar permanently deletes a secondary calendar and its events.
+// The primary calendar cannot be deleted.
+func (s *Service) DeleteCalendar(calendarID string) error {
+	if calendarID == "" || calendarID == "primary" {
+		return fmt.Errorf("the primary calendar cannot be deleted")
+	}
+	if err := s.svc.Calendars.Delete(calendarID).Do(); err != nil {
+		return fmt.Errorf("failed to delete calendar: %w", err)
+	}
+	return nil
+}
+
+// Subscribe adds an existing calendar, such as a shared or public one, to
+// your calendar list.
+func (s *Service) Subscribe(calendarID string) (*CalendarInfo, error) {
+	entry, err := s.svc.CalendarList.Insert(&calendar.CalendarListEntry{Id: calendarID}).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to subscribe to calendar: %w", err)
+	}
+
+	return &CalendarInfo{
+		ID:          entry.Id,
+		Summary:     entry.Summary,
+		Description: entry.Description,
+		TimeZone:    entry.TimeZone,
+		Primary:     entry.Primary,
+		AccessRole:  entry.AccessRole,
+	}, nil
+}
+
+// Unsubscribe removes a calendar from your calendar list without deleting
+// it.
+func (s *Service) Unsubscribe(calendarID string) error {
+	if err := s.svc.CalendarList.Delete(calendarID).Do(); err != nil {
+		return fmt.Errorf("failed to unsubscribe from calendar: %w", err)
+	}
+	return nil
+}
+
+// ACL returns the sharing rules of a calendar.
+func (s *Service) ACL(calendarID string) ([]ACLRule, error) {
+	if calendarID == "" {
+		calendarID = "primary"
+	}
+
+	rules := []ACLRule{}
+	pageToken := ""
+	for {
+		call := s.svc.Acl.List(calendarID)
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+		resp, err := call.Do()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list ACL: %w", err)
+		}
+		for _, rule := range resp.Items {
+			rules = append(rules, aclToRule(rule))
+		}
+		if resp.NextPageToken == "" {
+			return rules, nil
+		}
+		pageToken = resp.NextPageToken
+	}
+}
+
+// Grant shares a calendar with a scope (see ParseACLScope) at a role,
+// replacing any existing rule for the scope.
+func (s *Service) Grant(calendarID, scopeType, scopeValue, role string, notify bool) (*ACLRule, error) {
+	if calendarID == "" {
+		calendarID = "primary"
+	}
+
+	rule := &calendar.AclRule{
+		Role:  role,
+		Scope: &calendar.AclRuleScope{Type: scopeType, Value: scopeValue},
+	}
+	created, err := s.svc.Acl.Insert(calendarID, rule).SendNotifications(notify).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to grant access: %w", err)
+	}
+
+	result := aclToRule(created)
+	return &result, nil
+}
+
+// Revoke removes a sharing rule. ruleID is a rule ID as returned by ACL,
+// such as "user:alice@example.com" or "default".
+func (s *Service) Revoke(calendarID, ruleID string) error {
+	if calendarID == "" {
+		calendarID = "primary"
+	}
+	if err := s.svc.Acl.Delete(calendarID, ruleID).Do(); err != nil {
+		return fmt.Errorf("failed to revoke access: %w", err)
+	}
+	return nil
+}
+
+// ParseACLRole parses a role name case-insensitively. "freebusy" is
+// accepted for freeBusyReader.
+func ParseACLRole(role string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(role)) {
+	case "freebusyreader", "freebusy":
+		return RoleFreeBusyReader, nil
+	case "reader":
+		return RoleReader, nil
+	case "writer":
+		return RoleWriter, nil
+	case "owner":
+		return RoleOwner, nil
+	}
+	return "", fmt.Errorf("invalid role %q (use: freeBusyReader, reader, writer, owner)", role)
+}
+
+// ParseACLScope parses who a rule applies to: "user:alice@example.com",
+// "group:team@example.com", "domain:example.com" or "default" (public). A
+// bare email is a user and a bare domain name is a domain.
+func ParseACLScope(spec string) (scopeType, value string, err error) {
+	spec = strings.TrimSpace(spec)
+	if t, v, ok := strings.Cut(spec, ":"); ok {
+		switch t = strings.ToLower(t); t {
+		case "user", "group", "domain":
+			if v == "" {
+				return "", "", fmt.Errorf("missing %s in %q", t, spec)
+			}
+			return t, v, nil
+		}
+		return "", "", fmt.Errorf("invalid scope type %q (use: user, group, domain, default)", t)
+	}
+
+	switch {
+	case strings.EqualFold(spec, "default") || strings.EqualFold(spec, "public"):
+		return "default", "", nil
+	case strings.Contains(spec, "@"):
+		return "user", spec, nil
+	case strings.Contains(spec, "."):
+		return "domain", spec, nil
+	}
+	return "", "", fmt.Errorf("invalid scope %q (use an email, a domain, or type:value)", spec)
+}
+
+// ACLRuleID returns the ID of the rule for a scope.
+func ACLRuleID(scopeType, value string) string {
+	if scopeType == "default" {
+		return "default"
+	}
+	return scopeType + ":" + value
+}
+
+func calendarToInfo(c *calendar.Calendar) *CalendarInfo {
+	return &CalendarInfo{
+		ID:          c.Id,
+		Summary:     c.Summary,
+		Description: c.Description,
+		TimeZone:    c.TimeZone,
+	}
+}
+
+func aclToRule(rule *calendar.AclRule) ACLRule {
+	result := ACLRule{ID: rule.Id, Role: rule.Role}
+	if rule.Scope != nil {
+		result.ScopeType = rule.Scope.Type
+		result.ScopeValue = rule.Scope.Value
+	}
+	return result
+}
@@ -0,0 +1,51 @@
+package calendar
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseACLScope(t *testing.T) {
+	tests := []struct {
+		spec, scopeType, value, ruleID string
+	}{
+		{"alice@example.com", "user", "alice@example.com", "user:alice@example.com"},
+		{"user:alice@example.com", "user", "alice@example.com", "user:alice@example.com"},
+		{"Group:team@example.com", "group", "team@example.com", "group:team@example.com"},
+		{"example.com", "domain", "example.com", "domain:example.com"},
+		{"domain:example.com", "domain", "example.com", "domain:example.com"},
+		{"public", "default", "", "default"},
+		{"default", "default", "", "default"},
+	}
+	for _, tt := range tests {
+		scopeType, value, err := ParseACLScope(tt.spec)
+		require.NoError(t, err, tt.spec)
+		assert.Equal(t, tt.scopeType, scopeType, tt.spec)
+		assert.Equal(t, tt.value, value, tt.spec)
+		assert.Equal(t, tt.ruleID, ACLRuleID(scopeType, value), tt.spec)
+	}
+
+	for _, spec := range []string{"alice", "team:x@example.com", "user:"} {
+		_, _, err := ParseACLScope(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestParseACLRole(t *testing.T) {
+	for spec, want := range map[string]string{
+		"freebusy":       RoleFreeBusyReader,
+		"freeBusyReader": RoleFreeBusyReader,
+		"Reader":         RoleReader,
+		"writer":         RoleWriter,
+		"owner":          RoleOwner,
+	} {
+		got, err := ParseACLRole(spec)
+		require.NoError(t, err, spec)
+		assert.Equal(t, want, got, spec)
+	}
+
+	_, err := ParseACLRole("admin")
+	assert.Error(t, err)
+}
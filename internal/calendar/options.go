@@ -0,0 +1,112 @@
+package calendar
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// EventColors maps event color names to Calendar API color IDs.
+var EventColors = map[string]string{
+	"lavender":  "1",
+	"sage":      "2",
+	"grape":     "3",
+	"flamingo":  "4",
+	"banana":    "5",
+	"tangerine": "6",
+	"peacock":   "7",
+	"graphite":  "8",
+	"blueberry": "9",
+	"basil":     "10",
+	"tomato":    "11",
+}
+
+// ParseColor returns the color ID for a color name or ID.
+func ParseColor(color string) (string, error) {
+	color = strings.ToLower(strings.TrimSpace(color))
+	if id, ok := EventColors[color]; ok {
+		return id, nil
+	}
+	if n, err := strconv.Atoi(color); err == nil && n >= 1 && n <= 11 {
+		return color, nil
+	}
+	return "", fmt.Errorf("invalid color: %s (use 1-11 or lavender, sage, grape, flamingo, banana, tangerine, peacock, graphite, blueberry, basil, tomato)", color)
+}
+
+// ParseReminder parses a reminder such as "10m:popup", "1h:email" or "2d".
+// The method defaults to popup.
+func ParseReminder(spec string) (ReminderInfo, error) {
+	value, method, ok := strings.Cut(strings.ToLower(strings.TrimSpace(spec)), ":")
+	if !ok {
+		method = "popup"
+	}
+	if method != "popup" && method != "email" {
+		return ReminderInfo{}, fmt.Errorf("invalid reminder method: %s (use popup or email)", method)
+	}
+
+	unit := int64(1)
+	switch {
+	case strings.HasSuffix(value, "m"):
+		value = strings.TrimSuffix(value, "m")
+	case strings.HasSuffix(value, "h"):
+		value, unit = strings.TrimSuffix(value, "h"), 60
+	case strings.HasSuffix(value, "d"):
+		value, unit = strings.TrimSuffix(value, "d"), 24*60
+	case strings.HasSuffix(value, "w"):
+		value, unit = strings.TrimSuffix(value, "w"), 7*24*60
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	// The API accepts reminders up to four weeks before the event.
+	if err != nil || n < 0 || n*unit > 40320 {
+		return ReminderInfo{}, fmt.Errorf("invalid reminder: %s (use e.g. 10m:popup, 1h:email, 2d)", spec)
+	}
+
+	return ReminderInfo{Method: method, Minutes: n * unit}, nil
+}
+
+// ParseSendUpdates normalizes who is notified of a change: all, external
+// (externalOnly) or none. Empty means all.
+func ParseSendUpdates(value string) (string, error) {
+	switch strings.ToLower(value) {
+	case "", "all":
+		return "all", nil
+	case "external", "externalonly":
+		return "externalOnly", nil
+	case "none":
+		return "none", nil
+	}
+	return "", fmt.Errorf("invalid send-updates: %s (use all, external, none)", value)
+}
+
+// ParseTransparency converts free/busy to the API's transparency values.
+func ParseTransparency(value string) (string, error) {
+	switch strings.ToLower(value) {
+	case "":
+		return "", nil
+	case "free", "transparent":
+		return "transparent", nil
+	case "busy", "opaque":
+		return "opaque", nil
+	}
+	return "", fmt.Errorf("invalid transparency: %s (use free or busy)", value)
+}
+
+// ParseVisibility validates an event visibility.
+func ParseVisibility(value string) (string, error) {
+	switch v := strings.ToLower(value); v {
+	case "", "default", "public", "private", "confidential":
+		return v, nil
+	}
+	return "", fmt.Errorf("invalid visibility: %s (use default, public, private, confidential)", value)
+}
+
+// newRequestID returns a random ID for conference create requests.
+func newRequestID() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,97 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReminder(t *testing.T) {
+	tests := []struct {
+		spec string
+		want ReminderInfo
+	}{
+		{"10m:popup", ReminderInfo{Method: "popup", Minutes: 10}},
+		{"1h:email", ReminderInfo{Method: "email", Minutes: 60}},
+		{"2d", ReminderInfo{Method: "popup", Minutes: 2880}},
+		{"1w:Email", ReminderInfo{Method: "email", Minutes: 10080}},
+		{"0", ReminderInfo{Method: "popup", Minutes: 0}},
+	}
+	for _, tt := range tests {
+		got, err := ParseReminder(tt.spec)
+		require.NoError(t, err, tt.spec)
+		assert.Equal(t, tt.want, got, tt.spec)
+	}
+
+	for _, spec := range []string{"10m:sms", "soon", "-5m", "5w"} {
+		_, err := ParseReminder(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestParseEventOptionValues(t *testing.T) {
+	color, err := ParseColor("Tomato")
+	require.NoError(t, err)
+	assert.Equal(t, "11", color)
+	color, err = ParseColor("7")
+	require.NoError(t, err)
+	assert.Equal(t, "7", color)
+	_, err = ParseColor("12")
+	assert.Error(t, err)
+
+	send, err := ParseSendUpdates("external")
+	require.NoError(t, err)
+	assert.Equal(t, "externalOnly", send)
+	send, err = ParseSendUpdates("")
+	require.NoError(t, err)
+	assert.Equal(t, "all", send)
+	_, err = ParseSendUpdates("some")
+	assert.Error(t, err)
+
+	transparency, err := ParseTransparency("free")
+	require.NoError(t, err)
+	assert.Equal(t, "transparent", transparency)
+	_, err = ParseTransparency("maybe")
+	assert.Error(t, err)
+
+	_, err = ParseVisibility("secret")
+	assert.Error(t, err)
+}
+
+func TestBuildEvent(t *testing.T) {
+	start := time.Date(2026, 10, 20, 14, 0, 0, 0, time.UTC)
+	event, err := buildEvent(CreateEventOptions{
+		Title:             "Planning",
+		Start:             start,
+		End:               start.Add(time.Hour),
+		Attendees:         []string{"a@example.com"},
+		OptionalAttendees: []string{"b@example.com"},
+		Meet:              true,
+		Reminders:         []ReminderInfo{{Method: "popup", Minutes: 0}},
+		ColorID:           "5",
+		Transparency:      "transparent",
+		GuestsCanModify:   true,
+	}, "Europe/Berlin")
+	require.NoError(t, err)
+
+	assert.Equal(t, "2026-10-20T14:00:00Z", event.Start.DateTime)
+	assert.Equal(t, "Europe/Berlin", event.Start.TimeZone)
+	require.Len(t, event.Attendees, 2)
+	assert.False(t, event.Attendees[0].Optional)
+	assert.True(t, event.Attendees[1].Optional)
+	require.NotNil(t, event.ConferenceData)
+	assert.Equal(t, "hangoutsMeet", event.ConferenceData.CreateRequest.ConferenceSolutionKey.Type)
+	assert.NotEmpty(t, event.ConferenceData.CreateRequest.RequestId)
+	require.NotNil(t, event.Reminders)
+	assert.False(t, event.Reminders.UseDefault)
+	assert.Equal(t, []string{"Minutes"}, event.Reminders.Overrides[0].ForceSendFields)
+	assert.Equal(t, "5", event.ColorId)
+	assert.True(t, event.GuestsCanModify)
+
+	plain, err := buildEvent(CreateEventOptions{Start: start, End: start.Add(time.Hour)}, "")
+	require.NoError(t, err)
+	assert.Nil(t, plain.ConferenceData)
+	assert.Nil(t, plain.Reminders)
+}
@@ -115,6 +115,24 @@ func (s *Service) Get(calendarID, eventID string) (*EventFull, error) {
 	return parseEventToFull(event), nil
 }
 
+// GetByICalUID returns the event with the given iCalendar UID, as found in
+// invitation emails. For recurring events this is the series.
+func (s *Service) GetByICalUID(calendarID, uid string) (*EventFull, error) {
+	if calendarID == "" {
+		calendarID = "primary"
+	}
+
+	resp, err := s.svc.Events.List(calendarID).ICalUID(uid).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find event: %w", err)
+	}
+	if len(resp.Items) == 0 {
+		return nil, fmt.Errorf("no event with iCalUID %s in calendar %s", uid, calendarID)
+	}
+
+	return parseEventToFull(resp.Items[0]), nil
+}
+
 // Find searches for events matching the query.
 func (s *Service) Find(query string, from, to time.Time) ([]EventSummary, error) {
 	events, _, err := s.List(ListOptions{
@@ -163,6 +181,8 @@ func (s *Service) FreeBusy(start, end time.Time, calendarIDs []string) (*FreeBus
 	freeSlots := calculateFreeSlots(start, end, busy)
 
 	return &FreeBusyResult{
+		Start:     start.Format(time.RFC3339),
+		End:       end.Format(time.RFC3339),
 		Busy:      busy,
 		FreeSlots: freeSlots,
 	}, nil
@@ -183,12 +203,39 @@ func (s *Service) Calendars() ([]CalendarInfo, error) {
 			Description: item.Description,
 			TimeZone:    item.TimeZone,
 			Primary:     item.Primary,
+			AccessRole:  item.AccessRole,
 		})
 	}
 
 	return calendars, nil
 }
 
+// timeZone returns the time zone of a calendar.
+func (s *Service) timeZone(calendarID string) (string, error) {
+	cal, err := s.svc.Calendars.Get(calendarID).Do()
+	if err != nil {
+		return "", fmt.Errorf("failed to get calendar: %w", err)
+	}
+	return cal.TimeZone, nil
+}
+
+// Location returns the time zone of a calendar, falling back to UTC if the
+// calendar's zone is unknown.
+func (s *Service) Location(calendarID string) (*time.Location, error) {
+	if calendarID == "" {
+		calendarID = "primary"
+	}
+	tz, err := s.timeZone(calendarID)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return time.UTC, nil
+	}
+	return loc, nil
+}
+
 // parseEventToSummary converts a calendar event to an EventSummary.
 func parseEventToSummary(event *calendar.Event) EventSummary {
 	start, end, allDay := parseEventTimes(event)
@@ -204,17 +251,20 @@ func parseEventToSummary(event *calendar.Event) EventSummary {
 	}
 
 	return EventSummary{
-		ID:          event.Id,
-		Title:       event.Summary,
-		Description: event.Description,
-		Location:    event.Location,
-		Start:       start,
-		End:         end,
-		AllDay:      allDay,
-		Status:      event.Status,
-		Attendees:   attendees,
-		HTMLLink:    event.HtmlLink,
-		Organizer:   organizer,
+		ID:              event.Id,
+		Title:           event.Summary,
+		Description:     event.Description,
+		Location:        event.Location,
+		Start:           start,
+		End:             end,
+		AllDay:          allDay,
+		Status:          event.Status,
+		Attendees:       attendees,
+		HTMLLink:        event.HtmlLink,
+		Organizer:       organizer,
+		RecurringID:     event.RecurringEventId,
+		EventType:       eventType(event),
+		WorkingLocation: workingLocationInfo(event),
 	}
 }
 
@@ -230,6 +280,8 @@ func parseEventToFull(event *calendar.Event) *EventFull {
 			ResponseStatus: att.ResponseStatus,
 			Organizer:      att.Organizer,
 			Self:           att.Self,
+			Optional:       att.Optional,
+			Resource:       att.Resource,
 		})
 	}
 
@@ -265,23 +317,26 @@ func parseEventToFull(event *calendar.Event) *EventFull {
 	}
 
 	return &EventFull{
-		ID:             event.Id,
-		Title:          event.Summary,
-		Description:    event.Description,
-		Location:       event.Location,
-		Start:          start,
-		End:            end,
-		AllDay:         allDay,
-		Status:         event.Status,
-		Attendees:      attendees,
-		HTMLLink:       event.HtmlLink,
-		Organizer:      organizer,
-		Recurrence:     event.Recurrence,
-		RecurringID:    event.RecurringEventId,
-		Created:        event.Created,
-		Updated:        event.Updated,
-		Reminders:      reminders,
-		ConferenceData: conference,
+		ID:              event.Id,
+		Title:           event.Summary,
+		Description:     event.Description,
+		Location:        event.Location,
+		Start:           start,
+		End:             end,
+		AllDay:          allDay,
+		Status:          event.Status,
+		Attendees:       attendees,
+		HTMLLink:        event.HtmlLink,
+		Organizer:       organizer,
+		Recurrence:      event.Recurrence,
+		RecurringID:     event.RecurringEventId,
+		Created:         event.Created,
+		Updated:         event.Updated,
+		Reminders:       reminders,
+		ConferenceData:  conference,
+		EventType:       eventType(event),
+		WorkingLocation: workingLocationInfo(event),
+		AutoDecline:     autoDeclineInfo(event),
 	}
 }
 
@@ -0,0 +1,427 @@
+package calendar
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+// Scopes for changes to recurring events.
+const (
+	ScopeThis      = "this"      // Only the given occurrence
+	ScopeFollowing = "following" // The given occurrence and all later ones
+	ScopeAll       = "all"       // The whole series
+)
+
+var weekdayCodes = map[string]string{
+	"mo": "MO", "mon": "MO", "monday": "MO",
+	"tu": "TU", "tue": "TU", "tues": "TU", "tuesday": "TU",
+	"we": "WE", "wed": "WE", "wednesday": "WE",
+	"th": "TH", "thu": "TH", "thur": "TH", "thurs": "TH", "thursday": "TH",
+	"fr": "FR", "fri": "FR", "friday": "FR",
+	"sa": "SA", "sat": "SA", "saturday": "SA",
+	"su": "SU", "sun": "SU", "sunday": "SU",
+}
+
+var ordinals = map[string]int{
+	"1st": 1, "first": 1, "2nd": 2, "second": 2, "3rd": 3, "third": 3,
+	"4th": 4, "fourth": 4, "5th": 5, "fifth": 5, "last": -1,
+}
+
+var frequencies = map[string]string{
+	"daily": "DAILY", "day": "DAILY", "days": "DAILY",
+	"weekly": "WEEKLY", "week": "WEEKLY", "weeks": "WEEKLY",
+	"monthly": "MONTHLY", "month": "MONTHLY", "months": "MONTHLY",
+	"yearly": "YEARLY", "annually": "YEARLY", "year": "YEARLY", "years": "YEARLY",
+}
+
+// ParseRepeat compiles a friendly repeat specification into an RRULE line.
+//
+// Supported forms include "daily", "weekdays", "weekly", "weekly on
+// mon,wed", "every 2 weeks on tue", "biweekly", "monthly", "monthly on 15",
+// "monthly on 2nd tue", "monthly on last fri" and "yearly", optionally
+// followed by "until YYYY-MM-DD" or "count N" / "N times". Strings that
+// already are an RRULE (or start with FREQ=) are passed through.
+//
+// UNTIL is inclusive of the given day in start's time zone.
+func ParseRepeat(spec string, start time.Time, allDay bool) (string, error) {
+	spec = strings.TrimSpace(spec)
+	upper := strings.ToUpper(spec)
+	if strings.HasPrefix(upper, "RRULE:") {
+		return "RRULE:" + spec[len("RRULE:"):], nil
+	}
+	if strings.HasPrefix(upper, "FREQ=") {
+		return "RRULE:" + spec, nil
+	}
+
+	tokens := strings.FieldsFunc(strings.ToLower(spec), func(r rune) bool {
+		return r == ' ' || r == ',' || r == ';' || r == '\t'
+	})
+	if len(tokens) == 0 {
+		return "", fmt.Errorf("empty repeat specification")
+	}
+
+	var freq, byDay, byMonthDay, until, count string
+	interval := 1
+
+	i := 0
+	next := func() string {
+		if i < len(tokens) {
+			i++
+			return tokens[i-1]
+		}
+		return ""
+	}
+
+	switch tok := next(); tok {
+	case "weekdays", "weekday":
+		freq, byDay = "WEEKLY", "MO,TU,WE,TH,FR"
+	case "biweekly", "fortnightly":
+		freq, interval = "WEEKLY", 2
+	case "every":
+		tok = next()
+		if n, err := strconv.Atoi(tok); err == nil && n > 0 {
+			interval = n
+			tok = next()
+		} else if tok == "other" {
+			interval = 2
+			tok = next()
+		}
+		if tok == "weekday" || tok == "weekdays" {
+			freq, byDay = "WEEKLY", "MO,TU,WE,TH,FR"
+			break
+		}
+		if code, ok := weekdayCodes[tok]; ok {
+			freq, byDay = "WEEKLY", code
+			break
+		}
+		if freq = frequencies[tok]; freq == "" {
+			return "", fmt.Errorf("unknown repeat frequency %q", tok)
+		}
+	default:
+		if freq = frequencies[tok]; freq == "" {
+			return "", fmt.Errorf("unknown repeat frequency %q (use daily, weekdays, weekly, monthly, yearly)", tok)
+		}
+	}
+
+	for i < len(tokens) {
+		switch tok := next(); tok {
+		case "on", "and", "the", "of", "month", "every":
+			continue
+		case "until":
+			date, err := time.ParseInLocation("2006-01-02", next(), start.Location())
+			if err != nil {
+				return "", fmt.Errorf("invalid until date (use YYYY-MM-DD)")
+			}
+			if allDay {
+				until = date.Format("20060102")
+			} else {
+				until = date.AddDate(0, 0, 1).Add(-time.Second).UTC().Format("20060102T150405Z")
+			}
+		case "count", "for":
+			n, err := strconv.Atoi(next())
+			if err != nil || n <= 0 {
+				return "", fmt.Errorf("invalid count")
+			}
+			count = strconv.Itoa(n)
+			if i < len(tokens) && (tokens[i] == "times" || tokens[i] == "occurrences") {
+				i++
+			}
+		default:
+			if n, err := strconv.Atoi(tok); err == nil {
+				if i < len(tokens) && (tokens[i] == "times" || tokens[i] == "occurrences") {
+					i++
+					count = strconv.Itoa(n)
+					continue
+				}
+				if freq != "MONTHLY" || n < 1 || n > 31 {
+					return "", fmt.Errorf("unexpected number %d in repeat specification", n)
+				}
+				byMonthDay = appendList(byMonthDay, strconv.Itoa(n))
+				continue
+			}
+			if n, ok := ordinals[tok]; ok {
+				day := weekdayCodes[next()]
+				if day == "" || freq != "MONTHLY" {
+					return "", fmt.Errorf("use ordinals like \"2nd tue\" with monthly repeats")
+				}
+				byDay = appendList(byDay, strconv.Itoa(n)+day)
+				continue
+			}
+			if code, ok := weekdayCodes[tok]; ok {
+				byDay = appendList(byDay, code)
+				continue
+			}
+			trimmed := strings.TrimRight(tok, "stndrh")
+			if n, err := strconv.Atoi(trimmed); err == nil && freq == "MONTHLY" && n >= 1 && n <= 31 {
+				byMonthDay = appendList(byMonthDay, strconv.Itoa(n))
+				continue
+			}
+			return "", fmt.Errorf("unexpected %q in repeat specification", tok)
+		}
+	}
+
+	if until != "" && count != "" {
+		return "", fmt.Errorf("use either until or count, not both")
+	}
+
+	parts := []string{"FREQ=" + freq}
+	if interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(interval))
+	}
+	if byDay != "" {
+		parts = append(parts, "BYDAY="+byDay)
+	}
+	if byMonthDay != "" {
+		parts = append(parts, "BYMONTHDAY="+byMonthDay)
+	}
+	if count != "" {
+		parts = append(parts, "COUNT="+count)
+	}
+	if until != "" {
+		parts = append(parts, "UNTIL="+until)
+	}
+
+	return "RRULE:" + strings.Join(parts, ";"), nil
+}
+
+func appendList(list, item string) string {
+	if list == "" {
+		return item
+	}
+	return list + "," + item
+}
+
+// truncateRecurrence ends every RRULE before the given time by replacing
+// COUNT/UNTIL with an UNTIL just before it.
+func truncateRecurrence(recurrence []string, before time.Time, allDay bool) []string {
+	until := before.Add(-time.Second).UTC().Format("20060102T150405Z")
+	if allDay {
+		until = before.AddDate(0, 0, -1).Format("20060102")
+	}
+
+	result := make([]string, 0, len(recurrence))
+	for _, line := range recurrence {
+		if !strings.HasPrefix(strings.ToUpper(line), "RRULE:") {
+			result = append(result, line)
+			continue
+		}
+		parts := rruleParts(line)
+		parts = append(parts, "UNTIL="+until)
+		result = append(result, "RRULE:"+strings.Join(parts, ";"))
+	}
+	return result
+}
+
+// remainingRecurrence returns the recurrence of a series continuing after
+// done occurrences have been split off: COUNT is reduced accordingly and
+// UNTIL is kept.
+func remainingRecurrence(recurrence []string, done int) []string {
+	result := make([]string, 0, len(recurrence))
+	for _, line := range recurrence {
+		if !strings.HasPrefix(strings.ToUpper(line), "RRULE:") {
+			result = append(result, line)
+			continue
+		}
+		var parts []string
+		for _, p := range strings.Split(line[len("RRULE:"):], ";") {
+			if strings.HasPrefix(strings.ToUpper(p), "COUNT=") {
+				n, err := strconv.Atoi(p[len("COUNT="):])
+				if err == nil {
+					p = "COUNT=" + strconv.Itoa(max(n-done, 1))
+				}
+			}
+			parts = append(parts, p)
+		}
+		result = append(result, "RRULE:"+strings.Join(parts, ";"))
+	}
+	return result
+}
+
+// rruleParts returns the parts of an RRULE line without COUNT and UNTIL.
+func rruleParts(line string) []string {
+	var parts []string
+	for _, p := range strings.Split(line[len("RRULE:"):], ";") {
+		upper := strings.ToUpper(p)
+		if p == "" || strings.HasPrefix(upper, "COUNT=") || strings.HasPrefix(upper, "UNTIL=") {
+			continue
+		}
+		parts = append(parts, p)
+	}
+	return parts
+}
+
+// Instances returns the occurrences of a recurring event between from and
+// to (either may be zero).
+func (s *Service) Instances(calendarID, eventID string, from, to time.Time, maxResults int64) ([]EventSummary, error) {
+	if calendarID == "" {
+		calendarID = "primary"
+	}
+
+	call := s.svc.Events.Instances(calendarID, eventID)
+	if !from.IsZero() {
+		call = call.TimeMin(from.Format(time.RFC3339))
+	}
+	if !to.IsZero() {
+		call = call.TimeMax(to.Format(time.RFC3339))
+	}
+	if maxResults > 0 {
+		call = call.MaxResults(maxResults)
+	}
+
+	resp, err := call.Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list instances: %w", err)
+	}
+
+	events := make([]EventSummary, 0, len(resp.Items))
+	for _, item := range resp.Items {
+		events = append(events, parseEventToSummary(item))
+	}
+	return events, nil
+}
+
+// resolveSeries returns the occurrence and its series for an instance ID,
+// or nil and the series itself for a series ID.
+func (s *Service) resolveSeries(calendarID, eventID string) (instance, series *calendar.Event, err error) {
+	event, err := s.svc.Events.Get(calendarID, eventID).Do()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get event: %w", err)
+	}
+	if event.RecurringEventId == "" {
+		return nil, event, nil
+	}
+
+	series, err = s.svc.Events.Get(calendarID, event.RecurringEventId).Do()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get recurring event: %w", err)
+	}
+	return event, series, nil
+}
+
+// originalStart returns the time an occurrence was originally scheduled.
+func originalStart(instance *calendar.Event) (time.Time, bool, error) {
+	ost := instance.OriginalStartTime
+	if ost == nil {
+		ost = instance.Start
+	}
+	if ost.Date != "" {
+		t, err := time.Parse("2006-01-02", ost.Date)
+		return t, true, err
+	}
+	t, err := time.Parse(time.RFC3339, ost.DateTime)
+	return t, false, err
+}
+
+// atSeriesStart reports whether an occurrence is the first of its series,
+// in which case splitting the series there would leave nothing before it.
+func atSeriesStart(instance, series *calendar.Event) (bool, error) {
+	split, _, err := originalStart(instance)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse occurrence start: %w", err)
+	}
+	seriesStart, _, err := originalStart(series)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse series start: %w", err)
+	}
+	return !split.After(seriesStart), nil
+}
+
+// seriesRemainder returns the original start of an occurrence and the
+// recurrence a series continuing from it should use.
+func (s *Service) seriesRemainder(calendarID string, instance, series *calendar.Event) (time.Time, bool, []string, error) {
+	start, allDay, err := originalStart(instance)
+	if err != nil {
+		return time.Time{}, false, nil, fmt.Errorf("failed to parse occurrence start: %w", err)
+	}
+
+	// Count the occurrences before the split for COUNT-based rules.
+	done := 0
+	if hasCount(series.Recurrence) {
+		pageToken := ""
+		for {
+			call := s.svc.Events.Instances(calendarID, series.Id).
+				TimeMax(start.Format(time.RFC3339)).ShowDeleted(true).MaxResults(2500)
+			if pageToken != "" {
+				call = call.PageToken(pageToken)
+			}
+			before, err := call.Do()
+			if err != nil {
+				return time.Time{}, false, nil, fmt.Errorf("failed to list instances: %w", err)
+			}
+			done += len(before.Items)
+			if before.NextPageToken == "" {
+				break
+			}
+			pageToken = before.NextPageToken
+		}
+	}
+
+	return start, allDay, remainingRecurrence(series.Recurrence, done), nil
+}
+
+// followingSeries returns a copy of a series with the given recurrence, to
+// continue it from a split. The conference is kept. Guests are invited
+// afresh, so their responses are reset to needsAction; the organizer's
+// response is kept.
+func followingSeries(series *calendar.Event, recurrence []string) *calendar.Event {
+	var attendees []*calendar.EventAttendee
+	for _, a := range series.Attendees {
+		copied := *a
+		if !a.Organizer {
+			copied.ResponseStatus = "needsAction"
+			copied.Comment = ""
+		}
+		attendees = append(attendees, &copied)
+	}
+
+	var conference *calendar.ConferenceData
+	if series.ConferenceData != nil {
+		conference = &calendar.ConferenceData{
+			ConferenceId:       series.ConferenceData.ConferenceId,
+			ConferenceSolution: series.ConferenceData.ConferenceSolution,
+			EntryPoints:        series.ConferenceData.EntryPoints,
+			Notes:              series.ConferenceData.Notes,
+			Parameters:         series.ConferenceData.Parameters,
+		}
+	}
+
+	return &calendar.Event{
+		Summary:                 series.Summary,
+		Description:             series.Description,
+		Location:                series.Location,
+		Attendees:               attendees,
+		ConferenceData:          conference,
+		Reminders:               series.Reminders,
+		ColorId:                 series.ColorId,
+		Visibility:              series.Visibility,
+		Transparency:            series.Transparency,
+		GuestsCanModify:         series.GuestsCanModify,
+		GuestsCanInviteOthers:   series.GuestsCanInviteOthers,
+		GuestsCanSeeOtherGuests: series.GuestsCanSeeOtherGuests,
+		Start:                   &calendar.EventDateTime{Date: series.Start.Date, TimeZone: series.Start.TimeZone},
+		End:                     &calendar.EventDateTime{Date: series.End.Date, TimeZone: series.End.TimeZone},
+		Recurrence:              recurrence,
+	}
+}
+
+// endSeries ends a series before the given occurrence start.
+func (s *Service) endSeries(calendarID string, series *calendar.Event, before time.Time, allDay bool, sendUpdates string) error {
+	patch := &calendar.Event{Recurrence: truncateRecurrence(series.Recurrence, before, allDay)}
+	if _, err := s.svc.Events.Patch(calendarID, series.Id, patch).SendUpdates(sendUpdates).Do(); err != nil {
+		return fmt.Errorf("failed to end recurring event: %w", err)
+	}
+	return nil
+}
+
+func hasCount(recurrence []string) bool {
+	for _, line := range recurrence {
+		if strings.HasPrefix(strings.ToUpper(line), "RRULE:") && strings.Contains(strings.ToUpper(line), "COUNT=") {
+			return true
+		}
+	}
+	return false
+}
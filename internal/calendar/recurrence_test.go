@@ -0,0 +1,124 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/calendar/v3"
+)
+
+func TestParseRepeat(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	start := time.Date(2026, 10, 20, 9, 0, 0, 0, berlin)
+
+	tests := []struct {
+		spec string
+		want string
+	}{
+		{"daily", "RRULE:FREQ=DAILY"},
+		{"weekdays", "RRULE:FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR"},
+		{"weekly", "RRULE:FREQ=WEEKLY"},
+		{"weekly on Mon,Wed", "RRULE:FREQ=WEEKLY;BYDAY=MO,WE"},
+		{"weekly on mon and thursday", "RRULE:FREQ=WEEKLY;BYDAY=MO,TH"},
+		{"every 2 weeks on tue", "RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=TU"},
+		{"biweekly", "RRULE:FREQ=WEEKLY;INTERVAL=2"},
+		{"every friday", "RRULE:FREQ=WEEKLY;BYDAY=FR"},
+		{"every 3 days count 5", "RRULE:FREQ=DAILY;INTERVAL=3;COUNT=5"},
+		{"monthly", "RRULE:FREQ=MONTHLY"},
+		{"monthly on 2nd tue", "RRULE:FREQ=MONTHLY;BYDAY=2TU"},
+		{"monthly on the last fri", "RRULE:FREQ=MONTHLY;BYDAY=-1FR"},
+		{"monthly on 15th", "RRULE:FREQ=MONTHLY;BYMONTHDAY=15"},
+		{"yearly 10 times", "RRULE:FREQ=YEARLY;COUNT=10"},
+		{"weekly on mon until 2026-12-31", "RRULE:FREQ=WEEKLY;BYDAY=MO;UNTIL=20261231T225959Z"},
+		{"FREQ=DAILY;COUNT=3", "RRULE:FREQ=DAILY;COUNT=3"},
+		{"RRULE:FREQ=WEEKLY", "RRULE:FREQ=WEEKLY"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParseRepeat(tt.spec, start, false)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	got, err := ParseRepeat("daily until 2026-12-31", start, true)
+	require.NoError(t, err)
+	assert.Equal(t, "RRULE:FREQ=DAILY;UNTIL=20261231", got)
+
+	for _, spec := range []string{"", "hourly", "weekly on 2nd tue", "daily until tomorrow", "daily count 3 until 2026-12-31", "weekly 15"} {
+		_, err := ParseRepeat(spec, start, false)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestTruncateRecurrence(t *testing.T) {
+	before := time.Date(2026, 11, 2, 9, 0, 0, 0, time.UTC)
+	rules := []string{"RRULE:FREQ=WEEKLY;BYDAY=MO;COUNT=10", "EXDATE:20261026T090000Z"}
+
+	assert.Equal(t,
+		[]string{"RRULE:FREQ=WEEKLY;BYDAY=MO;UNTIL=20261102T085959Z", "EXDATE:20261026T090000Z"},
+		truncateRecurrence(rules, before, false))
+	assert.Equal(t,
+		[]string{"RRULE:FREQ=WEEKLY;BYDAY=MO;UNTIL=20261101", "EXDATE:20261026T090000Z"},
+		truncateRecurrence(rules, before, true))
+}
+
+func TestAtSeriesStart(t *testing.T) {
+	series := &calendar.Event{Start: &calendar.EventDateTime{DateTime: "2026-10-19T09:00:00+02:00"}}
+	occurrence := func(start string) *calendar.Event {
+		return &calendar.Event{OriginalStartTime: &calendar.EventDateTime{DateTime: start}}
+	}
+
+	first, err := atSeriesStart(occurrence("2026-10-19T07:00:00Z"), series)
+	require.NoError(t, err)
+	assert.True(t, first)
+
+	first, err = atSeriesStart(occurrence("2026-10-26T09:00:00+01:00"), series)
+	require.NoError(t, err)
+	assert.False(t, first)
+
+	allDay := &calendar.Event{Start: &calendar.EventDateTime{Date: "2026-10-19"}}
+	first, err = atSeriesStart(&calendar.Event{OriginalStartTime: &calendar.EventDateTime{Date: "2026-10-19"}}, allDay)
+	require.NoError(t, err)
+	assert.True(t, first)
+}
+
+func TestRemainingRecurrence(t *testing.T) {
+	assert.Equal(t,
+		[]string{"RRULE:FREQ=WEEKLY;COUNT=7"},
+		remainingRecurrence([]string{"RRULE:FREQ=WEEKLY;COUNT=10"}, 3))
+	assert.Equal(t,
+		[]string{"RRULE:FREQ=DAILY;UNTIL=20261231T000000Z"},
+		remainingRecurrence([]string{"RRULE:FREQ=DAILY;UNTIL=20261231T000000Z"}, 0))
+}
+
+func TestFollowingSeries(t *testing.T) {
+	series := &calendar.Event{
+		Summary: "Weekly sync",
+		Start:   &calendar.EventDateTime{DateTime: "2026-10-05T09:00:00+02:00", TimeZone: "Europe/Berlin"},
+		End:     &calendar.EventDateTime{DateTime: "2026-10-05T09:30:00+02:00", TimeZone: "Europe/Berlin"},
+		Attendees: []*calendar.EventAttendee{
+			{Email: "me@example.com", Organizer: true, ResponseStatus: "accepted"},
+			{Email: "bob@example.com", ResponseStatus: "declined", Comment: "conflict"},
+		},
+		ConferenceData: &calendar.ConferenceData{
+			ConferenceId: "abc-defg-hij",
+			EntryPoints:  []*calendar.EntryPoint{{EntryPointType: "video", Uri: "https://meet.google.com/abc-defg-hij"}},
+		},
+	}
+
+	next := followingSeries(series, []string{"RRULE:FREQ=WEEKLY"})
+	assert.Equal(t, []string{"RRULE:FREQ=WEEKLY"}, next.Recurrence)
+	require.NotNil(t, next.ConferenceData)
+	assert.Equal(t, "abc-defg-hij", next.ConferenceData.ConferenceId)
+	assert.Nil(t, next.ConferenceData.CreateRequest)
+	require.Len(t, next.Attendees, 2)
+	assert.Equal(t, "accepted", next.Attendees[0].ResponseStatus)
+	assert.Equal(t, "needsAction", next.Attendees[1].ResponseStatus)
+	assert.Empty(t, next.Attendees[1].Comment)
+	assert.Equal(t, "declined", series.Attendees[1].ResponseStatus, "original series is not modified")
+}
@@ -26,38 +26,44 @@ func NewService(ctx context.Context, client *http.Client) (*Service, error) {
 
 // EventSummary represents a summary of a calendar event.
 type EventSummary struct {
-	ID          string   `json:"id"`
-	Title       string   `json:"title"`
-	Description string   `json:"description,omitempty"`
-	Location    string   `json:"location,omitempty"`
-	Start       string   `json:"start"`
-	End         string   `json:"end"`
-	AllDay      bool     `json:"all_day"`
-	Status      string   `json:"status"`
-	Attendees   []string `json:"attendees,omitempty"`
-	HTMLLink    string   `json:"html_link"`
-	Organizer   string   `json:"organizer,omitempty"`
+	ID              string               `json:"id"`
+	Title           string               `json:"title"`
+	Description     string               `json:"description,omitempty"`
+	Location        string               `json:"location,omitempty"`
+	Start           string               `json:"start"`
+	End             string               `json:"end"`
+	AllDay          bool                 `json:"all_day"`
+	Status          string               `json:"status"`
+	Attendees       []string             `json:"attendees,omitempty"`
+	HTMLLink        string               `json:"html_link"`
+	Organizer       string               `json:"organizer,omitempty"`
+	RecurringID     string               `json:"recurring_event_id,omitempty"`
+	EventType       string               `json:"event_type"`
+	WorkingLocation *WorkingLocationInfo `json:"working_location,omitempty"`
 }
 
 // EventFull represents a full calendar event.
 type EventFull struct {
-	ID              string           `json:"id"`
-	Title           string           `json:"title"`
-	Description     string           `json:"description,omitempty"`
-	Location        string           `json:"location,omitempty"`
-	Start           string           `json:"start"`
-	End             string           `json:"end"`
-	AllDay          bool             `json:"all_day"`
-	Status          string           `json:"status"`
-	Attendees       []AttendeeInfo   `json:"attendees,omitempty"`
-	HTMLLink        string           `json:"html_link"`
-	Organizer       string           `json:"organizer,omitempty"`
-	Recurrence      []string         `json:"recurrence,omitempty"`
-	RecurringID     string           `json:"recurring_event_id,omitempty"`
-	Created         string           `json:"created"`
-	Updated         string           `json:"updated"`
-	Reminders       *RemindersInfo   `json:"reminders,omitempty"`
-	ConferenceData  *ConferenceInfo  `json:"conference_data,omitempty"`
+	ID              string               `json:"id"`
+	Title           string               `json:"title"`
+	Description     string               `json:"description,omitempty"`
+	Location        string               `json:"location,omitempty"`
+	Start           string               `json:"start"`
+	End             string               `json:"end"`
+	AllDay          bool                 `json:"all_day"`
+	Status          string               `json:"status"`
+	Attendees       []AttendeeInfo       `json:"attendees,omitempty"`
+	HTMLLink        string               `json:"html_link"`
+	Organizer       string               `json:"organizer,omitempty"`
+	Recurrence      []string             `json:"recurrence,omitempty"`
+	RecurringID     string               `json:"recurring_event_id,omitempty"`
+	Created         string               `json:"created"`
+	Updated         string               `json:"updated"`
+	Reminders       *RemindersInfo       `json:"reminders,omitempty"`
+	ConferenceData  *ConferenceInfo      `json:"conference_data,omitempty"`
+	EventType       string               `json:"event_type"`
+	WorkingLocation *WorkingLocationInfo `json:"working_location,omitempty"`
+	AutoDecline     *AutoDeclineInfo     `json:"auto_decline,omitempty"`
 }
 
 // AttendeeInfo represents information about an event attendee.
@@ -67,6 +73,8 @@ type AttendeeInfo struct {
 	ResponseStatus string `json:"response_status"`
 	Organizer      bool   `json:"organizer,omitempty"`
 	Self           bool   `json:"self,omitempty"`
+	Optional       bool   `json:"optional,omitempty"`
+	Resource       bool   `json:"resource,omitempty"`
 }
 
 // RemindersInfo represents reminder settings.
@@ -100,6 +108,7 @@ type CalendarInfo struct {
 	Description string `json:"description,omitempty"`
 	TimeZone    string `json:"time_zone,omitempty"`
 	Primary     bool   `json:"primary,omitempty"`
+	AccessRole  string `json:"access_role,omitempty"`
 }
 
 // FreeBusySlot represents a busy time slot.
@@ -110,6 +119,8 @@ type FreeBusySlot struct {
 
 // FreeBusyResult represents the result of a free/busy query.
 type FreeBusyResult struct {
+	Start     string         `json:"start"`
+	End       string         `json:"end"`
 	Busy      []FreeBusySlot `json:"busy"`
 	FreeSlots []FreeBusySlot `json:"free_slots,omitempty"`
 }
@@ -118,4 +129,7 @@ type FreeBusyResult struct {
 type CreateEventResult struct {
 	EventID  string `json:"event_id"`
 	HTMLLink string `json:"html_link"`
+	MeetLink string `json:"meet_link,omitempty"`
+	Start    string `json:"start,omitempty"`
+	End      string `json:"end,omitempty"`
 }
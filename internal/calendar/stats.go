@@ -0,0 +1,365 @@
+package calendar
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+// defaultTopCoAttendees is the number of co-attendees returned by Stats.
+const defaultTopCoAttendees = 10
+
+// minFocusBlock is the length a free block must exceed to be counted by
+// the fragmentation metric.
+const minFocusBlock = time.Hour
+
+// StatsOptions contains options for computing meeting statistics.
+type StatsOptions struct {
+	CalendarID string
+	From       time.Time
+	To         time.Time
+	Location   *time.Location // Days and weeks are counted in this zone
+	Hours      WorkingHours   // Working day used for fragmentation
+	Top        int            // Number of top co-attendees (default 10)
+}
+
+// MeetingStats summarizes the meetings in a date range. A meeting is a
+// timed event with at least one other guest that you have not declined.
+type MeetingStats struct {
+	From           string        `json:"from"`
+	To             string        `json:"to"`
+	TimeZone       string        `json:"time_zone"`
+	Domain         string        `json:"domain,omitempty"`
+	Meetings       int           `json:"meetings"`
+	MeetingHours   float64       `json:"meeting_hours"`
+	PerDay         []PeriodStats `json:"per_day"`
+	PerWeek        []PeriodStats `json:"per_week"`
+	BySize         SizeStats     `json:"by_size"`
+	RecurringHours float64       `json:"recurring_hours"`
+	OneOffHours    float64       `json:"one_off_hours"`
+	TopAttendees   []CoAttendee  `json:"top_co_attendees"`
+	ExternalHours  float64       `json:"external_hours"`
+	ExternalShare  float64       `json:"external_share"`
+	Fragmentation  Fragmentation `json:"fragmentation"`
+	Responses      ResponseStats `json:"responses"`
+}
+
+// PeriodStats is the meeting load of a day (2026-10-19) or ISO week
+// (2026-W43).
+type PeriodStats struct {
+	Period   string  `json:"period"`
+	Meetings int     `json:"meetings"`
+	Hours    float64 `json:"hours"`
+}
+
+// SizeStats counts meetings by number of participants, including you.
+type SizeStats struct {
+	OneOnOne int `json:"one_on_one"` // 2
+	Small    int `json:"small"`      // 3-5
+	Medium   int `json:"medium"`     // 6-10
+	Large    int `json:"large"`      // 11+
+}
+
+// CoAttendee is someone you share meetings with.
+type CoAttendee struct {
+	Email    string  `json:"email"`
+	Meetings int     `json:"meetings"`
+	Hours    float64 `json:"hours"`
+}
+
+// Fragmentation counts free blocks longer than an hour within working
+// hours on working days.
+type Fragmentation struct {
+	Workdays         int     `json:"workdays"`
+	FreeBlocksOver1h int     `json:"free_blocks_over_1h"`
+	PerWorkday       float64 `json:"per_workday"`
+	FreeHours        float64 `json:"free_hours"`
+}
+
+// ResponseStats summarizes your responses to invitations from others.
+type ResponseStats struct {
+	Invitations int     `json:"invitations"`
+	Accepted    int     `json:"accepted"`
+	Declined    int     `json:"declined"`
+	Tentative   int     `json:"tentative"`
+	NeedsAction int     `json:"needs_action"`
+	DeclineRate float64 `json:"decline_rate"`
+}
+
+// Stats computes meeting statistics for a date range.
+func (s *Service) Stats(opts StatsOptions) (*MeetingStats, error) {
+	calendarID := opts.CalendarID
+	if calendarID == "" {
+		calendarID = "primary"
+	}
+	if !opts.To.After(opts.From) {
+		return nil, fmt.Errorf("empty date range")
+	}
+
+	events, err := s.listRange(calendarID, opts.From, opts.To)
+	if err != nil {
+		return nil, err
+	}
+	return computeStats(events, opts), nil
+}
+
+// computeStats computes meeting statistics from single events.
+func computeStats(events []*calendar.Event, opts StatsOptions) *MeetingStats {
+	loc := opts.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	top := opts.Top
+	if top <= 0 {
+		top = defaultTopCoAttendees
+	}
+
+	stats := &MeetingStats{
+		From:         opts.From.In(loc).Format(time.RFC3339),
+		To:           opts.To.In(loc).Format(time.RFC3339),
+		TimeZone:     loc.String(),
+		PerDay:       []PeriodStats{},
+		PerWeek:      []PeriodStats{},
+		TopAttendees: []CoAttendee{},
+	}
+	stats.Domain = selfDomain(events)
+
+	days := map[string]*PeriodStats{}
+	weeks := map[string]*PeriodStats{}
+	co := map[string]*CoAttendee{}
+	var busy []interval
+	var externalHours float64
+
+	for _, e := range events {
+		if e.Status == "cancelled" {
+			continue
+		}
+		full := parseEventToFull(e)
+
+		// Responses to others' invitations, including all-day ones.
+		var self *AttendeeInfo
+		for i := range full.Attendees {
+			if full.Attendees[i].Self {
+				self = &full.Attendees[i]
+			}
+		}
+		if self != nil && !self.Organizer {
+			stats.Responses.Invitations++
+			switch self.ResponseStatus {
+			case "accepted":
+				stats.Responses.Accepted++
+			case "declined":
+				stats.Responses.Declined++
+			case "tentative":
+				stats.Responses.Tentative++
+			default:
+				stats.Responses.NeedsAction++
+			}
+		}
+
+		if full.AllDay || (self != nil && self.ResponseStatus == "declined") {
+			continue
+		}
+		start, end, ok := eventInterval(e, loc)
+		if !ok || !end.After(start) {
+			continue
+		}
+		if blocksTime(e, nil) {
+			busy = append(busy, interval{start, end})
+		}
+
+		var others []AttendeeInfo
+		for _, a := range full.Attendees {
+			if !a.Self && !a.Resource && a.ResponseStatus != "declined" {
+				others = append(others, a)
+			}
+		}
+		if len(others) == 0 {
+			continue
+		}
+
+		hours := end.Sub(start).Hours()
+		stats.Meetings++
+		stats.MeetingHours += hours
+		if full.RecurringID != "" {
+			stats.RecurringHours += hours
+		} else {
+			stats.OneOffHours += hours
+		}
+
+		switch size := len(others) + 1; {
+		case size == 2:
+			stats.BySize.OneOnOne++
+		case size <= 5:
+			stats.BySize.Small++
+		case size <= 10:
+			stats.BySize.Medium++
+		default:
+			stats.BySize.Large++
+		}
+
+		local := start.In(loc)
+		addPeriod(days, local.Format("2006-01-02"), hours)
+		year, week := local.ISOWeek()
+		addPeriod(weeks, fmt.Sprintf("%d-W%02d", year, week), hours)
+
+		external := false
+		for _, a := range others {
+			email := strings.ToLower(a.Email)
+			c, ok := co[email]
+			if !ok {
+				c = &CoAttendee{Email: email}
+				co[email] = c
+			}
+			c.Meetings++
+			c.Hours += hours
+			if stats.Domain != "" && emailDomain(email) != stats.Domain {
+				external = true
+			}
+		}
+		if external {
+			externalHours += hours
+		}
+	}
+
+	stats.PerDay = sortedPeriods(days)
+	stats.PerWeek = sortedPeriods(weeks)
+
+	for _, c := range co {
+		c.Hours = roundHours(c.Hours)
+		stats.TopAttendees = append(stats.TopAttendees, *c)
+	}
+	sort.Slice(stats.TopAttendees, func(i, j int) bool {
+		a, b := stats.TopAttendees[i], stats.TopAttendees[j]
+		if a.Meetings != b.Meetings {
+			return a.Meetings > b.Meetings
+		}
+		if a.Hours != b.Hours {
+			return a.Hours > b.Hours
+		}
+		return a.Email < b.Email
+	})
+	stats.TopAttendees = stats.TopAttendees[:min(top, len(stats.TopAttendees))]
+
+	if stats.MeetingHours > 0 {
+		stats.ExternalShare = roundRatio(externalHours / stats.MeetingHours)
+	}
+	if stats.Responses.Invitations > 0 {
+		stats.Responses.DeclineRate = roundRatio(float64(stats.Responses.Declined) / float64(stats.Responses.Invitations))
+	}
+	stats.ExternalHours = roundHours(externalHours)
+	stats.MeetingHours = roundHours(stats.MeetingHours)
+	stats.RecurringHours = roundHours(stats.RecurringHours)
+	stats.OneOffHours = roundHours(stats.OneOffHours)
+	stats.Fragmentation = fragmentation(busy, opts.From, opts.To, opts.Hours)
+
+	return stats
+}
+
+// fragmentation counts the free blocks longer than an hour within the
+// working hours of each working day in the range.
+func fragmentation(busy []interval, from, to time.Time, wh WorkingHours) Fragmentation {
+	var f Fragmentation
+	sort.Slice(busy, func(i, j int) bool { return busy[i].start.Before(busy[j].start) })
+
+	var freeHours float64
+	first := from.In(wh.Location())
+	for day := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, wh.Location()); day.Before(to); day = day.AddDate(0, 0, 1) {
+		if !wh.Days[day.Weekday()] {
+			continue
+		}
+		dayStart, dayEnd := wh.at(day, wh.Start), wh.at(day, wh.End)
+		if dayStart.Before(from) || dayEnd.After(to) {
+			// Only whole working days are counted.
+			continue
+		}
+		f.Workdays++
+
+		free := dayStart
+		for _, b := range append(busy, interval{dayEnd, dayEnd}) {
+			if !b.end.After(free) || b.start.After(dayEnd) {
+				continue
+			}
+			if gap := minTime(b.start, dayEnd).Sub(free); gap > minFocusBlock {
+				f.FreeBlocksOver1h++
+				freeHours += gap.Hours()
+			}
+			if b.end.After(free) {
+				free = b.end
+			}
+		}
+	}
+
+	if f.Workdays > 0 {
+		f.PerWorkday = roundRatio(float64(f.FreeBlocksOver1h) / float64(f.Workdays))
+	}
+	f.FreeHours = roundHours(freeHours)
+	return f
+}
+
+// selfDomain returns the email domain of the calendar owner, taken from
+// the first event that lists you as an attendee or organizer.
+func selfDomain(events []*calendar.Event) string {
+	for _, e := range events {
+		for _, a := range e.Attendees {
+			if a.Self {
+				return emailDomain(a.Email)
+			}
+		}
+		if e.Organizer != nil && e.Organizer.Self {
+			return emailDomain(e.Organizer.Email)
+		}
+	}
+	return ""
+}
+
+func emailDomain(email string) string {
+	_, domain, _ := strings.Cut(strings.ToLower(email), "@")
+	return domain
+}
+
+func addPeriod(periods map[string]*PeriodStats, key string, hours float64) {
+	p, ok := periods[key]
+	if !ok {
+		p = &PeriodStats{Period: key}
+		periods[key] = p
+	}
+	p.Meetings++
+	p.Hours += hours
+}
+
+func sortedPeriods(periods map[string]*PeriodStats) []PeriodStats {
+	result := make([]PeriodStats, 0, len(periods))
+	for _, p := range periods {
+		p.Hours = roundHours(p.Hours)
+		result = append(result, *p)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Period < result[j].Period })
+	return result
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func roundHours(h float64) float64 {
+	return math.Round(h*100) / 100
+}
+
+func roundRatio(r float64) float64 {
+	return math.Round(r*1000) / 1000
+}
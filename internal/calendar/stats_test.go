@@ -0,0 +1,96 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/calendar/v3"
+)
+
+func TestComputeStats(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	hours, err := ParseWorkingHours("09:00-17:00 mon-fri", berlin)
+	require.NoError(t, err)
+
+	event := func(id, start, end string, attendees ...*calendar.EventAttendee) *calendar.Event {
+		return &calendar.Event{
+			Id: id, Status: "confirmed", Attendees: attendees,
+			Start: &calendar.EventDateTime{DateTime: start},
+			End:   &calendar.EventDateTime{DateTime: end},
+		}
+	}
+	me := func(status string, organizer bool) *calendar.EventAttendee {
+		return &calendar.EventAttendee{Email: "me@example.com", Self: true, ResponseStatus: status, Organizer: organizer}
+	}
+	guest := func(email string) *calendar.EventAttendee {
+		return &calendar.EventAttendee{Email: email, ResponseStatus: "accepted"}
+	}
+
+	oneOnOne := event("1on1_20261019", "2026-10-19T09:00:00+02:00", "2026-10-19T10:00:00+02:00",
+		me("accepted", true), guest("alice@example.com"))
+	oneOnOne.RecurringEventId = "1on1"
+	vendor := event("vendor", "2026-10-19T13:00:00+02:00", "2026-10-19T14:30:00+02:00",
+		me("accepted", false), guest("bob@example.com"), guest("Vendor@Partner.org"))
+	allHands := event("allhands", "2026-10-20T10:00:00+02:00", "2026-10-20T11:00:00+02:00",
+		me("declined", false), guest("carol@example.com"))
+	focus := event("focus", "2026-10-20T14:00:00+02:00", "2026-10-20T16:00:00+02:00")
+	sync := event("sync", "2026-10-20T09:00:00+02:00", "2026-10-20T09:30:00+02:00",
+		me("accepted", true), guest("alice@example.com"),
+		&calendar.EventAttendee{Email: "room-1@resource.calendar.google.com", Resource: true, ResponseStatus: "accepted"})
+	holiday := &calendar.Event{
+		Id: "holiday", Status: "confirmed",
+		Start: &calendar.EventDateTime{Date: "2026-10-20"},
+		End:   &calendar.EventDateTime{Date: "2026-10-21"},
+	}
+	cancelled := event("cancelled", "2026-10-19T11:00:00+02:00", "2026-10-19T12:00:00+02:00",
+		me("accepted", true), guest("alice@example.com"))
+	cancelled.Status = "cancelled"
+
+	stats := computeStats([]*calendar.Event{oneOnOne, vendor, allHands, focus, sync, holiday, cancelled}, StatsOptions{
+		From:     time.Date(2026, 10, 19, 0, 0, 0, 0, berlin),
+		To:       time.Date(2026, 10, 21, 0, 0, 0, 0, berlin),
+		Location: berlin,
+		Hours:    hours,
+	})
+
+	assert.Equal(t, "example.com", stats.Domain)
+	assert.Equal(t, 3, stats.Meetings)
+	assert.Equal(t, 3.0, stats.MeetingHours)
+	assert.Equal(t, []PeriodStats{
+		{Period: "2026-10-19", Meetings: 2, Hours: 2.5},
+		{Period: "2026-10-20", Meetings: 1, Hours: 0.5},
+	}, stats.PerDay)
+	assert.Equal(t, []PeriodStats{{Period: "2026-W43", Meetings: 3, Hours: 3}}, stats.PerWeek)
+	assert.Equal(t, SizeStats{OneOnOne: 2, Small: 1}, stats.BySize)
+	assert.Equal(t, 1.0, stats.RecurringHours)
+	assert.Equal(t, 2.0, stats.OneOffHours)
+	assert.Equal(t, []CoAttendee{
+		{Email: "alice@example.com", Meetings: 2, Hours: 1.5},
+		{Email: "bob@example.com", Meetings: 1, Hours: 1.5},
+		{Email: "vendor@partner.org", Meetings: 1, Hours: 1.5},
+	}, stats.TopAttendees)
+	assert.Equal(t, 1.5, stats.ExternalHours)
+	assert.Equal(t, 0.5, stats.ExternalShare)
+
+	// Monday: 10-13 and 14:30-17. Tuesday: 9:30-14; 16-17 is not longer
+	// than an hour and the declined all-hands does not split the morning.
+	assert.Equal(t, Fragmentation{Workdays: 2, FreeBlocksOver1h: 3, PerWorkday: 1.5, FreeHours: 10}, stats.Fragmentation)
+
+	assert.Equal(t, ResponseStats{Invitations: 2, Accepted: 1, Declined: 1, DeclineRate: 0.5}, stats.Responses)
+}
+
+func TestComputeStats_Empty(t *testing.T) {
+	stats := computeStats(nil, StatsOptions{
+		From: time.Date(2026, 10, 24, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2026, 10, 26, 0, 0, 0, 0, time.UTC),
+	})
+
+	assert.Equal(t, 0, stats.Meetings)
+	assert.Empty(t, stats.PerDay)
+	assert.NotNil(t, stats.TopAttendees)
+	assert.Equal(t, "UTC", stats.TimeZone)
+	assert.Zero(t, stats.Responses.DeclineRate)
+}
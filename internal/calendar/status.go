@@ -0,0 +1,196 @@
+package calendar
+
+import (
+	"fmt"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+// Event types.
+const (
+	EventTypeDefault         = "default"
+	EventTypeFocusTime       = "focusTime"
+	EventTypeOutOfOffice     = "outOfOffice"
+	EventTypeWorkingLocation = "workingLocation"
+)
+
+// Auto-decline modes of focus time and out-of-office events.
+const (
+	DeclineNone     = "declineNone"
+	DeclineNew      = "declineOnlyNewConflictingInvitations"
+	DeclineExisting = "declineAllConflictingInvitations"
+)
+
+// StatusEventOptions contains options for creating a focus time or
+// out-of-office event on your primary calendar.
+type StatusEventOptions struct {
+	Title          string // Defaults to "Focus time" or "Out of office"
+	Start          time.Time
+	End            time.Time
+	DeclineMode    string // DeclineNone, DeclineNew or DeclineExisting
+	DeclineMessage string // Sent with automatic declines
+}
+
+// WorkingLocationInfo describes where you work on a working location
+// event.
+type WorkingLocationInfo struct {
+	Type           string `json:"type"` // homeOffice, officeLocation or customLocation
+	Label          string `json:"label,omitempty"`
+	BuildingID     string `json:"building_id,omitempty"`
+	FloorID        string `json:"floor_id,omitempty"`
+	FloorSectionID string `json:"floor_section_id,omitempty"`
+	DeskID         string `json:"desk_id,omitempty"`
+}
+
+// AutoDeclineInfo describes how a focus time or out-of-office event
+// handles overlapping invitations.
+type AutoDeclineInfo struct {
+	Mode       string `json:"mode"`
+	Message    string `json:"message,omitempty"`
+	ChatStatus string `json:"chat_status,omitempty"`
+}
+
+// BlockFocus creates a focus time event on your primary calendar.
+func (s *Service) BlockFocus(opts StatusEventOptions) (*CreateEventResult, error) {
+	return s.createStatusEvent(EventTypeFocusTime, opts)
+}
+
+// OutOfOffice creates an out-of-office event on your primary calendar.
+func (s *Service) OutOfOffice(opts StatusEventOptions) (*CreateEventResult, error) {
+	return s.createStatusEvent(EventTypeOutOfOffice, opts)
+}
+
+func (s *Service) createStatusEvent(eventType string, opts StatusEventOptions) (*CreateEventResult, error) {
+	event, err := BuildStatusEvent(eventType, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Status events can only be created on the primary calendar.
+	created, err := s.svc.Events.Insert("primary", event).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create event: %w", err)
+	}
+
+	start, end, _ := parseEventTimes(created)
+	return &CreateEventResult{
+		EventID:  created.Id,
+		HTMLLink: created.HtmlLink,
+		Start:    start,
+		End:      end,
+	}, nil
+}
+
+// BuildStatusEvent converts status event options to an API event of the
+// given type (EventTypeFocusTime or EventTypeOutOfOffice).
+func BuildStatusEvent(eventType string, opts StatusEventOptions) (*calendar.Event, error) {
+	if !opts.End.After(opts.Start) {
+		return nil, fmt.Errorf("end must be after start")
+	}
+
+	mode := opts.DeclineMode
+	if mode == "" {
+		mode = DeclineNone
+	}
+	if opts.DeclineMessage != "" && mode == DeclineNone {
+		return nil, fmt.Errorf("a decline message requires auto-decline")
+	}
+
+	event := &calendar.Event{
+		Summary:      opts.Title,
+		EventType:    eventType,
+		Transparency: "opaque",
+		Start:        &calendar.EventDateTime{DateTime: opts.Start.Format(time.RFC3339)},
+		End:          &calendar.EventDateTime{DateTime: opts.End.Format(time.RFC3339)},
+	}
+
+	switch eventType {
+	case EventTypeFocusTime:
+		if event.Summary == "" {
+			event.Summary = "Focus time"
+		}
+		event.FocusTimeProperties = &calendar.EventFocusTimeProperties{
+			AutoDeclineMode: mode,
+			DeclineMessage:  opts.DeclineMessage,
+		}
+	case EventTypeOutOfOffice:
+		if event.Summary == "" {
+			event.Summary = "Out of office"
+		}
+		event.OutOfOfficeProperties = &calendar.EventOutOfOfficeProperties{
+			AutoDeclineMode: mode,
+			DeclineMessage:  opts.DeclineMessage,
+		}
+	default:
+		return nil, fmt.Errorf("unsupported event type %q", eventType)
+	}
+
+	return event, nil
+}
+
+// eventType returns an event's type, "default" if unset.
+func eventType(e *calendar.Event) string {
+	if e.EventType == "" {
+		return EventTypeDefault
+	}
+	return e.EventType
+}
+
+// workingLocationInfo converts working location properties, returning nil
+// for other event types.
+func workingLocationInfo(e *calendar.Event) *WorkingLocationInfo {
+	p := e.WorkingLocationProperties
+	if p == nil {
+		return nil
+	}
+
+	info := &WorkingLocationInfo{Type: p.Type}
+	switch {
+	case p.OfficeLocation != nil:
+		info.Type = "officeLocation"
+		info.Label = p.OfficeLocation.Label
+		info.BuildingID = p.OfficeLocation.BuildingId
+		info.FloorID = p.OfficeLocation.FloorId
+		info.FloorSectionID = p.OfficeLocation.FloorSectionId
+		info.DeskID = p.OfficeLocation.DeskId
+	case p.CustomLocation != nil:
+		info.Type = "customLocation"
+		info.Label = p.CustomLocation.Label
+	case p.HomeOffice != nil:
+		info.Type = "homeOffice"
+	}
+	return info
+}
+
+// autoDeclineInfo returns the auto-decline settings of a focus time or
+// out-of-office event, or nil for other event types.
+func autoDeclineInfo(e *calendar.Event) *AutoDeclineInfo {
+	switch {
+	case e.FocusTimeProperties != nil:
+		p := e.FocusTimeProperties
+		return &AutoDeclineInfo{Mode: p.AutoDeclineMode, Message: p.DeclineMessage, ChatStatus: p.ChatStatus}
+	case e.OutOfOfficeProperties != nil:
+		p := e.OutOfOfficeProperties
+		return &AutoDeclineInfo{Mode: p.AutoDeclineMode, Message: p.DeclineMessage}
+	}
+	return nil
+}
+
+// describeWorkingLocation returns a short description such as "home" or
+// "office: Berlin HQ".
+func describeWorkingLocation(wl *WorkingLocationInfo) string {
+	switch wl.Type {
+	case "homeOffice":
+		return "home"
+	case "officeLocation":
+		if wl.Label != "" {
+			return "office: " + wl.Label
+		}
+		return "office"
+	}
+	if wl.Label != "" {
+		return wl.Label
+	}
+	return "elsewhere"
+}
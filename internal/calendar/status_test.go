@@ -0,0 +1,70 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/calendar/v3"
+)
+
+func TestBuildStatusEvent(t *testing.T) {
+	start := time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC)
+	opts := StatusEventOptions{Start: start, End: start.Add(2 * time.Hour), DeclineMode: DeclineNew, DeclineMessage: "Heads down"}
+
+	focus, err := BuildStatusEvent(EventTypeFocusTime, opts)
+	require.NoError(t, err)
+	assert.Equal(t, "focusTime", focus.EventType)
+	assert.Equal(t, "Focus time", focus.Summary)
+	assert.Equal(t, "opaque", focus.Transparency)
+	assert.Equal(t, "2026-10-20T09:00:00Z", focus.Start.DateTime)
+	assert.Equal(t, &calendar.EventFocusTimeProperties{AutoDeclineMode: DeclineNew, DeclineMessage: "Heads down"}, focus.FocusTimeProperties)
+	assert.Nil(t, focus.OutOfOfficeProperties)
+
+	opts.Title = "Vacation"
+	ooo, err := BuildStatusEvent(EventTypeOutOfOffice, opts)
+	require.NoError(t, err)
+	assert.Equal(t, "Vacation", ooo.Summary)
+	assert.Equal(t, DeclineNew, ooo.OutOfOfficeProperties.AutoDeclineMode)
+
+	_, err = BuildStatusEvent(EventTypeFocusTime, StatusEventOptions{Start: start, End: start, DeclineMode: DeclineNone})
+	assert.Error(t, err)
+	_, err = BuildStatusEvent(EventTypeFocusTime, StatusEventOptions{Start: start, End: start.Add(time.Hour), DeclineMessage: "x"})
+	assert.Error(t, err)
+	_, err = BuildStatusEvent(EventTypeWorkingLocation, opts)
+	assert.Error(t, err)
+}
+
+func TestEventTypeInReadOutputs(t *testing.T) {
+	plain := &calendar.Event{Id: "plain", Start: &calendar.EventDateTime{Date: "2026-10-20"}, End: &calendar.EventDateTime{Date: "2026-10-21"}}
+	assert.Equal(t, EventTypeDefault, parseEventToSummary(plain).EventType)
+	assert.Nil(t, parseEventToSummary(plain).WorkingLocation)
+	assert.Nil(t, parseEventToFull(plain).AutoDecline)
+
+	office := &calendar.Event{
+		Id: "office", EventType: "workingLocation",
+		Start: &calendar.EventDateTime{Date: "2026-10-20"}, End: &calendar.EventDateTime{Date: "2026-10-21"},
+		WorkingLocationProperties: &calendar.EventWorkingLocationProperties{
+			Type:           "officeLocation",
+			OfficeLocation: &calendar.EventWorkingLocationPropertiesOfficeLocation{Label: "Berlin HQ", FloorId: "3", DeskId: "3-14"},
+		},
+	}
+	summary := parseEventToSummary(office)
+	assert.Equal(t, EventTypeWorkingLocation, summary.EventType)
+	assert.Equal(t, &WorkingLocationInfo{Type: "officeLocation", Label: "Berlin HQ", FloorID: "3", DeskID: "3-14"}, summary.WorkingLocation)
+	assert.Equal(t, "office: Berlin HQ", describeWorkingLocation(summary.WorkingLocation))
+
+	home := &calendar.Event{WorkingLocationProperties: &calendar.EventWorkingLocationProperties{HomeOffice: map[string]interface{}{}}}
+	assert.Equal(t, &WorkingLocationInfo{Type: "homeOffice"}, workingLocationInfo(home))
+
+	focus := &calendar.Event{
+		Id: "focus", EventType: "focusTime",
+		Start:               &calendar.EventDateTime{DateTime: "2026-10-20T09:00:00Z"},
+		End:                 &calendar.EventDateTime{DateTime: "2026-10-20T11:00:00Z"},
+		FocusTimeProperties: &calendar.EventFocusTimeProperties{AutoDeclineMode: DeclineExisting, ChatStatus: "doNotDisturb"},
+	}
+	full := parseEventToFull(focus)
+	assert.Equal(t, EventTypeFocusTime, full.EventType)
+	assert.Equal(t, &AutoDeclineInfo{Mode: DeclineExisting, ChatStatus: "doNotDisturb"}, full.AutoDecline)
+}
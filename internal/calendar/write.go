@@ -10,14 +10,26 @@ import (
 
 // CreateEventOptions contains options for creating an event.
 type CreateEventOptions struct {
-	CalendarID  string
-	Title       string
-	Description string
-	Location    string
-	Start       time.Time
-	End         time.Time
-	AllDay      bool
-	Attendees   []string
+	CalendarID        string
+	Title             string
+	Description       string
+	Location          string
+	Start             time.Time
+	End               time.Time
+	AllDay            bool
+	Attendees         []string
+	OptionalAttendees []string
+	Recurrence        []string // RRULE/EXDATE/RDATE lines, see ParseRepeat
+	TimeZone          string   // IANA zone; recurring events default to the calendar's
+	Meet              bool     // Create a Google Meet conference
+	Reminders         []ReminderInfo
+	ColorID           string // See ParseColor
+	Visibility        string // default, public, private, confidential
+	Transparency      string // opaque (busy) or transparent (free)
+	GuestsCanModify   bool
+	SendUpdates       string // all (default), externalOnly, none
+	AllowConflicts    bool   // Skip the double-booking check
+	CheckAttendees    bool   // Also check required attendees' free/busy
 }
 
 // Create creates a new calendar event.
@@ -26,11 +38,66 @@ func (s *Service) Create(opts CreateEventOptions) (*CreateEventResult, error) {
 	if calendarID == "" {
 		calendarID = "primary"
 	}
+	sendUpdates := opts.SendUpdates
+	if sendUpdates == "" {
+		sendUpdates = "all"
+	}
+
+	timeZone := opts.TimeZone
+	if len(opts.Recurrence) > 0 && !opts.AllDay && timeZone == "" {
+		// Recurring events need a zone to expand the rule in.
+		var err error
+		if timeZone, err = s.timeZone(calendarID); err != nil {
+			return nil, err
+		}
+	}
+
+	event, err := buildEvent(opts, timeZone)
+	if err != nil {
+		return nil, err
+	}
+
+	if !opts.AllowConflicts && !opts.AllDay && opts.Transparency != "transparent" {
+		// Only the first occurrence of a recurring event is checked.
+		check := conflictCheck{calendarID: calendarID, start: opts.Start, end: opts.End, recurring: len(opts.Recurrence) > 0}
+		if opts.CheckAttendees {
+			check.attendees = opts.Attendees
+		}
+		if err := s.checkConflicts(check); err != nil {
+			return nil, err
+		}
+	}
 
+	call := s.svc.Events.Insert(calendarID, event).SendUpdates(sendUpdates)
+	if opts.Meet {
+		call = call.ConferenceDataVersion(1)
+	}
+	created, err := call.Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create event: %w", err)
+	}
+
+	start, end, _ := parseEventTimes(created)
+	return &CreateEventResult{
+		EventID:  created.Id,
+		HTMLLink: created.HtmlLink,
+		MeetLink: created.HangoutLink,
+		Start:    start,
+		End:      end,
+	}, nil
+}
+
+// buildEvent converts create options to an API event.
+func buildEvent(opts CreateEventOptions, timeZone string) (*calendar.Event, error) {
 	event := &calendar.Event{
-		Summary:     opts.Title,
-		Description: opts.Description,
-		Location:    opts.Location,
+		Summary:         opts.Title,
+		Description:     opts.Description,
+		Location:        opts.Location,
+		Recurrence:      opts.Recurrence,
+		ColorId:         opts.ColorID,
+		Visibility:      opts.Visibility,
+		Transparency:    opts.Transparency,
+		GuestsCanModify: opts.GuestsCanModify,
 	}
 
 	if opts.AllDay {
@@ -43,92 +110,292 @@ func (s *Service) Create(opts CreateEventOptions) (*CreateEventResult, error) {
 	} else {
 		event.Start = &calendar.EventDateTime{
 			DateTime: opts.Start.Format(time.RFC3339),
+			TimeZone: timeZone,
 		}
 		event.End = &calendar.EventDateTime{
 			DateTime: opts.End.Format(time.RFC3339),
+			TimeZone: timeZone,
 		}
 	}
 
-	if len(opts.Attendees) > 0 {
-		for _, email := range opts.Attendees {
-			event.Attendees = append(event.Attendees, &calendar.EventAttendee{
-				Email: email,
+	for _, email := range opts.Attendees {
+		event.Attendees = append(event.Attendees, &calendar.EventAttendee{
+			Email: email,
+		})
+	}
+	for _, email := range opts.OptionalAttendees {
+		event.Attendees = append(event.Attendees, &calendar.EventAttendee{
+			Email:    email,
+			Optional: true,
+		})
+	}
+
+	if len(opts.Reminders) > 0 {
+		event.Reminders = &calendar.EventReminders{ForceSendFields: []string{"UseDefault"}}
+		for _, r := range opts.Reminders {
+			event.Reminders.Overrides = append(event.Reminders.Overrides, &calendar.EventReminder{
+				Method:          r.Method,
+				Minutes:         r.Minutes,
+				ForceSendFields: []string{"Minutes"},
 			})
 		}
 	}
 
-	created, err := s.svc.Events.Insert(calendarID, event).SendUpdates("all").Do()
-	if err != nil {
-		return nil, fmt.Errorf("failed to create event: %w", err)
+	if opts.Meet {
+		requestID, err := newRequestID()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create conference request: %w", err)
+		}
+		event.ConferenceData = &calendar.ConferenceData{
+			CreateRequest: &calendar.CreateConferenceRequest{
+				RequestId:             requestID,
+				ConferenceSolutionKey: &calendar.ConferenceSolutionKey{Type: "hangoutsMeet"},
+			},
+		}
 	}
 
-	return &CreateEventResult{
-		EventID:  created.Id,
-		HTMLLink: created.HtmlLink,
-	}, nil
+	return event, nil
 }
 
 // RescheduleOptions contains options for rescheduling an event.
 type RescheduleOptions struct {
-	CalendarID string
-	EventID    string
-	Start      time.Time
-	End        time.Time
+	CalendarID     string
+	EventID        string
+	Start          time.Time
+	End            time.Time
+	Scope          string // ScopeThis, ScopeFollowing or ScopeAll for recurring events
+	AllowConflicts bool   // Skip the double-booking check
+	CheckAttendees bool   // Also check required attendees' free/busy
 }
 
 // Reschedule updates an event's time.
+//
+// For recurring events, Scope selects what moves: ScopeThis moves only the
+// given occurrence, ScopeAll shifts the whole series by the same offset and
+// ScopeFollowing splits the series at the occurrence and starts a new series
+// at the new time; from the first occurrence it moves the whole series.
+// Without a scope, the event ID is changed as given.
 func (s *Service) Reschedule(opts RescheduleOptions) (*CreateEventResult, error) {
 	calendarID := opts.CalendarID
 	if calendarID == "" {
 		calendarID = "primary"
 	}
 
-	// Get the existing event
-	event, err := s.svc.Events.Get(calendarID, opts.EventID).Do()
+	instance, series, err := s.resolveSeries(calendarID, opts.EventID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get event: %w", err)
+		return nil, err
 	}
 
-	// Update times
-	if event.Start.Date != "" {
-		// All-day event
-		event.Start = &calendar.EventDateTime{
-			Date: opts.Start.Format("2006-01-02"),
+	// Get the existing event
+	event, eventID := series, series.Id
+	if instance != nil {
+		event, eventID = instance, instance.Id
+	}
+	start, end := opts.Start, opts.End
+
+	if !opts.AllowConflicts {
+		if err := s.checkRescheduleConflicts(calendarID, event, series, opts); err != nil {
+			return nil, err
 		}
-		event.End = &calendar.EventDateTime{
-			Date: opts.End.Format("2006-01-02"),
+	}
+
+	scope := opts.Scope
+	if scope == ScopeFollowing && instance != nil {
+		first, err := atSeriesStart(instance, series)
+		if err != nil {
+			return nil, err
 		}
-	} else {
-		event.Start = &calendar.EventDateTime{
-			DateTime: opts.Start.Format(time.RFC3339),
+		if first {
+			// Nothing precedes the occurrence, so move the series in place.
+			scope = ScopeAll
 		}
-		event.End = &calendar.EventDateTime{
-			DateTime: opts.End.Format(time.RFC3339),
+	}
+
+	switch scope {
+	case "":
+	case ScopeThis:
+		if instance == nil && len(series.Recurrence) > 0 {
+			return nil, fmt.Errorf("%s is a recurring series; use an occurrence ID from calendar instances", opts.EventID)
+		}
+	case ScopeAll:
+		if instance != nil {
+			origStart, _, err := originalStart(instance)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse occurrence start: %w", err)
+			}
+			seriesStart, _, err := originalStart(series)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse series start: %w", err)
+			}
+			start = seriesStart.Add(opts.Start.Sub(origStart))
+			end = start.Add(opts.End.Sub(opts.Start))
+			event, eventID = series, series.Id
+		}
+	case ScopeFollowing:
+		if instance != nil {
+			return s.rescheduleFollowing(calendarID, instance, series, opts.Start, opts.End)
 		}
+	default:
+		return nil, fmt.Errorf("invalid scope: %s (use: this, following, all)", opts.Scope)
 	}
 
-	updated, err := s.svc.Events.Update(calendarID, opts.EventID, event).SendUpdates("all").Do()
+	// Update times
+	setEventTimes(event, start, end)
+
+	updated, err := s.svc.Events.Update(calendarID, eventID, event).SendUpdates("all").Do()
 	if err != nil {
 		return nil, fmt.Errorf("failed to reschedule event: %w", err)
 	}
 
+	newStart, newEnd, _ := parseEventTimes(updated)
 	return &CreateEventResult{
 		EventID:  updated.Id,
 		HTMLLink: updated.HtmlLink,
+		Start:    newStart,
+		End:      newEnd,
 	}, nil
 }
 
-// Cancel deletes an event.
-func (s *Service) Cancel(calendarID, eventID string, notify bool) error {
+// checkRescheduleConflicts checks the new time of an event being moved,
+// ignoring the event itself. All-day and free events are not checked, and
+// when a series moves only the given occurrence is.
+func (s *Service) checkRescheduleConflicts(calendarID string, event, series *calendar.Event, opts RescheduleOptions) error {
+	if event.Transparency == "transparent" || event.Start == nil || event.Start.Date != "" {
+		return nil
+	}
+
+	check := conflictCheck{calendarID: calendarID, start: opts.Start, end: opts.End, ignore: []string{event.Id}}
+	if opts.Scope == ScopeAll || opts.Scope == ScopeFollowing {
+		check.ignore = append(check.ignore, series.Id)
+		check.recurring = len(series.Recurrence) > 0
+	}
+	check.ignoreStart, check.ignoreEnd, _ = eventInterval(event, opts.Start.Location())
+	if opts.CheckAttendees {
+		for _, a := range event.Attendees {
+			if !a.Self && !a.Optional && a.ResponseStatus != "declined" {
+				check.attendees = append(check.attendees, a.Email)
+			}
+		}
+	}
+	return s.checkConflicts(check)
+}
+
+// rescheduleFollowing ends a series before the occurrence and continues it
+// as a new series at the new time.
+func (s *Service) rescheduleFollowing(calendarID string, instance, series *calendar.Event, start, end time.Time) (*CreateEventResult, error) {
+	split, allDay, remaining, err := s.seriesRemainder(calendarID, instance, series)
+	if err != nil {
+		return nil, err
+	}
+
+	next := followingSeries(series, remaining)
+	setEventTimes(next, start, end)
+
+	// Create the new series first; if ending the original then fails, the
+	// new series is deleted again so only the original stays live.
+	created, err := s.svc.Events.Insert(calendarID, next).ConferenceDataVersion(1).SendUpdates("all").Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create recurring event: %w", err)
+	}
+
+	if err := s.endSeries(calendarID, series, split, allDay, "all"); err != nil {
+		if delErr := s.svc.Events.Delete(calendarID, created.Id).SendUpdates("all").Do(); delErr != nil {
+			return nil, fmt.Errorf("%w (and failed to delete new series %s: %v)", err, created.Id, delErr)
+		}
+		return nil, err
+	}
+
+	newStart, newEnd, _ := parseEventTimes(created)
+	return &CreateEventResult{
+		EventID:  created.Id,
+		HTMLLink: created.HtmlLink,
+		Start:    newStart,
+		End:      newEnd,
+	}, nil
+}
+
+// setEventTimes sets the start and end of an event, keeping it all-day if
+// it was and preserving its time zone.
+func setEventTimes(event *calendar.Event, start, end time.Time) {
+	if event.Start.Date != "" {
+		// All-day event
+		event.Start = &calendar.EventDateTime{
+			Date: start.Format("2006-01-02"),
+		}
+		event.End = &calendar.EventDateTime{
+			Date: end.Format("2006-01-02"),
+		}
+		return
+	}
+
+	event.Start = &calendar.EventDateTime{
+		DateTime: start.Format(time.RFC3339),
+		TimeZone: event.Start.TimeZone,
+	}
+	event.End = &calendar.EventDateTime{
+		DateTime: end.Format(time.RFC3339),
+		TimeZone: event.End.TimeZone,
+	}
+}
+
+// CancelOptions contains options for cancelling an event.
+type CancelOptions struct {
+	CalendarID string
+	EventID    string
+	Notify     bool
+	Scope      string // ScopeThis, ScopeFollowing or ScopeAll for recurring events
+}
+
+// Cancel deletes an event. For recurring events, Scope selects the given
+// occurrence only (ScopeThis), the series from that occurrence on
+// (ScopeFollowing, which deletes the whole series from its first
+// occurrence) or the whole series (ScopeAll).
+func (s *Service) Cancel(opts CancelOptions) error {
+	calendarID := opts.CalendarID
 	if calendarID == "" {
 		calendarID = "primary"
 	}
 
 	sendUpdates := "none"
-	if notify {
+	if opts.Notify {
 		sendUpdates = "all"
 	}
 
+	eventID := opts.EventID
+	if opts.Scope != "" {
+		instance, series, err := s.resolveSeries(calendarID, opts.EventID)
+		if err != nil {
+			return err
+		}
+
+		switch opts.Scope {
+		case ScopeThis:
+			if instance == nil && len(series.Recurrence) > 0 {
+				return fmt.Errorf("%s is a recurring series; use an occurrence ID from calendar instances", opts.EventID)
+			}
+		case ScopeAll:
+			eventID = series.Id
+		case ScopeFollowing:
+			if instance != nil {
+				first, err := atSeriesStart(instance, series)
+				if err != nil {
+					return err
+				}
+				if first {
+					eventID = series.Id
+					break
+				}
+				split, allDay, err := originalStart(instance)
+				if err != nil {
+					return fmt.Errorf("failed to parse occurrence start: %w", err)
+				}
+				return s.endSeries(calendarID, series, split, allDay, sendUpdates)
+			}
+		default:
+			return fmt.Errorf("invalid scope: %s (use: this, following, all)", opts.Scope)
+		}
+	}
+
 	if err := s.svc.Events.Delete(calendarID, eventID).SendUpdates(sendUpdates).Do(); err != nil {
 		return fmt.Errorf("failed to cancel event: %w", err)
 	}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 const (
@@ -24,6 +25,27 @@ type Config struct {
 	OutputFormat    string `json:"output_format,omitempty"`
 	TimeoutSeconds  int    `json:"timeout_seconds,omitempty"`
 	AuditLog        bool   `json:"audit_log,omitempty"`
+	// WorkingHours maps attendee emails to working-hours specs such as
+	// "Europe/Berlin 09:00-17:00 mon-fri lunch 12:00-13:00". The
+	// "default" entry applies to yourself and attendees without an entry.
+	WorkingHours map[string]string `json:"working_hours,omitempty"`
+}
+
+// WorkingHoursKey is the configuration key prefix for working hours:
+// "working_hours" sets the default, "working_hours.<email>" an attendee's.
+const WorkingHoursKey = "working_hours"
+
+// workingHoursEntry returns the WorkingHours map key for a configuration
+// key, or false if key is not a working hours key.
+func workingHoursEntry(key string) (string, bool) {
+	if key == WorkingHoursKey {
+		return "default", true
+	}
+	email, ok := strings.CutPrefix(key, WorkingHoursKey+".")
+	if !ok || email == "" {
+		return "", false
+	}
+	return strings.ToLower(email), true
 }
 
 // DefaultConfig returns a configuration with default values.
@@ -138,6 +160,18 @@ func Set(key, value string) error {
 		config = DefaultConfig()
 	}
 
+	if entry, ok := workingHoursEntry(key); ok {
+		if value == "" {
+			delete(config.WorkingHours, entry)
+		} else {
+			if config.WorkingHours == nil {
+				config.WorkingHours = map[string]string{}
+			}
+			config.WorkingHours[entry] = value
+		}
+		return Save(config)
+	}
+
 	switch key {
 	case "redirect_url":
 		config.RedirectURL = value
@@ -164,6 +198,10 @@ func Get(key string) (string, error) {
 		return "", err
 	}
 
+	if entry, ok := workingHoursEntry(key); ok {
+		return config.WorkingHours[entry], nil
+	}
+
 	switch key {
 	case "client_id":
 		return config.ClientID, nil
@@ -130,6 +130,26 @@ func TestSetAndGet(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "true", value)
 
+	// Working hours
+	require.NoError(t, Set("working_hours", "Europe/Berlin 09:00-17:00"))
+	require.NoError(t, Set("working_hours.Alice@Example.com", "America/New_York 08:00-16:00"))
+
+	value, err = Get("working_hours.alice@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "America/New_York 08:00-16:00", value)
+
+	loaded, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"default":           "Europe/Berlin 09:00-17:00",
+		"alice@example.com": "America/New_York 08:00-16:00",
+	}, loaded.WorkingHours)
+
+	require.NoError(t, Set("working_hours.alice@example.com", ""))
+	value, err = Get("working_hours.alice@example.com")
+	require.NoError(t, err)
+	assert.Empty(t, value)
+
 	// Test invalid key
 	err = Set("invalid_key", "value")
 	assert.Error(t, err)
@@ -0,0 +1,413 @@
+// Package dateparse parses the absolute and relative date/time expressions
+// accepted by calendar flags, such as "tomorrow 14:00", "next tue 9am",
+// "+2h" or "2026-10-20 15:30 Europe/Berlin".
+//
+// Parsing is deterministic: expressions are resolved against a given
+// instant and time zone, never the machine's clock or zone.
+package dateparse
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// absoluteLayouts are tried, in order, on expressions without an offset.
+var absoluteLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tues": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+var months = map[string]time.Month{
+	"jan": time.January, "january": time.January,
+	"feb": time.February, "february": time.February,
+	"mar": time.March, "march": time.March,
+	"apr": time.April, "april": time.April,
+	"may": time.May,
+	"jun": time.June, "june": time.June,
+	"jul": time.July, "july": time.July,
+	"aug": time.August, "august": time.August,
+	"sep": time.September, "sept": time.September, "september": time.September,
+	"oct": time.October, "october": time.October,
+	"nov": time.November, "november": time.November,
+	"dec": time.December, "december": time.December,
+}
+
+// Parse resolves a date/time expression relative to now in loc.
+//
+// Accepted forms:
+//   - RFC3339 with offset: 2026-10-20T15:30:00+02:00
+//   - Local date/time: 2026-10-20T15:30, 2026-10-20 15:30, 2026-10-20
+//   - Any of the above without offset followed by a zone: 2026-10-20 15:30 Europe/Berlin
+//   - Relative: now, +2h, -30m, +1d, in 2 hours, in 3 days
+//   - Day words: today, tomorrow, yesterday, tue, next tue, oct 20, 20 oct 2026
+//   - Times: 14:00, 9am, 9:30pm, 9 am, noon, midnight (optionally after "at")
+//
+// A time without a day means today; a day without a time means midnight.
+// A bare weekday is the next such day including today; "next tue" is the
+// next one after today.
+func Parse(expr string, now time.Time, loc *time.Location) (time.Time, error) {
+	s := strings.TrimSpace(expr)
+	if s == "" {
+		return time.Time{}, fmt.Errorf("empty date/time")
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+
+	// A trailing zone name overrides loc.
+	fields := strings.Fields(s)
+	if last := fields[len(fields)-1]; len(fields) > 1 && (strings.Contains(last, "/") || strings.EqualFold(last, "utc")) {
+		name := last
+		if strings.EqualFold(last, "utc") {
+			name = "UTC"
+		}
+		zone, err := time.LoadLocation(name)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("unknown time zone %q", last)
+		}
+		loc = zone
+		fields = fields[:len(fields)-1]
+		s = strings.Join(fields, " ")
+	}
+
+	for _, layout := range absoluteLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+
+	now = now.In(loc)
+	lower := strings.ToLower(s)
+
+	if lower == "now" {
+		return now, nil
+	}
+	if t, ok := parseOffset(lower, now); ok {
+		return t, nil
+	}
+
+	return parseWords(strings.Fields(lower), now, loc, expr)
+}
+
+// parseOffset parses "+2h", "-30m", "+1d" and "in 2 hours" relative to
+// now. Days and weeks are calendar days, so the clock time is kept across
+// DST changes.
+func parseOffset(s string, now time.Time) (time.Time, bool) {
+	sign := 1
+	var amount []string
+	switch {
+	case strings.HasPrefix(s, "+") || strings.HasPrefix(s, "-"):
+		if s[0] == '-' {
+			sign = -1
+		}
+		amount = strings.Fields(s[1:])
+		if len(amount) != 1 {
+			return time.Time{}, false
+		}
+	case strings.HasPrefix(s, "in "):
+		amount = strings.Fields(s[3:])
+	default:
+		return time.Time{}, false
+	}
+
+	if days, ok := offsetDays(amount); ok {
+		return now.AddDate(0, 0, sign*days), true
+	}
+
+	switch len(amount) {
+	case 1:
+		d, err := ParseDuration(amount[0])
+		if err != nil {
+			return time.Time{}, false
+		}
+		return now.Add(time.Duration(sign) * d), true
+	case 2:
+		n, err := strconv.Atoi(amount[0])
+		if err != nil {
+			return time.Time{}, false
+		}
+		unit := map[string]time.Duration{
+			"minute": time.Minute, "minutes": time.Minute, "min": time.Minute, "mins": time.Minute,
+			"hour": time.Hour, "hours": time.Hour,
+		}[amount[1]]
+		if unit == 0 {
+			return time.Time{}, false
+		}
+		return now.Add(time.Duration(sign*n) * unit), true
+	}
+	return time.Time{}, false
+}
+
+// offsetDays parses a whole number of days or weeks such as "3d", "1w" or
+// "2 weeks" into days.
+func offsetDays(amount []string) (int, bool) {
+	var number, unit string
+	switch len(amount) {
+	case 1:
+		i := strings.IndexFunc(amount[0], func(r rune) bool { return r < '0' || r > '9' })
+		if i <= 0 {
+			return 0, false
+		}
+		number, unit = amount[0][:i], amount[0][i:]
+	case 2:
+		number, unit = amount[0], amount[1]
+	default:
+		return 0, false
+	}
+
+	n, err := strconv.Atoi(number)
+	if err != nil {
+		return 0, false
+	}
+	switch unit {
+	case "d", "day", "days":
+		return n, true
+	case "w", "week", "weeks":
+		return 7 * n, true
+	}
+	return 0, false
+}
+
+// parseWords parses day words and clock times.
+func parseWords(tokens []string, now time.Time, loc *time.Location, expr string) (time.Time, error) {
+	var date *time.Time
+	hour, minute := -1, 0
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+
+	setDate := func(t time.Time) error {
+		if date != nil {
+			return fmt.Errorf("invalid date/time %q: more than one date", expr)
+		}
+		date = &t
+		return nil
+	}
+	setTime := func(h, m int) error {
+		if hour >= 0 {
+			return fmt.Errorf("invalid date/time %q: more than one time", expr)
+		}
+		hour, minute = h, m
+		return nil
+	}
+
+	for i := 0; i < len(tokens); i++ {
+		tok := strings.TrimSuffix(tokens[i], ",")
+		var err error
+
+		switch {
+		case tok == "at" || tok == "on":
+			continue
+		case tok == "today":
+			err = setDate(today)
+		case tok == "tomorrow":
+			err = setDate(today.AddDate(0, 0, 1))
+		case tok == "yesterday":
+			err = setDate(today.AddDate(0, 0, -1))
+		case tok == "next" && i+1 < len(tokens) && isWeekday(tokens[i+1]):
+			i++
+			days := (int(weekdays[tokens[i]]) - int(now.Weekday()) + 7) % 7
+			if days == 0 {
+				days = 7
+			}
+			err = setDate(today.AddDate(0, 0, days))
+		case isWeekday(tok):
+			days := (int(weekdays[tok]) - int(now.Weekday()) + 7) % 7
+			err = setDate(today.AddDate(0, 0, days))
+		case tok == "noon":
+			err = setTime(12, 0)
+		case tok == "midnight":
+			err = setTime(0, 0)
+		case isMonth(tok) || isMonth(next(tokens, i)) && isNumber(tok):
+			var t time.Time
+			var used int
+			t, used, err = parseMonthDay(tokens[i:], today)
+			if err == nil {
+				i += used - 1
+				err = setDate(t)
+			}
+		default:
+			if t, perr := time.ParseInLocation("2006-01-02", tok, loc); perr == nil {
+				err = setDate(t)
+				break
+			}
+			suffix := ""
+			if n := next(tokens, i); n == "am" || n == "pm" {
+				suffix = n
+				i++
+			}
+			h, m, ok := parseClock(tok + suffix)
+			if !ok {
+				return time.Time{}, fmt.Errorf("invalid date/time %q: unexpected %q", expr, tok)
+			}
+			err = setTime(h, m)
+		}
+		if err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	if date == nil && hour < 0 {
+		return time.Time{}, fmt.Errorf("invalid date/time %q", expr)
+	}
+	if date == nil {
+		date = &today
+	}
+	if hour < 0 {
+		hour = 0
+	}
+	return time.Date(date.Year(), date.Month(), date.Day(), hour, minute, 0, 0, loc), nil
+}
+
+// parseMonthDay parses "oct 20", "october 20 2026" and "20 oct [2026]".
+// Without a year, the next such date on or after today is used.
+func parseMonthDay(tokens []string, today time.Time) (time.Time, int, error) {
+	var month time.Month
+	var day, year, used int
+
+	first := strings.TrimSuffix(tokens[0], ",")
+	second := strings.TrimSuffix(next(tokens, 0), ",")
+	if isMonth(first) {
+		month = months[first]
+		d, err := strconv.Atoi(strings.TrimRight(second, "stndrh"))
+		if err != nil {
+			return time.Time{}, 0, fmt.Errorf("missing day after %q", first)
+		}
+		day, used = d, 2
+	} else {
+		d, _ := strconv.Atoi(first)
+		day, month, used = d, months[second], 2
+	}
+
+	if y, err := strconv.Atoi(next(tokens, used-1)); err == nil && y >= 1000 {
+		year = y
+		used++
+	}
+
+	if year == 0 {
+		year = today.Year()
+		if time.Date(year, month, day, 0, 0, 0, 0, today.Location()).Before(today) {
+			year++
+		}
+	}
+
+	t := time.Date(year, month, day, 0, 0, 0, 0, today.Location())
+	if t.Day() != day || day < 1 {
+		return time.Time{}, 0, fmt.Errorf("invalid day %d of %s", day, month)
+	}
+	return t, used, nil
+}
+
+// parseClock parses "14:00", "9am", "9:30pm" and "12am".
+func parseClock(s string) (int, int, bool) {
+	meridiem := ""
+	if strings.HasSuffix(s, "am") || strings.HasSuffix(s, "pm") {
+		meridiem, s = s[len(s)-2:], s[:len(s)-2]
+	}
+
+	h, m, hasMinutes := strings.Cut(s, ":")
+	if !hasMinutes && meridiem == "" {
+		return 0, 0, false
+	}
+	hour, err := strconv.Atoi(h)
+	if err != nil {
+		return 0, 0, false
+	}
+	minute := 0
+	if hasMinutes {
+		if minute, err = strconv.Atoi(m); err != nil || len(m) != 2 || minute > 59 {
+			return 0, 0, false
+		}
+	}
+
+	switch meridiem {
+	case "":
+		if hour > 23 {
+			return 0, 0, false
+		}
+	default:
+		if hour < 1 || hour > 12 {
+			return 0, 0, false
+		}
+		hour %= 12
+		if meridiem == "pm" {
+			hour += 12
+		}
+	}
+	return hour, minute, true
+}
+
+func next(tokens []string, i int) string {
+	if i+1 < len(tokens) {
+		return tokens[i+1]
+	}
+	return ""
+}
+
+func isWeekday(s string) bool {
+	_, ok := weekdays[s]
+	return ok
+}
+
+func isMonth(s string) bool {
+	_, ok := months[strings.TrimSuffix(s, ",")]
+	return ok
+}
+
+func isNumber(s string) bool {
+	_, err := strconv.Atoi(s)
+	return err == nil
+}
+
+// AddDuration adds a duration such as "30m", "1h30m" or "2d" to t. Whole
+// days and weeks are added as calendar days, keeping the clock time across
+// DST changes.
+func AddDuration(t time.Time, s string) (time.Time, error) {
+	if days, ok := offsetDays([]string{strings.ToLower(strings.TrimSpace(s))}); ok {
+		return t.AddDate(0, 0, days), nil
+	}
+	d, err := ParseDuration(s)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.Add(d), nil
+}
+
+// ParseDuration parses a duration such as 30m, 1h30m, 1.5h, 2d or 1w. A
+// bare number is minutes.
+func ParseDuration(s string) (time.Duration, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if n, err := strconv.Atoi(s); err == nil {
+		return time.Duration(n) * time.Minute, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return d, nil
+	}
+
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if v, ok := strings.CutSuffix(s, suffix); ok {
+			if n, err := strconv.ParseFloat(v, 64); err == nil {
+				return time.Duration(n * float64(unit)), nil
+			}
+		}
+	}
+
+	return 0, fmt.Errorf("invalid duration %q (use e.g. 30m, 1h30m, 2d)", s)
+}
@@ -0,0 +1,108 @@
+package dateparse
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	// Sunday 2026-10-18 10:30 in Berlin; the machine zone must not matter.
+	now := time.Date(2026, 10, 18, 10, 30, 0, 0, berlin)
+
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"2026-10-20T15:30:00-04:00", "2026-10-20T15:30:00-04:00"},
+		{"2026-10-20T15:30:00Z", "2026-10-20T15:30:00Z"},
+		{"2026-10-20T15:30:00", "2026-10-20T15:30:00+02:00"},
+		{"2026-10-20 15:30", "2026-10-20T15:30:00+02:00"},
+		{"2026-10-20", "2026-10-20T00:00:00+02:00"},
+		{"2026-10-20 15:30 America/New_York", "2026-10-20T15:30:00-04:00"},
+		{"2026-10-20 15:30 UTC", "2026-10-20T15:30:00Z"},
+		{"now", "2026-10-18T10:30:00+02:00"},
+		{"+2h", "2026-10-18T12:30:00+02:00"},
+		{"-30m", "2026-10-18T10:00:00+02:00"},
+		{"+1d", "2026-10-19T10:30:00+02:00"},
+		{"in 3 days", "2026-10-21T10:30:00+02:00"},
+		{"in 90m", "2026-10-18T12:00:00+02:00"},
+		{"today 16:00", "2026-10-18T16:00:00+02:00"},
+		{"tomorrow 14:00", "2026-10-19T14:00:00+02:00"},
+		{"tomorrow at 9:30pm", "2026-10-19T21:30:00+02:00"},
+		{"yesterday noon", "2026-10-17T12:00:00+02:00"},
+		{"next tue 9am", "2026-10-20T09:00:00+02:00"},
+		{"tue 9 am", "2026-10-20T09:00:00+02:00"},
+		{"sun 12am", "2026-10-18T00:00:00+02:00"},
+		{"next sunday", "2026-10-25T00:00:00+02:00"},
+		{"15:00", "2026-10-18T15:00:00+02:00"},
+		{"oct 20 14:00", "2026-10-20T14:00:00+02:00"},
+		{"20 oct 2027", "2027-10-20T00:00:00+02:00"},
+		{"March 3rd, 2027 10am", "2027-03-03T10:00:00+01:00"},
+		{"jan 5", "2027-01-05T00:00:00+01:00"},
+		{"2026-10-26 9am", "2026-10-26T09:00:00+01:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := Parse(tt.expr, now, berlin)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.Format(time.RFC3339))
+		})
+	}
+
+	// Day offsets keep the clock time across the end of DST (Oct 25).
+	dstEve := time.Date(2026, 10, 24, 10, 30, 0, 0, berlin)
+	for expr, want := range map[string]string{
+		"+1d":        "2026-10-25T10:30:00+01:00",
+		"in 1 week":  "2026-10-31T10:30:00+01:00",
+		"in 2 weeks": "2026-11-07T10:30:00+01:00",
+		"+1w":        "2026-10-31T10:30:00+01:00",
+		"+24h":       "2026-10-25T09:30:00+01:00",
+	} {
+		got, err := Parse(expr, dstEve, berlin)
+		require.NoError(t, err, expr)
+		assert.Equal(t, want, got.Format(time.RFC3339), expr)
+	}
+	got, err := Parse("-1d", time.Date(2026, 10, 25, 10, 30, 0, 0, berlin), berlin)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-10-24T10:30:00+02:00", got.Format(time.RFC3339))
+
+	for _, expr := range []string{"", "soon", "tomorrow today", "25:00", "13pm", "feb 30", "2026-10-20 Mars/Base", "9 10"} {
+		_, err := Parse(expr, now, berlin)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := map[string]time.Duration{
+		"30m":   30 * time.Minute,
+		"1h30m": 90 * time.Minute,
+		"1.5h":  90 * time.Minute,
+		"45":    45 * time.Minute,
+		"2d":    48 * time.Hour,
+		"1w":    7 * 24 * time.Hour,
+	}
+	for s, want := range tests {
+		got, err := ParseDuration(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, want, got, s)
+	}
+
+	_, err := ParseDuration("a while")
+	assert.Error(t, err)
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	start := time.Date(2026, 10, 24, 9, 0, 0, 0, berlin)
+	end, err := AddDuration(start, "2d")
+	require.NoError(t, err)
+	assert.Equal(t, "2026-10-26T09:00:00+01:00", end.Format(time.RFC3339))
+	end, err = AddDuration(start, "90m")
+	require.NoError(t, err)
+	assert.Equal(t, "2026-10-24T10:30:00+02:00", end.Format(time.RFC3339))
+}
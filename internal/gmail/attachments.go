@@ -0,0 +1,397 @@
+package gmail
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+// ManifestFileName is the name of the manifest written next to downloaded
+// attachments.
+const ManifestFileName = "manifest.json"
+
+// DefaultMaxTextBytes is the default limit for extracted attachment text.
+const DefaultMaxTextBytes = 100000
+
+// DownloadOptions contains options for downloading attachments.
+type DownloadOptions struct {
+	MessageIDs   []string
+	Dir          string
+	ExtractText  bool
+	MaxTextBytes int
+}
+
+// SavedAttachment describes an attachment written to disk.
+type SavedAttachment struct {
+	MessageID     string `json:"message_id"`
+	AttachmentID  string `json:"attachment_id,omitempty"`
+	Filename      string `json:"filename"`
+	Path          string `json:"path"`
+	MimeType      string `json:"mime_type"`
+	Size          int64  `json:"size"`
+	SHA256        string `json:"sha256"`
+	Text          string `json:"text,omitempty"`
+	TextTruncated bool   `json:"text_truncated,omitempty"`
+}
+
+// DownloadError describes a message or attachment that could not be saved.
+type DownloadError struct {
+	MessageID string `json:"message_id"`
+	Filename  string `json:"filename,omitempty"`
+	Error     string `json:"error"`
+}
+
+// DownloadResult represents the result of downloading attachments.
+type DownloadResult struct {
+	Dir         string            `json:"dir"`
+	Manifest    string            `json:"manifest"`
+	Attachments []SavedAttachment `json:"attachments"`
+	Count       int               `json:"count"`
+	Errors      []DownloadError   `json:"errors,omitempty"`
+}
+
+// DownloadAttachments saves every attachment of the given messages into
+// opts.Dir using sanitized, de-duplicated filenames and writes a manifest
+// with SHA-256 checksums. With ExtractText, the text of text-like
+// attachments (text/*, CSV, JSON, ICS, EML) is returned inline.
+func (s *Service) DownloadAttachments(opts DownloadOptions) (*DownloadResult, error) {
+	if err := os.MkdirAll(opts.Dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	maxText := opts.MaxTextBytes
+	if maxText <= 0 {
+		maxText = DefaultMaxTextBytes
+	}
+
+	result := &DownloadResult{
+		Dir:         opts.Dir,
+		Manifest:    filepath.Join(opts.Dir, ManifestFileName),
+		Attachments: []SavedAttachment{},
+	}
+	used := map[string]bool{ManifestFileName: true}
+
+	for _, messageID := range opts.MessageIDs {
+		msg, err := s.svc.Users.Messages.Get("me", messageID).Format("full").Do()
+		if err != nil {
+			result.Errors = append(result.Errors, DownloadError{
+				MessageID: messageID,
+				Error:     fmt.Sprintf("failed to get message: %v", err),
+			})
+			continue
+		}
+
+		for _, part := range attachmentParts(msg.Payload) {
+			data, err := s.partData(messageID, part)
+			if err != nil {
+				result.Errors = append(result.Errors, DownloadError{
+					MessageID: messageID,
+					Filename:  part.Filename,
+					Error:     err.Error(),
+				})
+				continue
+			}
+
+			name := uniqueFilename(opts.Dir, sanitizeFilename(part.Filename), used)
+			path := filepath.Join(opts.Dir, name)
+			if err := os.WriteFile(path, data, 0644); err != nil {
+				result.Errors = append(result.Errors, DownloadError{
+					MessageID: messageID,
+					Filename:  part.Filename,
+					Error:     fmt.Sprintf("failed to write attachment: %v", err),
+				})
+				continue
+			}
+
+			sum := sha256.Sum256(data)
+			saved := SavedAttachment{
+				MessageID: messageID,
+				Filename:  part.Filename,
+				Path:      path,
+				MimeType:  part.MimeType,
+				Size:      int64(len(data)),
+				SHA256:    hex.EncodeToString(sum[:]),
+			}
+			if part.Body != nil {
+				saved.AttachmentID = part.Body.AttachmentId
+			}
+
+			if opts.ExtractText && IsTextAttachment(part.MimeType, part.Filename) {
+				text, err := ExtractText(part.MimeType, part.Filename, data)
+				if err != nil {
+					result.Errors = append(result.Errors, DownloadError{
+						MessageID: messageID,
+						Filename:  part.Filename,
+						Error:     fmt.Sprintf("failed to extract text: %v", err),
+					})
+				} else {
+					if len(text) > maxText {
+						text = truncateUTF8(text, maxText)
+						saved.TextTruncated = true
+					}
+					saved.Text = text
+				}
+			}
+
+			result.Attachments = append(result.Attachments, saved)
+		}
+	}
+	result.Count = len(result.Attachments)
+
+	if err := writeManifest(result.Manifest, result.Attachments); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// partData returns the decoded content of an attachment part, fetching it
+// from the API when the body is not inlined.
+func (s *Service) partData(messageID string, part *gmail.MessagePart) ([]byte, error) {
+	if part.Body == nil {
+		return nil, fmt.Errorf("attachment has no body")
+	}
+	if part.Body.AttachmentId != "" {
+		return s.GetAttachment(messageID, part.Body.AttachmentId)
+	}
+
+	data, err := base64.URLEncoding.DecodeString(part.Body.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode attachment: %w", err)
+	}
+	return data, nil
+}
+
+// attachmentParts returns all parts of a payload that carry a filename.
+func attachmentParts(payload *gmail.MessagePart) []*gmail.MessagePart {
+	var parts []*gmail.MessagePart
+
+	var walk func(part *gmail.MessagePart)
+	walk = func(part *gmail.MessagePart) {
+		if part == nil {
+			return
+		}
+		if part.Filename != "" && part.Body != nil && (part.Body.AttachmentId != "" || part.Body.Data != "") {
+			parts = append(parts, part)
+		}
+		for _, p := range part.Parts {
+			walk(p)
+		}
+	}
+
+	walk(payload)
+	return parts
+}
+
+// writeManifest writes the attachment list without extracted text.
+func writeManifest(path string, attachments []SavedAttachment) error {
+	entries := make([]SavedAttachment, len(attachments))
+	for i, a := range attachments {
+		a.Text = ""
+		a.TextTruncated = false
+		entries[i] = a
+	}
+
+	data, err := json.MarshalIndent(map[string]interface{}{
+		"attachments": entries,
+		"count":       len(entries),
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return nil
+}
+
+var unsafeFilenameChars = regexp.MustCompile(`[\x00-\x1f<>:"/\\|?*]+`)
+
+// sanitizeFilename makes an attachment filename safe to write into a
+// directory: path components and reserved characters are removed.
+func sanitizeFilename(name string) string {
+	name = strings.ReplaceAll(name, "\\", "/")
+	name = filepath.Base(name)
+	name = unsafeFilenameChars.ReplaceAllString(name, "_")
+	name = strings.Trim(name, " .")
+	if name == "" {
+		name = "attachment"
+	}
+	if len(name) > 200 {
+		ext := filepath.Ext(name)
+		if len(ext) > 20 {
+			ext = ""
+		}
+		name = truncateUTF8(name, 200-len(ext)) + ext
+	}
+	return name
+}
+
+// uniqueFilename returns name, or name with a numeric suffix, such that it
+// has not been used in this run and does not exist in dir.
+func uniqueFilename(dir, name string, used map[string]bool) string {
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+
+	candidate := name
+	for i := 1; ; i++ {
+		if !used[strings.ToLower(candidate)] {
+			if _, err := os.Stat(filepath.Join(dir, candidate)); os.IsNotExist(err) {
+				used[strings.ToLower(candidate)] = true
+				return candidate
+			}
+		}
+		candidate = fmt.Sprintf("%s-%d%s", base, i, ext)
+	}
+}
+
+// IsTextAttachment reports whether text can be extracted locally from an
+// attachment with the given MIME type or filename.
+func IsTextAttachment(mimeType, filename string) bool {
+	mimeType = strings.ToLower(mimeType)
+	if strings.HasPrefix(mimeType, "text/") {
+		return true
+	}
+	switch mimeType {
+	case "application/json", "application/csv", "application/ics", "message/rfc822":
+		return true
+	}
+
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".txt", ".csv", ".tsv", ".json", ".ics", ".eml", ".md":
+		return true
+	}
+	return false
+}
+
+// ExtractText returns the plain text content of a text-like attachment.
+// EML messages are rendered as their main headers followed by the body.
+func ExtractText(mimeType, filename string, data []byte) (string, error) {
+	if strings.EqualFold(mimeType, "message/rfc822") || strings.EqualFold(filepath.Ext(filename), ".eml") {
+		return emlToText(data)
+	}
+	return string(data), nil
+}
+
+// emlToText renders an RFC 822 message as readable text.
+func emlToText(data []byte) (string, error) {
+	msg, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse message: %w", err)
+	}
+
+	var sb strings.Builder
+	decoder := new(mime.WordDecoder)
+	for _, key := range []string{"From", "To", "Cc", "Date", "Subject"} {
+		value := msg.Header.Get(key)
+		if value == "" {
+			continue
+		}
+		if decoded, err := decoder.DecodeHeader(value); err == nil {
+			value = decoded
+		}
+		sb.WriteString(fmt.Sprintf("%s: %s\n", key, value))
+	}
+	sb.WriteString("\n")
+
+	text, html := readMIMEBody(msg.Header.Get("Content-Type"), msg.Header.Get("Content-Transfer-Encoding"), msg.Body)
+	if text == "" && html != "" {
+		text = stripHTML(html)
+	}
+	sb.WriteString(text)
+
+	return sb.String(), nil
+}
+
+// readMIMEBody returns the first text/plain and text/html bodies of a
+// (possibly multipart) MIME entity.
+func readMIMEBody(contentType, transferEncoding string, r io.Reader) (text, html string) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = "text/plain"
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		mr := multipart.NewReader(r, params["boundary"])
+		for {
+			part, err := mr.NextPart()
+			if err != nil {
+				break
+			}
+			partText, partHTML := readMIMEBody(part.Header.Get("Content-Type"), part.Header.Get("Content-Transfer-Encoding"), part)
+			if text == "" {
+				text = partText
+			}
+			if html == "" {
+				html = partHTML
+			}
+		}
+		return text, html
+	}
+
+	body, err := io.ReadAll(decodeTransfer(transferEncoding, r))
+	if err != nil {
+		return "", ""
+	}
+
+	switch mediaType {
+	case "text/plain":
+		return string(body), ""
+	case "text/html":
+		return "", string(body)
+	}
+	return "", ""
+}
+
+// decodeTransfer wraps r with a decoder for the Content-Transfer-Encoding.
+func decodeTransfer(encoding string, r io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, r)
+	case "quoted-printable":
+		return quotedprintable.NewReader(r)
+	}
+	return r
+}
+
+var (
+	htmlBlockTags = regexp.MustCompile(`(?i)<br\s*/?>|</p>|</div>|</tr>|</li>`)
+	htmlTags      = regexp.MustCompile(`<[^>]*>`)
+)
+
+// stripHTML reduces HTML to plain text by dropping tags.
+func stripHTML(s string) string {
+	text := htmlBlockTags.ReplaceAllString(s, "\n")
+	text = htmlTags.ReplaceAllString(text, "")
+	text = strings.ReplaceAll(html.UnescapeString(text), "\u00a0", " ")
+	return strings.TrimSpace(text)
+}
+
+// truncateUTF8 shortens s to at most n bytes without splitting a UTF-8
+// encoded rune.
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
@@ -0,0 +1,131 @@
+package gmail
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/gmail/v1"
+)
+
+func TestSanitizeFilename(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"invoice.pdf", "invoice.pdf"},
+		{"../../etc/passwd", "passwd"},
+		{`C:\Users\me\report.xlsx`, "report.xlsx"},
+		{"a<b>c?.txt", "a_b_c_.txt"},
+		{"..", "attachment"},
+		{"", "attachment"},
+		{" .hidden. ", "hidden"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.expected, sanitizeFilename(tt.input))
+		})
+	}
+
+	long := strings.Repeat("x", 300) + ".pdf"
+	result := sanitizeFilename(long)
+	assert.Len(t, result, 200)
+	assert.True(t, strings.HasSuffix(result, ".pdf"))
+
+	// Multi-byte names are cut on a rune boundary.
+	result = sanitizeFilename(strings.Repeat("ä", 150) + ".pdf")
+	assert.True(t, utf8.ValidString(result))
+	assert.LessOrEqual(t, len(result), 200)
+	assert.True(t, strings.HasSuffix(result, ".pdf"))
+}
+
+func TestUniqueFilename(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "existing.txt"), []byte("x"), 0644))
+
+	used := map[string]bool{ManifestFileName: true}
+	assert.Equal(t, "report.pdf", uniqueFilename(dir, "report.pdf", used))
+	assert.Equal(t, "report-1.pdf", uniqueFilename(dir, "report.pdf", used))
+	assert.Equal(t, "Report-2.pdf", uniqueFilename(dir, "Report.pdf", used))
+	assert.Equal(t, "existing-1.txt", uniqueFilename(dir, "existing.txt", used))
+	assert.Equal(t, "manifest-1.json", uniqueFilename(dir, "manifest.json", used))
+}
+
+func TestIsTextAttachment(t *testing.T) {
+	assert.True(t, IsTextAttachment("text/plain", "notes.txt"))
+	assert.True(t, IsTextAttachment("text/csv", "data.csv"))
+	assert.True(t, IsTextAttachment("application/octet-stream", "data.csv"))
+	assert.True(t, IsTextAttachment("application/json", "x"))
+	assert.True(t, IsTextAttachment("text/calendar", "invite.ics"))
+	assert.True(t, IsTextAttachment("message/rfc822", "fwd.eml"))
+	assert.False(t, IsTextAttachment("application/pdf", "invoice.pdf"))
+	assert.False(t, IsTextAttachment("image/png", "logo.png"))
+}
+
+func TestExtractText_EML(t *testing.T) {
+	eml := "From: Alice <alice@example.com>\r\n" +
+		"To: bob@example.com\r\n" +
+		"Subject: =?UTF-8?Q?Invoice_f=C3=BCr_March?=\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"Content-Type: multipart/alternative; boundary=\"b1\"\r\n" +
+		"\r\n" +
+		"--b1\r\n" +
+		"Content-Type: text/plain; charset=UTF-8\r\n" +
+		"Content-Transfer-Encoding: quoted-printable\r\n" +
+		"\r\n" +
+		"Total: 100 =E2=82=AC\r\n" +
+		"--b1\r\n" +
+		"Content-Type: text/html; charset=UTF-8\r\n" +
+		"\r\n" +
+		"<p>Total: 100 &euro;</p>\r\n" +
+		"--b1--\r\n"
+
+	text, err := ExtractText("message/rfc822", "invoice.eml", []byte(eml))
+	require.NoError(t, err)
+	assert.Contains(t, text, "From: Alice <alice@example.com>")
+	assert.Contains(t, text, "Subject: Invoice für March")
+	assert.Contains(t, text, "Total: 100 €")
+	assert.NotContains(t, text, "<p>")
+}
+
+func TestExtractText_EMLHTMLOnly(t *testing.T) {
+	eml := "Subject: Hi\r\n" +
+		"Content-Type: text/html\r\n" +
+		"Content-Transfer-Encoding: base64\r\n" +
+		"\r\n" +
+		"PHA+SGVsbG8gJmFtcDsgd2VsY29tZTwvcD4=\r\n"
+
+	text, err := ExtractText("", "hello.eml", []byte(eml))
+	require.NoError(t, err)
+	assert.Contains(t, text, "Hello & welcome")
+}
+
+func TestAttachmentParts(t *testing.T) {
+	payload := &gmail.MessagePart{
+		MimeType: "multipart/mixed",
+		Parts: []*gmail.MessagePart{
+			{MimeType: "text/plain", Body: &gmail.MessagePartBody{Data: "aGk="}},
+			{MimeType: "application/pdf", Filename: "a.pdf", Body: &gmail.MessagePartBody{AttachmentId: "att1"}},
+			{MimeType: "text/csv", Filename: "b.csv", Body: &gmail.MessagePartBody{Data: "YSxi"}},
+		},
+	}
+
+	parts := attachmentParts(payload)
+	require.Len(t, parts, 2)
+	assert.Equal(t, "a.pdf", parts[0].Filename)
+	assert.Equal(t, "b.csv", parts[1].Filename)
+}
+
+func TestTruncateUTF8(t *testing.T) {
+	assert.Equal(t, "short", truncateUTF8("short", 10))
+	assert.Equal(t, "ab", truncateUTF8("abc", 2))
+	// "ä" is two bytes; cutting after its first byte backs off before it.
+	assert.Equal(t, "a", truncateUTF8("aäb", 2))
+	assert.Equal(t, "aä", truncateUTF8("aäb", 3))
+	assert.True(t, utf8.ValidString(truncateUTF8("日本語", 4)))
+}
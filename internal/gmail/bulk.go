@@ -0,0 +1,192 @@
+package gmail
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+// batchModifyLimit is the maximum number of message IDs accepted by a
+// single Users.Messages.BatchModify request.
+const batchModifyLimit = 1000
+
+// listPageSize is the page size used when paging through message IDs.
+const listPageSize = 500
+
+// BulkOptions contains options for modifying all messages matching a query.
+type BulkOptions struct {
+	Query        string
+	AddLabels    []string // Label IDs or names
+	RemoveLabels []string // Label IDs or names
+	Max          int      // 0 means no limit
+	DryRun       bool
+}
+
+// BulkProgress describes one completed BatchModify chunk.
+type BulkProgress struct {
+	Batch     int `json:"batch"`
+	Size      int `json:"size"`
+	Processed int `json:"processed"`
+}
+
+// BulkResult represents the result of a bulk modification.
+type BulkResult struct {
+	Query        string         `json:"query"`
+	Matched      int            `json:"matched"`
+	Modified     int            `json:"modified"`
+	Truncated    bool           `json:"truncated"`
+	DryRun       bool           `json:"dry_run"`
+	AddLabels    []string       `json:"add_labels,omitempty"`
+	RemoveLabels []string       `json:"remove_labels,omitempty"`
+	Progress     []BulkProgress `json:"progress,omitempty"`
+}
+
+// Bulk applies label changes to every message matching opts.Query.
+// Messages are modified with BatchModify in chunks of up to 1000 IDs.
+func (s *Service) Bulk(opts BulkOptions) (*BulkResult, error) {
+	if opts.Query == "" {
+		return nil, fmt.Errorf("query is required")
+	}
+	if len(opts.AddLabels) == 0 && len(opts.RemoveLabels) == 0 {
+		return nil, fmt.Errorf("no action specified")
+	}
+
+	addIDs, err := s.ResolveLabelIDs(opts.AddLabels)
+	if err != nil {
+		return nil, err
+	}
+	removeIDs, err := s.ResolveLabelIDs(opts.RemoveLabels)
+	if err != nil {
+		return nil, err
+	}
+
+	ids, truncated, err := s.ListIDs(opts.Query, opts.Max)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &BulkResult{
+		Query:        opts.Query,
+		Matched:      len(ids),
+		Truncated:    truncated,
+		DryRun:       opts.DryRun,
+		AddLabels:    addIDs,
+		RemoveLabels: removeIDs,
+	}
+
+	if opts.DryRun {
+		return result, nil
+	}
+
+	for i, chunk := range chunkIDs(ids, batchModifyLimit) {
+		req := &gmail.BatchModifyMessagesRequest{
+			Ids:            chunk,
+			AddLabelIds:    addIDs,
+			RemoveLabelIds: removeIDs,
+		}
+		if err := s.svc.Users.Messages.BatchModify("me", req).Do(); err != nil {
+			return result, fmt.Errorf("failed to modify batch %d: %w", i+1, err)
+		}
+
+		result.Modified += len(chunk)
+		result.Progress = append(result.Progress, BulkProgress{
+			Batch:     i + 1,
+			Size:      len(chunk),
+			Processed: result.Modified,
+		})
+	}
+
+	return result, nil
+}
+
+// ListIDs pages through Users.Messages.List and returns the IDs of all
+// messages matching query (all messages if empty), up to max (0 means no
+// limit). The boolean result reports whether more matches were available
+// beyond max.
+func (s *Service) ListIDs(query string, max int) ([]string, bool, error) {
+	var ids []string
+	pageToken := ""
+
+	for {
+		call := s.svc.Users.Messages.List("me").MaxResults(listPageSize)
+		if query != "" {
+			call = call.Q(query)
+		}
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+
+		resp, err := call.Do()
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to list messages: %w", err)
+		}
+
+		for _, msg := range resp.Messages {
+			if max > 0 && len(ids) >= max {
+				return ids, true, nil
+			}
+			ids = append(ids, msg.Id)
+		}
+
+		if resp.NextPageToken == "" {
+			return ids, false, nil
+		}
+		if max > 0 && len(ids) >= max {
+			return ids, true, nil
+		}
+		pageToken = resp.NextPageToken
+	}
+}
+
+// ResolveLabelIDs maps label names to label IDs. Values that already match
+// a label ID are returned unchanged; names are matched case-insensitively.
+func (s *Service) ResolveLabelIDs(names []string) ([]string, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	labels, err := s.Labels()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(names))
+	for _, name := range names {
+		id, ok := matchLabel(labels, name)
+		if !ok {
+			return nil, fmt.Errorf("label not found: %s", name)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// matchLabel finds a label by ID or case-insensitive name.
+func matchLabel(labels []LabelInfo, name string) (string, bool) {
+	for _, l := range labels {
+		if l.ID == name {
+			return l.ID, true
+		}
+	}
+	for _, l := range labels {
+		if strings.EqualFold(l.Name, name) {
+			return l.ID, true
+		}
+	}
+	return "", false
+}
+
+// chunkIDs splits ids into consecutive chunks of at most size elements.
+func chunkIDs(ids []string, size int) [][]string {
+	var chunks [][]string
+	for start := 0; start < len(ids); start += size {
+		end := start + size
+		if end > len(ids) {
+			end = len(ids)
+		}
+		chunks = append(chunks, ids[start:end])
+	}
+	return chunks
+}
@@ -0,0 +1,56 @@
+package gmail
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChunkIDs(t *testing.T) {
+	tests := []struct {
+		name     string
+		count    int
+		size     int
+		expected []int
+	}{
+		{"empty", 0, 1000, nil},
+		{"single partial chunk", 3, 1000, []int{3}},
+		{"exact chunk", 1000, 1000, []int{1000}},
+		{"multiple chunks", 2500, 1000, []int{1000, 1000, 500}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids := make([]string, tt.count)
+			chunks := chunkIDs(ids, tt.size)
+
+			var sizes []int
+			for _, c := range chunks {
+				sizes = append(sizes, len(c))
+			}
+			assert.Equal(t, tt.expected, sizes)
+		})
+	}
+}
+
+func TestMatchLabel(t *testing.T) {
+	labels := []LabelInfo{
+		{ID: "INBOX", Name: "INBOX", Type: "system"},
+		{ID: "Label_1", Name: "Newsletters", Type: "user"},
+	}
+
+	id, ok := matchLabel(labels, "INBOX")
+	assert.True(t, ok)
+	assert.Equal(t, "INBOX", id)
+
+	id, ok = matchLabel(labels, "newsletters")
+	assert.True(t, ok)
+	assert.Equal(t, "Label_1", id)
+
+	id, ok = matchLabel(labels, "Label_1")
+	assert.True(t, ok)
+	assert.Equal(t, "Label_1", id)
+
+	_, ok = matchLabel(labels, "Receipts")
+	assert.False(t, ok)
+}
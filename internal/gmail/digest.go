@@ -0,0 +1,401 @@
+package gmail
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+// DefaultDigestMax is the default maximum number of messages in a digest.
+const DefaultDigestMax = 500
+
+// categoryNames maps Gmail category labels to digest group names.
+var categoryNames = map[string]string{
+	"CATEGORY_PERSONAL":   "primary",
+	"CATEGORY_SOCIAL":     "social",
+	"CATEGORY_PROMOTIONS": "promotions",
+	"CATEGORY_UPDATES":    "updates",
+	"CATEGORY_FORUMS":     "forums",
+}
+
+// DigestOptions contains options for building an inbox digest.
+type DigestOptions struct {
+	Since   time.Time
+	Query   string // Scope of the digest; defaults to in:inbox
+	Max     int
+	Workers int
+}
+
+// DigestThread summarizes the messages of one thread within the digest
+// window.
+type DigestThread struct {
+	ID            string `json:"id"`
+	Subject       string `json:"subject"`
+	From          string `json:"from"`
+	Date          string `json:"date"`
+	Snippet       string `json:"snippet,omitempty"`
+	Messages      int    `json:"messages"`
+	Unread        bool   `json:"unread,omitempty"`
+	HasAttachment bool   `json:"has_attachment,omitempty"`
+	Invite        bool   `json:"invite,omitempty"`
+	Newsletter    bool   `json:"newsletter,omitempty"`
+
+	group  string
+	latest int64
+	direct bool
+}
+
+// DigestGroup is a set of threads sharing a category or label.
+type DigestGroup struct {
+	Name    string         `json:"name"`
+	Count   int            `json:"count"`
+	Threads []DigestThread `json:"threads"`
+}
+
+// DomainCount is the number of messages from one sender domain.
+type DomainCount struct {
+	Domain string `json:"domain"`
+	Count  int    `json:"count"`
+}
+
+// DigestNewsletter summarizes messages from one bulk sender.
+type DigestNewsletter struct {
+	From        string `json:"from"`
+	Count       int    `json:"count"`
+	Subject     string `json:"subject"`
+	MessageID   string `json:"message_id"`
+	Unsubscribe string `json:"unsubscribe,omitempty"`
+	OneClick    bool   `json:"one_click,omitempty"`
+
+	latest int64
+}
+
+// DigestMessage identifies a single message in the digest.
+type DigestMessage struct {
+	ID       string `json:"id"`
+	ThreadID string `json:"thread_id"`
+	From     string `json:"from"`
+	Subject  string `json:"subject"`
+	Date     string `json:"date"`
+}
+
+// DigestResult is a compact overview of recent mail.
+type DigestResult struct {
+	Since         string             `json:"since"`
+	Query         string             `json:"query"`
+	MessageCount  int                `json:"message_count"`
+	ThreadCount   int                `json:"thread_count"`
+	Truncated     bool               `json:"truncated,omitempty"`
+	Groups        []DigestGroup      `json:"groups"`
+	SenderDomains []DomainCount      `json:"sender_domains"`
+	AwaitingReply []DigestThread     `json:"awaiting_reply"`
+	Newsletters   []DigestNewsletter `json:"newsletters"`
+	Invites       []DigestMessage    `json:"invites"`
+	Attachments   []DigestMessage    `json:"attachments"`
+	Failed        []FetchError       `json:"failed,omitempty"`
+}
+
+// digestInput is everything needed to build a digest once messages are
+// fetched.
+type digestInput struct {
+	messages    []*gmail.Message
+	sent        []*gmail.Message
+	identity    *Identity
+	labelNames  map[string]string
+	attachments map[string]bool
+}
+
+// Digest builds a digest of messages received since opts.Since. Messages
+// are fetched with their headers and MIME structure, but no bodies, by a
+// concurrent worker pool; invitations are recognized by their text/calendar
+// part. Sent messages in the same window are used to tell which threads
+// still await a reply.
+func (s *Service) Digest(opts DigestOptions) (*DigestResult, error) {
+	if opts.Max <= 0 {
+		opts.Max = DefaultDigestMax
+	}
+	scope := strings.TrimSpace(opts.Query)
+	if scope == "" {
+		scope = "in:inbox"
+	}
+	after := fmt.Sprintf("after:%d", opts.Since.Unix())
+	query := after + " " + scope
+
+	identity, err := s.Identity()
+	if err != nil {
+		return nil, err
+	}
+
+	labels, err := s.Labels()
+	if err != nil {
+		return nil, err
+	}
+	labelNames := make(map[string]string, len(labels))
+	for _, l := range labels {
+		if l.Type == "user" {
+			labelNames[l.ID] = l.Name
+		}
+	}
+
+	ids, truncated, err := s.ListIDs(query, opts.Max)
+	if err != nil {
+		return nil, err
+	}
+
+	// Gmail's own attachment detection also covers parts nested deeper
+	// than the fetched structure.
+	withAttachments, _, err := s.ListIDs(query+" has:attachment", opts.Max)
+	if err != nil {
+		return nil, err
+	}
+	sentIDs, _, err := s.ListIDs(after+" in:sent", opts.Max)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, failed := s.fetchStructure(ids, opts.Workers)
+	sent, _ := s.fetchMetadata(sentIDs, []string{"Date"}, opts.Workers)
+
+	result := buildDigest(digestInput{
+		messages:    messages,
+		sent:        sent,
+		identity:    identity,
+		labelNames:  labelNames,
+		attachments: toSet(withAttachments),
+	})
+	result.Since = opts.Since.Format(time.RFC3339)
+	result.Query = query
+	result.Truncated = truncated
+	result.Failed = failed
+
+	return result, nil
+}
+
+// buildDigest aggregates fetched messages into a digest.
+func buildDigest(in digestInput) *DigestResult {
+	result := &DigestResult{
+		Groups:        []DigestGroup{},
+		SenderDomains: []DomainCount{},
+		AwaitingReply: []DigestThread{},
+		Newsletters:   []DigestNewsletter{},
+		Invites:       []DigestMessage{},
+		Attachments:   []DigestMessage{},
+	}
+
+	// Latest reply by the user per thread.
+	lastSent := map[string]int64{}
+	for _, m := range in.sent {
+		if m != nil && m.InternalDate > lastSent[m.ThreadId] {
+			lastSent[m.ThreadId] = m.InternalDate
+		}
+	}
+
+	threads := map[string]*DigestThread{}
+	var threadOrder []string
+	domains := map[string]int{}
+	newsletters := map[string]*DigestNewsletter{}
+	var newsletterOrder []string
+
+	for _, m := range in.messages {
+		if m == nil {
+			continue
+		}
+		result.MessageCount++
+
+		headers := extractHeaders(m.Payload)
+		from := headerValue(headers, "From")
+		fromSelf := in.identity.Owns(from)
+		unsubscribe := headerValue(headers, "List-Unsubscribe")
+		bulk := isBulkMessage(headers)
+
+		ref := DigestMessage{
+			ID:       m.Id,
+			ThreadID: m.ThreadId,
+			From:     from,
+			Subject:  headerValue(headers, "Subject"),
+			Date:     time.UnixMilli(m.InternalDate).Format(time.RFC3339),
+		}
+
+		if !fromSelf {
+			if domain := addressDomain(from); domain != "" {
+				domains[domain]++
+			}
+		}
+
+		if in.attachments[m.Id] {
+			result.Attachments = append(result.Attachments, ref)
+		}
+		invite := hasCalendarPart(m.Payload)
+		if invite {
+			result.Invites = append(result.Invites, ref)
+		}
+
+		if unsubscribe != "" {
+			key := normalizeAddress(from)
+			n, ok := newsletters[key]
+			if !ok {
+				n = &DigestNewsletter{From: from}
+				newsletters[key] = n
+				newsletterOrder = append(newsletterOrder, key)
+			}
+			n.Count++
+			if m.InternalDate >= n.latest {
+				n.latest = m.InternalDate
+				n.Subject = ref.Subject
+				n.MessageID = m.Id
+				n.Unsubscribe = unsubscribe
+				n.OneClick = strings.Contains(strings.ToLower(headerValue(headers, "List-Unsubscribe-Post")), "one-click")
+			}
+		}
+
+		t, ok := threads[m.ThreadId]
+		if !ok {
+			t = &DigestThread{ID: m.ThreadId}
+			threads[m.ThreadId] = t
+			threadOrder = append(threadOrder, m.ThreadId)
+		}
+		t.Messages++
+		if hasLabel(m.LabelIds, "UNREAD") {
+			t.Unread = true
+		}
+		t.HasAttachment = t.HasAttachment || in.attachments[m.Id]
+		t.Invite = t.Invite || invite
+		if m.InternalDate >= t.latest {
+			t.latest = m.InternalDate
+			t.Subject = ref.Subject
+			t.From = from
+			t.Date = ref.Date
+			t.Snippet = m.Snippet
+			t.Newsletter = bulk
+			t.group = digestGroup(m.LabelIds, in.labelNames)
+			t.direct = !fromSelf && !bulk && addressedTo(headers, in.identity)
+		}
+	}
+
+	result.ThreadCount = len(threads)
+
+	// Threads newest first.
+	sort.SliceStable(threadOrder, func(i, j int) bool {
+		return threads[threadOrder[i]].latest > threads[threadOrder[j]].latest
+	})
+
+	groups := map[string]*DigestGroup{}
+	var groupOrder []string
+	for _, id := range threadOrder {
+		t := threads[id]
+		g, ok := groups[t.group]
+		if !ok {
+			g = &DigestGroup{Name: t.group}
+			groups[t.group] = g
+			groupOrder = append(groupOrder, t.group)
+		}
+		g.Threads = append(g.Threads, *t)
+		g.Count++
+
+		awaiting := t.direct && lastSent[id] < t.latest &&
+			(t.group != "promotions" && t.group != "social" && t.group != "forums")
+		if awaiting {
+			result.AwaitingReply = append(result.AwaitingReply, *t)
+		}
+	}
+
+	sort.SliceStable(groupOrder, func(i, j int) bool {
+		return groups[groupOrder[i]].Count > groups[groupOrder[j]].Count
+	})
+	for _, name := range groupOrder {
+		result.Groups = append(result.Groups, *groups[name])
+	}
+
+	for domain, count := range domains {
+		result.SenderDomains = append(result.SenderDomains, DomainCount{Domain: domain, Count: count})
+	}
+	sort.Slice(result.SenderDomains, func(i, j int) bool {
+		a, b := result.SenderDomains[i], result.SenderDomains[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Domain < b.Domain
+	})
+
+	for _, key := range newsletterOrder {
+		result.Newsletters = append(result.Newsletters, *newsletters[key])
+	}
+	sort.SliceStable(result.Newsletters, func(i, j int) bool {
+		return result.Newsletters[i].Count > result.Newsletters[j].Count
+	})
+
+	return result
+}
+
+// digestGroup returns the group of a message: its first user label by
+// name, otherwise its Gmail category, otherwise "inbox".
+func digestGroup(labelIDs []string, labelNames map[string]string) string {
+	var names []string
+	for _, id := range labelIDs {
+		if name, ok := labelNames[id]; ok {
+			names = append(names, name)
+		}
+	}
+	if len(names) > 0 {
+		sort.Strings(names)
+		return names[0]
+	}
+
+	for _, id := range labelIDs {
+		if name, ok := categoryNames[id]; ok {
+			return name
+		}
+	}
+	return "inbox"
+}
+
+// isBulkMessage reports whether a message was sent by a mailing list or
+// bulk sender.
+func isBulkMessage(headers map[string]string) bool {
+	if headerValue(headers, "List-Unsubscribe") != "" || headerValue(headers, "List-Id") != "" {
+		return true
+	}
+	precedence := strings.ToLower(headerValue(headers, "Precedence"))
+	return precedence == "bulk" || precedence == "list"
+}
+
+// addressedTo reports whether one of the user's addresses is in To or Cc.
+func addressedTo(headers map[string]string, id *Identity) bool {
+	for _, key := range []string{"To", "Cc"} {
+		for _, addr := range parseAddressHeader(headerValue(headers, key)) {
+			if id.Owns(addr) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// addressDomain returns the lower-cased domain of an address header value.
+func addressDomain(from string) string {
+	addr := normalizeAddress(from)
+	if i := strings.LastIndex(addr, "@"); i >= 0 {
+		return addr[i+1:]
+	}
+	return ""
+}
+
+func hasLabel(labelIDs []string, label string) bool {
+	for _, id := range labelIDs {
+		if id == label {
+			return true
+		}
+	}
+	return false
+}
+
+func toSet(ids []string) map[string]bool {
+	set := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set
+}
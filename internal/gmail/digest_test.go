@@ -0,0 +1,109 @@
+package gmail
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/gmail/v1"
+)
+
+func digestMessage(id, thread string, date int64, labels []string, headers map[string]string) *gmail.Message {
+	payload := &gmail.MessagePart{}
+	for k, v := range headers {
+		payload.Headers = append(payload.Headers, &gmail.MessagePartHeader{Name: k, Value: v})
+	}
+	return &gmail.Message{Id: id, ThreadId: thread, InternalDate: date, LabelIds: labels, Payload: payload}
+}
+
+func TestBuildDigest(t *testing.T) {
+	in := digestInput{
+		identity:   testIdentity(),
+		labelNames: map[string]string{"Label_1": "Clients"},
+		messages: []*gmail.Message{
+			digestMessage("m1", "t1", 1000, []string{"INBOX", "UNREAD", "CATEGORY_PERSONAL"},
+				map[string]string{"From": "Alice <alice@acme.com>", "To": "me@example.com", "Subject": "Contract"}),
+			digestMessage("m2", "t1", 2000, []string{"INBOX", "CATEGORY_PERSONAL"},
+				map[string]string{"From": "alice@acme.com", "To": "me@example.com", "Subject": "Re: Contract"}),
+			digestMessage("m3", "t2", 1500, []string{"INBOX", "CATEGORY_PROMOTIONS"},
+				map[string]string{"From": "news@shop.com", "To": "me@example.com", "Subject": "Sale",
+					"List-Unsubscribe": "<https://shop.com/u>", "List-Unsubscribe-Post": "List-Unsubscribe=One-Click"}),
+			digestMessage("m4", "t3", 3000, []string{"INBOX", "Label_1"},
+				map[string]string{"From": "bob@acme.com", "To": "support@example.com", "Subject": "Invite"}),
+			digestMessage("m5", "t4", 500, []string{"INBOX"},
+				map[string]string{"From": "carol@other.org", "To": "team@other.org", "Subject": "FYI"}),
+			nil,
+		},
+		sent: []*gmail.Message{
+			{Id: "s1", ThreadId: "t3", InternalDate: 3500},
+		},
+		attachments: map[string]bool{"m4": true},
+	}
+	// An invitation without an .ics filename, as Outlook sends it.
+	in.messages[3].Payload.MimeType = "multipart/mixed"
+	in.messages[3].Payload.Parts = []*gmail.MessagePart{
+		{MimeType: "multipart/alternative", Parts: []*gmail.MessagePart{
+			{MimeType: "text/plain"},
+			{MimeType: "text/calendar"},
+		}},
+	}
+
+	d := buildDigest(in)
+
+	assert.Equal(t, 5, d.MessageCount)
+	assert.Equal(t, 4, d.ThreadCount)
+
+	// Equal-sized groups keep the order of their newest thread.
+	names := []string{}
+	for _, g := range d.Groups {
+		names = append(names, g.Name)
+	}
+	assert.Equal(t, []string{"Clients", "primary", "promotions", "inbox"}, names)
+
+	primary := d.Groups[1].Threads[0]
+	assert.Equal(t, 2, primary.Messages)
+	assert.Equal(t, "Re: Contract", primary.Subject)
+	assert.True(t, primary.Unread)
+
+	assert.Equal(t, []DomainCount{{"acme.com", 3}, {"other.org", 1}, {"shop.com", 1}}, d.SenderDomains)
+
+	// t1 is addressed to the user and unanswered; t3 was answered, t2 is a
+	// newsletter and t4 is not addressed to the user.
+	require.Len(t, d.AwaitingReply, 1)
+	assert.Equal(t, "t1", d.AwaitingReply[0].ID)
+
+	require.Len(t, d.Newsletters, 1)
+	assert.Equal(t, "m3", d.Newsletters[0].MessageID)
+	assert.True(t, d.Newsletters[0].OneClick)
+
+	require.Len(t, d.Invites, 1)
+	assert.Equal(t, "m4", d.Invites[0].ID)
+	require.Len(t, d.Attachments, 1)
+}
+
+func TestDigestGroup(t *testing.T) {
+	names := map[string]string{"Label_2": "Zeta", "Label_1": "Alpha"}
+	assert.Equal(t, "Alpha", digestGroup([]string{"Label_2", "Label_1", "CATEGORY_UPDATES"}, names))
+	assert.Equal(t, "updates", digestGroup([]string{"INBOX", "CATEGORY_UPDATES"}, names))
+	assert.Equal(t, "inbox", digestGroup([]string{"INBOX"}, names))
+}
+
+func TestRunPool(t *testing.T) {
+	var calls int32
+	errs := runPool(50, 4, func(i int) error {
+		atomic.AddInt32(&calls, 1)
+		if i%10 == 0 {
+			return fmt.Errorf("item %d", i)
+		}
+		return nil
+	})
+
+	assert.Equal(t, int32(50), calls)
+	require.Len(t, errs, 50)
+	assert.EqualError(t, errs[20], "item 20")
+	assert.NoError(t, errs[21])
+
+	assert.Empty(t, runPool(0, 4, func(int) error { return nil }))
+}
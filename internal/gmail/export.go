@@ -0,0 +1,333 @@
+package gmail
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+// Export formats.
+const (
+	ExportFormatMbox = "mbox"
+	ExportFormatEML  = "eml"
+)
+
+// ExportOptions contains options for exporting messages.
+type ExportOptions struct {
+	Query  string
+	Format string // mbox or eml
+	Out    string // mbox file or eml directory
+	Max    int    // 0 means no limit
+}
+
+// ExportError describes a message that could not be exported.
+type ExportError struct {
+	MessageID string `json:"message_id"`
+	Error     string `json:"error"`
+}
+
+// ExportResult represents the result of an export run.
+type ExportResult struct {
+	Format       string        `json:"format"`
+	Out          string        `json:"out"`
+	ProgressFile string        `json:"progress_file,omitempty"`
+	Matched      int           `json:"matched"`
+	Exported     int           `json:"exported"`
+	Skipped      int           `json:"skipped"`
+	Truncated    bool          `json:"truncated"`
+	Complete     bool          `json:"complete"`
+	Failed       []ExportError `json:"failed,omitempty"`
+}
+
+// Export writes all messages matching opts.Query in raw form, either
+// appended to an mbox file or as one <id>.eml file per message. Messages
+// already exported by a previous run are skipped, so an interrupted export
+// can be resumed by running it again.
+func (s *Service) Export(opts ExportOptions) (*ExportResult, error) {
+	if opts.Format != ExportFormatMbox && opts.Format != ExportFormatEML {
+		return nil, fmt.Errorf("invalid format: %s (use: mbox, eml)", opts.Format)
+	}
+
+	ids, truncated, err := s.ListIDs(opts.Query, opts.Max)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ExportResult{
+		Format:    opts.Format,
+		Out:       opts.Out,
+		Matched:   len(ids),
+		Truncated: truncated,
+	}
+
+	if opts.Format == ExportFormatEML {
+		err = s.exportEML(ids, opts.Out, result)
+	} else {
+		err = s.exportMbox(ids, opts, result)
+	}
+	if err != nil {
+		return result, err
+	}
+
+	result.Complete = len(result.Failed) == 0 && !truncated
+	return result, nil
+}
+
+// exportEML writes each message to <dir>/<id>.eml, skipping existing files.
+func (s *Service) exportEML(ids []string, dir string, result *ExportResult) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	for _, id := range ids {
+		path := filepath.Join(dir, id+".eml")
+		if _, err := os.Stat(path); err == nil {
+			result.Skipped++
+			continue
+		}
+
+		raw, err := s.GetRaw(id)
+		if err != nil {
+			result.Failed = append(result.Failed, ExportError{MessageID: id, Error: err.Error()})
+			continue
+		}
+
+		// Write to a temporary file first so a partial write is never
+		// mistaken for a finished message on resume.
+		tmp := path + ".tmp"
+		if err := os.WriteFile(tmp, raw, 0644); err != nil {
+			return fmt.Errorf("failed to write message: %w", err)
+		}
+		if err := os.Rename(tmp, path); err != nil {
+			return fmt.Errorf("failed to write message: %w", err)
+		}
+		result.Exported++
+	}
+
+	return nil
+}
+
+// exportMbox appends messages to an mbox file. After each message is
+// written and synced, its ID and the resulting mbox size are appended to a
+// sidecar progress file. On resume the mbox is truncated back to the last
+// recorded size, dropping a message that was only partly written.
+func (s *Service) exportMbox(ids []string, opts ExportOptions, result *ExportResult) error {
+	progressPath := opts.Out + ".progress"
+	result.ProgressFile = progressPath
+
+	p, err := loadExportProgress(progressPath)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(opts.Out), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	f, err := os.OpenFile(opts.Out, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open mbox file: %w", err)
+	}
+	defer f.Close()
+
+	size, err := resumeMbox(f, p)
+	if err != nil {
+		return err
+	}
+
+	if p.partial {
+		// Drop an incomplete last line so new records start on their own line.
+		if err := os.Truncate(progressPath, p.size); err != nil {
+			return fmt.Errorf("failed to repair progress file: %w", err)
+		}
+	}
+	progress, err := os.OpenFile(progressPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open progress file: %w", err)
+	}
+	defer progress.Close()
+
+	if p.size == 0 {
+		// Record where this export starts in case the mbox already has mail.
+		if _, err := fmt.Fprintf(progress, "- %d\n", size); err != nil {
+			return fmt.Errorf("failed to write progress file: %w", err)
+		}
+	}
+
+	for _, id := range ids {
+		if p.done[id] {
+			result.Skipped++
+			continue
+		}
+
+		raw, err := s.GetRaw(id)
+		if err != nil {
+			result.Failed = append(result.Failed, ExportError{MessageID: id, Error: err.Error()})
+			continue
+		}
+
+		entry := mboxEntry(raw)
+		if _, err := f.Write(entry); err != nil {
+			return fmt.Errorf("failed to write mbox: %w", err)
+		}
+		if err := f.Sync(); err != nil {
+			return fmt.Errorf("failed to write mbox: %w", err)
+		}
+		size += int64(len(entry))
+
+		if _, err := fmt.Fprintf(progress, "%s %d\n", id, size); err != nil {
+			return fmt.Errorf("failed to write progress file: %w", err)
+		}
+		p.done[id] = true
+		result.Exported++
+	}
+
+	return nil
+}
+
+// resumeMbox truncates the mbox to the size recorded in the progress file
+// and returns its size.
+func resumeMbox(f *os.File, p *exportProgress) (int64, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return 0, fmt.Errorf("failed to open mbox file: %w", err)
+	}
+	size := info.Size()
+
+	if p.offset < 0 || size == p.offset {
+		return size, nil
+	}
+	if size < p.offset {
+		return 0, fmt.Errorf("mbox file %s is shorter than its progress file records; remove both to start over", f.Name())
+	}
+	if err := f.Truncate(p.offset); err != nil {
+		return 0, fmt.Errorf("failed to truncate partial message: %w", err)
+	}
+	return p.offset, nil
+}
+
+// mboxEntry formats a raw RFC 2822 message as an mboxrd entry: a "From "
+// separator line, the message with LF line endings and ">From " quoting,
+// and a trailing blank line.
+func mboxEntry(raw []byte) []byte {
+	sender := "MAILER-DAEMON"
+	date := time.Now().UTC()
+	if msg, err := mail.ReadMessage(bytes.NewReader(raw)); err == nil {
+		if addr, err := mail.ParseAddress(msg.Header.Get("From")); err == nil && addr.Address != "" {
+			sender = addr.Address
+		}
+		if d, err := msg.Header.Date(); err == nil {
+			date = d.UTC()
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("From %s %s\n", sender, date.Format(time.ANSIC)))
+
+	normalized := strings.TrimRight(strings.ReplaceAll(string(raw), "\r\n", "\n"), "\n")
+	for _, line := range strings.Split(normalized, "\n") {
+		if strings.HasPrefix(strings.TrimLeft(line, ">"), "From ") {
+			buf.WriteString(">")
+		}
+		buf.WriteString(line)
+		buf.WriteString("\n")
+	}
+	buf.WriteString("\n")
+
+	return buf.Bytes()
+}
+
+// exportProgress is the state recorded by earlier runs of an mbox export.
+// Each line of the progress file holds a message ID and the mbox size after
+// it was written; a "-" line records the size the export started at.
+type exportProgress struct {
+	done    map[string]bool
+	offset  int64 // mbox size after the last record, -1 if unknown
+	size    int64 // length of the complete lines
+	partial bool  // the file ends with an incomplete line
+}
+
+// loadExportProgress reads the progress file of an mbox export.
+func loadExportProgress(path string) (*exportProgress, error) {
+	p := &exportProgress{done: make(map[string]bool), offset: -1}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return p, nil
+		}
+		return nil, fmt.Errorf("failed to read progress file: %w", err)
+	}
+
+	complete := data[:bytes.LastIndexByte(data, '\n')+1]
+	p.size = int64(len(complete))
+	p.partial = len(complete) < len(data)
+
+	for _, line := range strings.Split(string(complete), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if fields[0] != "-" {
+			p.done[fields[0]] = true
+		}
+		p.offset = -1
+		if len(fields) > 1 {
+			if n, err := strconv.ParseInt(fields[1], 10, 64); err == nil {
+				p.offset = n
+			}
+		}
+	}
+	return p, nil
+}
+
+// EMLInfo summarizes a local EML file.
+type EMLInfo struct {
+	File      string `json:"file"`
+	Subject   string `json:"subject"`
+	From      string `json:"from,omitempty"`
+	To        string `json:"to,omitempty"`
+	DraftID   string `json:"draft_id,omitempty"`
+	MessageID string `json:"message_id,omitempty"`
+}
+
+// ParseEML validates a local EML file and returns its main headers.
+func ParseEML(raw []byte) (*EMLInfo, error) {
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse EML: %w", err)
+	}
+
+	return &EMLInfo{
+		Subject: msg.Header.Get("Subject"),
+		From:    msg.Header.Get("From"),
+		To:      msg.Header.Get("To"),
+	}, nil
+}
+
+// DraftCreateRaw creates a draft from a raw RFC 2822 message.
+func (s *Service) DraftCreateRaw(raw []byte) (*DraftInfo, error) {
+	draft := &gmail.Draft{
+		Message: &gmail.Message{
+			Raw: base64.URLEncoding.EncodeToString(raw),
+		},
+	}
+
+	created, err := s.svc.Users.Drafts.Create("me", draft).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create draft: %w", err)
+	}
+
+	return &DraftInfo{
+		ID:        created.Id,
+		MessageID: created.Message.Id,
+		ThreadID:  created.Message.ThreadId,
+	}, nil
+}
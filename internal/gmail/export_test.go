@@ -0,0 +1,94 @@
+package gmail
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMboxEntry(t *testing.T) {
+	raw := "From: Alice <alice@example.com>\r\n" +
+		"Date: Tue, 20 Oct 2026 15:30:00 +0200\r\n" +
+		"Subject: Hello\r\n" +
+		"\r\n" +
+		"First line\r\n" +
+		"From here on\r\n" +
+		">From quoted\r\n"
+
+	entry := string(mboxEntry([]byte(raw)))
+
+	assert.Equal(t, "From alice@example.com Tue Oct 20 13:30:00 2026\n"+
+		"From: Alice <alice@example.com>\n"+
+		"Date: Tue, 20 Oct 2026 15:30:00 +0200\n"+
+		"Subject: Hello\n"+
+		"\n"+
+		"First line\n"+
+		">From here on\n"+
+		">>From quoted\n"+
+		"\n", entry)
+}
+
+func TestMboxEntry_Unparseable(t *testing.T) {
+	entry := string(mboxEntry([]byte("not a message")))
+	assert.Regexp(t, `^From MAILER-DAEMON `, entry)
+	assert.Contains(t, entry, "\nnot a message\n\n")
+}
+
+func TestLoadExportProgress(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.mbox.progress")
+
+	p, err := loadExportProgress(path)
+	require.NoError(t, err)
+	assert.Empty(t, p.done)
+	assert.Equal(t, int64(-1), p.offset)
+
+	require.NoError(t, os.WriteFile(path, []byte("- 0\nm1 120\nm2 300\n\nm3 4"), 0644))
+	p, err = loadExportProgress(path)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"m1": true, "m2": true}, p.done)
+	assert.Equal(t, int64(300), p.offset)
+	assert.Equal(t, int64(len("- 0\nm1 120\nm2 300\n\n")), p.size)
+	assert.True(t, p.partial)
+
+	// Progress files without sizes mark messages done but do not truncate.
+	require.NoError(t, os.WriteFile(path, []byte("m1\nm2\n"), 0644))
+	p, err = loadExportProgress(path)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"m1": true, "m2": true}, p.done)
+	assert.Equal(t, int64(-1), p.offset)
+}
+
+func TestResumeMbox(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.mbox")
+	require.NoError(t, os.WriteFile(path, []byte("From a\n\nFrom b (partial"), 0644))
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	require.NoError(t, err)
+	defer f.Close()
+
+	size, err := resumeMbox(f, &exportProgress{offset: 8})
+	require.NoError(t, err)
+	assert.Equal(t, int64(8), size)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "From a\n\n", string(data))
+
+	size, err = resumeMbox(f, &exportProgress{offset: -1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(8), size)
+
+	_, err = resumeMbox(f, &exportProgress{offset: 100})
+	assert.Error(t, err)
+}
+
+func TestParseEML(t *testing.T) {
+	info, err := ParseEML([]byte("From: a@example.com\r\nTo: b@example.com\r\nSubject: Template\r\n\r\nBody"))
+	require.NoError(t, err)
+	assert.Equal(t, "Template", info.Subject)
+	assert.Equal(t, "b@example.com", info.To)
+
+	_, err = ParseEML([]byte("no headers here"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,99 @@
+package gmail
+
+import (
+	"fmt"
+	"sync"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+// DefaultFetchWorkers is the number of concurrent requests used when
+// fetching many messages.
+const DefaultFetchWorkers = 10
+
+// FetchError records a message that could not be fetched.
+type FetchError struct {
+	MessageID string `json:"message_id"`
+	Error     string `json:"error"`
+}
+
+// runPool calls fn for every index in [0, n) using at most workers
+// goroutines and returns the error of each call by index.
+func runPool(n, workers int, fn func(i int) error) []error {
+	errs := make([]error, n)
+	if n == 0 {
+		return errs
+	}
+	if workers <= 0 {
+		workers = DefaultFetchWorkers
+	}
+	if workers > n {
+		workers = n
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				errs[i] = fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return errs
+}
+
+// fetchMetadata fetches messages in metadata format with the given headers
+// concurrently. The result is in the order of ids; messages that could not
+// be fetched are nil and reported as FetchErrors.
+func (s *Service) fetchMetadata(ids []string, headers []string, workers int) ([]*gmail.Message, []FetchError) {
+	return s.fetchMessages(ids, workers, func(call *gmail.UsersMessagesGetCall) *gmail.UsersMessagesGetCall {
+		return call.Format("metadata").MetadataHeaders(headers...)
+	})
+}
+
+// structureFields selects a message's headers and MIME structure, without
+// part bodies, from a full-format get. Parts are followed four levels deep,
+// enough for invitations nested in multipart/mixed and multipart/alternative.
+const structureFields = "id,threadId,labelIds,snippet,internalDate," +
+	"payload(headers,mimeType,filename,parts(mimeType,filename,parts(mimeType,filename,parts(mimeType,filename))))"
+
+// fetchStructure fetches messages with all headers and their MIME part
+// types and filenames concurrently, like fetchMetadata.
+func (s *Service) fetchStructure(ids []string, workers int) ([]*gmail.Message, []FetchError) {
+	return s.fetchMessages(ids, workers, func(call *gmail.UsersMessagesGetCall) *gmail.UsersMessagesGetCall {
+		return call.Format("full").Fields(structureFields)
+	})
+}
+
+// fetchMessages gets messages concurrently with the call options applied
+// by configure.
+func (s *Service) fetchMessages(ids []string, workers int, configure func(*gmail.UsersMessagesGetCall) *gmail.UsersMessagesGetCall) ([]*gmail.Message, []FetchError) {
+	messages := make([]*gmail.Message, len(ids))
+
+	errs := runPool(len(ids), workers, func(i int) error {
+		msg, err := configure(s.svc.Users.Messages.Get("me", ids[i])).Do()
+		if err != nil {
+			return fmt.Errorf("failed to get message: %w", err)
+		}
+		messages[i] = msg
+		return nil
+	})
+
+	var failed []FetchError
+	for i, err := range errs {
+		if err != nil {
+			failed = append(failed, FetchError{MessageID: ids[i], Error: err.Error()})
+		}
+	}
+
+	return messages, failed
+}
@@ -0,0 +1,63 @@
+package gmail
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+)
+
+func TestListConcurrentPreservesOrder(t *testing.T) {
+	ids := []string{"m1", "m2", "m3", "m4", "m5", "m6"}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/gmail/v1/users/me/messages")
+		if path == "" {
+			var list gmail.ListMessagesResponse
+			for _, id := range ids {
+				list.Messages = append(list.Messages, &gmail.Message{Id: id})
+			}
+			json.NewEncoder(w).Encode(list)
+			return
+		}
+
+		id := strings.TrimPrefix(path, "/")
+		if id == "m4" {
+			http.Error(w, `{"error":{"code":404,"message":"not found"}}`, http.StatusNotFound)
+			return
+		}
+		// Earlier messages answer last.
+		time.Sleep(time.Duration(len(ids)-int(id[1]-'0')) * 5 * time.Millisecond)
+		json.NewEncoder(w).Encode(gmail.Message{
+			Id:      id,
+			Payload: &gmail.MessagePart{Headers: []*gmail.MessagePartHeader{{Name: "Subject", Value: "subject " + id}}},
+		})
+	}))
+	defer server.Close()
+
+	api, err := gmail.NewService(context.Background(),
+		option.WithEndpoint(server.URL), option.WithHTTPClient(server.Client()))
+	require.NoError(t, err)
+	svc := &Service{svc: api}
+
+	result, err := svc.List(ListOptions{MaxResults: 10, Workers: 4})
+	require.NoError(t, err)
+
+	var got []string
+	for _, m := range result.Messages {
+		got = append(got, m.ID)
+	}
+	assert.Equal(t, []string{"m1", "m2", "m3", "m5", "m6"}, got)
+	assert.Equal(t, "subject m1", result.Messages[0].Subject)
+
+	require.Len(t, result.Failed, 1)
+	assert.Equal(t, "m4", result.Failed[0].MessageID)
+}
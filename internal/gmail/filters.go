@@ -0,0 +1,547 @@
+package gmail
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+// FilterCriteria describes which messages a filter matches.
+type FilterCriteria struct {
+	From             string `json:"from,omitempty"`
+	To               string `json:"to,omitempty"`
+	Subject          string `json:"subject,omitempty"`
+	HasWords         string `json:"has_words,omitempty"`
+	DoesNotHaveWords string `json:"does_not_have_words,omitempty"`
+	HasAttachment    bool   `json:"has_attachment,omitempty"`
+	SizeGreaterThan  int64  `json:"size_gt,omitempty"`
+	SizeLessThan     int64  `json:"size_lt,omitempty"`
+}
+
+// FilterAction describes what a filter does with matching messages.
+// Labels are referred to by name.
+type FilterAction struct {
+	AddLabels      []string `json:"add_labels,omitempty"`
+	RemoveLabels   []string `json:"remove_labels,omitempty"`
+	Archive        bool     `json:"archive,omitempty"`
+	MarkRead       bool     `json:"mark_read,omitempty"`
+	Star           bool     `json:"star,omitempty"`
+	Trash          bool     `json:"trash,omitempty"`
+	NeverSpam      bool     `json:"never_spam,omitempty"`
+	Important      bool     `json:"important,omitempty"`
+	NeverImportant bool     `json:"never_important,omitempty"`
+	Forward        string   `json:"forward,omitempty"`
+}
+
+// FilterInfo represents a Gmail filter.
+type FilterInfo struct {
+	ID       string         `json:"id,omitempty"`
+	Criteria FilterCriteria `json:"criteria"`
+	Action   FilterAction   `json:"action"`
+}
+
+// FilterExport is the mailFilters.xml export of a mailbox's filters.
+// Filters the format cannot represent are listed in Skipped rather than
+// exported incompletely.
+type FilterExport struct {
+	XML     []byte             `json:"-"`
+	Count   int                `json:"count"`
+	Skipped []FilterExportSkip `json:"skipped,omitempty"`
+}
+
+// FilterExportSkip describes a filter left out of an export.
+type FilterExportSkip struct {
+	Filter FilterInfo `json:"filter"`
+	Reason string     `json:"reason"`
+}
+
+// FilterImportResult represents the result of importing filters.
+type FilterImportResult struct {
+	Created []FilterInfo        `json:"created"`
+	Failed  []FilterImportError `json:"failed,omitempty"`
+}
+
+// FilterImportError describes a filter that could not be created.
+type FilterImportError struct {
+	Index  int        `json:"index"`
+	Filter FilterInfo `json:"filter"`
+	Error  string     `json:"error"`
+}
+
+// Filters returns all filters with label IDs translated to names.
+func (s *Service) Filters() ([]FilterInfo, error) {
+	resp, err := s.svc.Users.Settings.Filters.List("me").Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list filters: %w", err)
+	}
+
+	labels, err := s.Labels()
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]string, len(labels))
+	for _, l := range labels {
+		names[l.ID] = l.Name
+	}
+
+	filters := make([]FilterInfo, 0, len(resp.Filter))
+	for _, f := range resp.Filter {
+		filters = append(filters, parseFilter(f, names))
+	}
+
+	return filters, nil
+}
+
+// CreateFilter creates a filter. Labels that do not exist yet are created.
+func (s *Service) CreateFilter(filter FilterInfo) (*FilterInfo, error) {
+	if filter.Criteria == (FilterCriteria{}) {
+		return nil, fmt.Errorf("filter needs at least one criterion")
+	}
+
+	labels, err := s.Labels()
+	if err != nil {
+		return nil, err
+	}
+
+	resolve := func(names []string, create bool) ([]string, error) {
+		ids := make([]string, 0, len(names))
+		for _, name := range names {
+			id, ok := matchLabel(labels, name)
+			if !ok {
+				if !create {
+					return nil, fmt.Errorf("label not found: %s", name)
+				}
+				created, err := s.CreateLabel(name)
+				if err != nil {
+					return nil, err
+				}
+				labels = append(labels, *created)
+				id = created.ID
+			}
+			ids = append(ids, id)
+		}
+		return ids, nil
+	}
+
+	addIDs, err := resolve(filter.Action.AddLabels, true)
+	if err != nil {
+		return nil, err
+	}
+	removeIDs, err := resolve(filter.Action.RemoveLabels, false)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.svc.Users.Settings.Filters.Create("me", buildFilter(filter, addIDs, removeIDs)).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create filter: %w", err)
+	}
+
+	result := filter
+	result.ID = created.Id
+	return &result, nil
+}
+
+// DeleteFilter deletes a filter by ID.
+func (s *Service) DeleteFilter(filterID string) error {
+	if err := s.svc.Users.Settings.Filters.Delete("me", filterID).Do(); err != nil {
+		return fmt.Errorf("failed to delete filter: %w", err)
+	}
+	return nil
+}
+
+// ExportFilters returns all filters in Gmail's mailFilters.xml format.
+func (s *Service) ExportFilters() (*FilterExport, error) {
+	filters, err := s.Filters()
+	if err != nil {
+		return nil, err
+	}
+
+	export := &FilterExport{}
+	var exportable []FilterInfo
+	for _, f := range filters {
+		if reason := filterXMLUnsupported(f); reason != "" {
+			export.Skipped = append(export.Skipped, FilterExportSkip{Filter: f, Reason: reason})
+			continue
+		}
+		exportable = append(exportable, f)
+	}
+
+	if export.XML, err = MarshalFilterXML(exportable); err != nil {
+		return nil, err
+	}
+	export.Count = len(exportable)
+	return export, nil
+}
+
+// filterXMLUnsupported returns why a filter cannot be represented in
+// mailFilters.xml, or "" if it can.
+func filterXMLUnsupported(f FilterInfo) string {
+	if len(f.Action.RemoveLabels) > 0 {
+		return fmt.Sprintf("mailFilters.xml cannot remove labels (%s)", strings.Join(f.Action.RemoveLabels, ", "))
+	}
+	return ""
+}
+
+// ImportFilters creates the filters from a mailFilters.xml document.
+// Filters that fail are reported individually; the rest are still created.
+func (s *Service) ImportFilters(data []byte) (*FilterImportResult, error) {
+	filters, err := ParseFilterXML(data)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &FilterImportResult{Created: []FilterInfo{}}
+
+	for i, f := range filters {
+		created, err := s.CreateFilter(f)
+		if err != nil {
+			result.Failed = append(result.Failed, FilterImportError{
+				Index:  i,
+				Filter: f,
+				Error:  err.Error(),
+			})
+			continue
+		}
+		result.Created = append(result.Created, *created)
+	}
+
+	return result, nil
+}
+
+// CreateLabel creates a user label with the given name.
+func (s *Service) CreateLabel(name string) (*LabelInfo, error) {
+	label, err := s.svc.Users.Labels.Create("me", &gmail.Label{
+		Name:                  name,
+		LabelListVisibility:   "labelShow",
+		MessageListVisibility: "show",
+	}).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create label: %w", err)
+	}
+
+	return &LabelInfo{
+		ID:   label.Id,
+		Name: label.Name,
+		Type: label.Type,
+	}, nil
+}
+
+// parseFilter converts a Gmail filter to a FilterInfo, mapping system label
+// changes to their named actions.
+func parseFilter(f *gmail.Filter, labelNames map[string]string) FilterInfo {
+	info := FilterInfo{ID: f.Id}
+
+	if c := f.Criteria; c != nil {
+		info.Criteria = FilterCriteria{
+			From:             c.From,
+			To:               c.To,
+			Subject:          c.Subject,
+			HasWords:         c.Query,
+			DoesNotHaveWords: c.NegatedQuery,
+			HasAttachment:    c.HasAttachment,
+		}
+		switch c.SizeComparison {
+		case "larger":
+			info.Criteria.SizeGreaterThan = c.Size
+		case "smaller":
+			info.Criteria.SizeLessThan = c.Size
+		}
+	}
+
+	if a := f.Action; a != nil {
+		info.Action.Forward = a.Forward
+		for _, id := range a.AddLabelIds {
+			switch id {
+			case "STARRED":
+				info.Action.Star = true
+			case "TRASH":
+				info.Action.Trash = true
+			case "IMPORTANT":
+				info.Action.Important = true
+			default:
+				info.Action.AddLabels = append(info.Action.AddLabels, labelName(labelNames, id))
+			}
+		}
+		for _, id := range a.RemoveLabelIds {
+			switch id {
+			case "INBOX":
+				info.Action.Archive = true
+			case "UNREAD":
+				info.Action.MarkRead = true
+			case "SPAM":
+				info.Action.NeverSpam = true
+			case "IMPORTANT":
+				info.Action.NeverImportant = true
+			default:
+				info.Action.RemoveLabels = append(info.Action.RemoveLabels, labelName(labelNames, id))
+			}
+		}
+	}
+
+	return info
+}
+
+// buildFilter converts a FilterInfo to a Gmail filter using resolved label IDs.
+func buildFilter(f FilterInfo, addIDs, removeIDs []string) *gmail.Filter {
+	criteria := &gmail.FilterCriteria{
+		From:          f.Criteria.From,
+		To:            f.Criteria.To,
+		Subject:       f.Criteria.Subject,
+		Query:         f.Criteria.HasWords,
+		NegatedQuery:  f.Criteria.DoesNotHaveWords,
+		HasAttachment: f.Criteria.HasAttachment,
+	}
+	if f.Criteria.SizeGreaterThan > 0 {
+		criteria.Size = f.Criteria.SizeGreaterThan
+		criteria.SizeComparison = "larger"
+	} else if f.Criteria.SizeLessThan > 0 {
+		criteria.Size = f.Criteria.SizeLessThan
+		criteria.SizeComparison = "smaller"
+	}
+
+	action := &gmail.FilterAction{
+		AddLabelIds:    addIDs,
+		RemoveLabelIds: removeIDs,
+		Forward:        f.Action.Forward,
+	}
+	if f.Action.Star {
+		action.AddLabelIds = append(action.AddLabelIds, "STARRED")
+	}
+	if f.Action.Trash {
+		action.AddLabelIds = append(action.AddLabelIds, "TRASH")
+	}
+	if f.Action.Archive {
+		action.RemoveLabelIds = append(action.RemoveLabelIds, "INBOX")
+	}
+	if f.Action.MarkRead {
+		action.RemoveLabelIds = append(action.RemoveLabelIds, "UNREAD")
+	}
+	if f.Action.NeverSpam {
+		action.RemoveLabelIds = append(action.RemoveLabelIds, "SPAM")
+	}
+	if f.Action.Important {
+		action.AddLabelIds = append(action.AddLabelIds, "IMPORTANT")
+	}
+	if f.Action.NeverImportant {
+		action.RemoveLabelIds = append(action.RemoveLabelIds, "IMPORTANT")
+	}
+
+	return &gmail.Filter{Criteria: criteria, Action: action}
+}
+
+func labelName(names map[string]string, id string) string {
+	if name, ok := names[id]; ok {
+		return name
+	}
+	return id
+}
+
+// ParseSize parses a size such as "512", "100KB" or "5MB" into bytes.
+func ParseSize(s string) (int64, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	multiplier := int64(1)
+	for _, unit := range []struct {
+		suffix string
+		value  int64
+	}{
+		{"MB", 1024 * 1024},
+		{"KB", 1024},
+		{"M", 1024 * 1024},
+		{"K", 1024},
+		{"B", 1},
+	} {
+		if strings.HasSuffix(s, unit.suffix) {
+			multiplier = unit.value
+			s = strings.TrimSpace(strings.TrimSuffix(s, unit.suffix))
+			break
+		}
+	}
+
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size: %q (use e.g. 500KB or 5MB)", s)
+	}
+	return n * multiplier, nil
+}
+
+// mailFilters.xml is an Atom feed with one entry per filter whose settings
+// are stored as apps:property name/value pairs.
+const (
+	atomNamespace = "http://www.w3.org/2005/Atom"
+	appsNamespace = "http://schemas.google.com/apps/2006"
+)
+
+type filterFeedOut struct {
+	XMLName xml.Name         `xml:"feed"`
+	Xmlns   string           `xml:"xmlns,attr"`
+	Apps    string           `xml:"xmlns:apps,attr"`
+	Title   string           `xml:"title"`
+	Entries []filterEntryOut `xml:"entry"`
+}
+
+type filterEntryOut struct {
+	Category   filterCategory   `xml:"category"`
+	Title      string           `xml:"title"`
+	Content    string           `xml:"content"`
+	Properties []filterProperty `xml:"apps:property"`
+}
+
+type filterFeedIn struct {
+	Entries []struct {
+		Properties []filterProperty `xml:"property"`
+	} `xml:"entry"`
+}
+
+type filterCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type filterProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// MarshalFilterXML encodes filters in Gmail's mailFilters.xml format.
+func MarshalFilterXML(filters []FilterInfo) ([]byte, error) {
+	feed := filterFeedOut{
+		Xmlns: atomNamespace,
+		Apps:  appsNamespace,
+		Title: "Mail Filters",
+	}
+
+	for _, f := range filters {
+		var props []filterProperty
+		add := func(name, value string) {
+			if value != "" {
+				props = append(props, filterProperty{Name: name, Value: value})
+			}
+		}
+		flag := func(name string, set bool) {
+			if set {
+				add(name, "true")
+			}
+		}
+
+		add("from", f.Criteria.From)
+		add("to", f.Criteria.To)
+		add("subject", f.Criteria.Subject)
+		add("hasTheWord", f.Criteria.HasWords)
+		add("doesNotHaveTheWord", f.Criteria.DoesNotHaveWords)
+		flag("hasAttachment", f.Criteria.HasAttachment)
+		if f.Criteria.SizeGreaterThan > 0 {
+			add("size", strconv.FormatInt(f.Criteria.SizeGreaterThan, 10))
+			add("sizeOperator", "s_sl")
+			add("sizeUnit", "s_sb")
+		} else if f.Criteria.SizeLessThan > 0 {
+			add("size", strconv.FormatInt(f.Criteria.SizeLessThan, 10))
+			add("sizeOperator", "s_ss")
+			add("sizeUnit", "s_sb")
+		}
+		for _, label := range f.Action.AddLabels {
+			add("label", label)
+		}
+		flag("shouldArchive", f.Action.Archive)
+		flag("shouldMarkAsRead", f.Action.MarkRead)
+		flag("shouldStar", f.Action.Star)
+		flag("shouldTrash", f.Action.Trash)
+		flag("shouldNeverSpam", f.Action.NeverSpam)
+		flag("shouldAlwaysMarkAsImportant", f.Action.Important)
+		flag("shouldNeverMarkAsImportant", f.Action.NeverImportant)
+		add("forwardTo", f.Action.Forward)
+
+		feed.Entries = append(feed.Entries, filterEntryOut{
+			Category:   filterCategory{Term: "filter"},
+			Title:      "Mail Filter",
+			Properties: props,
+		})
+	}
+
+	data, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode filters: %w", err)
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+// ParseFilterXML decodes filters from Gmail's mailFilters.xml format.
+func ParseFilterXML(data []byte) ([]FilterInfo, error) {
+	var feed filterFeedIn
+	if err := xml.Unmarshal(data, &feed); err != nil {
+		return nil, fmt.Errorf("failed to parse filters XML: %w", err)
+	}
+
+	filters := make([]FilterInfo, 0, len(feed.Entries))
+	for i, entry := range feed.Entries {
+		var f FilterInfo
+		var size int64
+		var sizeOperator, sizeUnit string
+
+		for _, p := range entry.Properties {
+			isTrue := p.Value == "true"
+			switch p.Name {
+			case "from":
+				f.Criteria.From = p.Value
+			case "to":
+				f.Criteria.To = p.Value
+			case "subject":
+				f.Criteria.Subject = p.Value
+			case "hasTheWord":
+				f.Criteria.HasWords = p.Value
+			case "doesNotHaveTheWord":
+				f.Criteria.DoesNotHaveWords = p.Value
+			case "hasAttachment":
+				f.Criteria.HasAttachment = isTrue
+			case "size":
+				n, err := strconv.ParseInt(p.Value, 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("filter %d: invalid size %q", i, p.Value)
+				}
+				size = n
+			case "sizeOperator":
+				sizeOperator = p.Value
+			case "sizeUnit":
+				sizeUnit = p.Value
+			case "label":
+				f.Action.AddLabels = append(f.Action.AddLabels, p.Value)
+			case "shouldArchive":
+				f.Action.Archive = isTrue
+			case "shouldMarkAsRead":
+				f.Action.MarkRead = isTrue
+			case "shouldStar":
+				f.Action.Star = isTrue
+			case "shouldTrash":
+				f.Action.Trash = isTrue
+			case "shouldNeverSpam":
+				f.Action.NeverSpam = isTrue
+			case "shouldAlwaysMarkAsImportant":
+				f.Action.Important = isTrue
+			case "shouldNeverMarkAsImportant":
+				f.Action.NeverImportant = isTrue
+			case "forwardTo":
+				f.Action.Forward = p.Value
+			}
+		}
+
+		if size > 0 {
+			switch sizeUnit {
+			case "s_skb":
+				size *= 1024
+			case "s_smb":
+				size *= 1024 * 1024
+			}
+			if sizeOperator == "s_ss" {
+				f.Criteria.SizeLessThan = size
+			} else {
+				f.Criteria.SizeGreaterThan = size
+			}
+		}
+
+		filters = append(filters, f)
+	}
+
+	return filters, nil
+}
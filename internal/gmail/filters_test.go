@@ -0,0 +1,127 @@
+package gmail
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/gmail/v1"
+)
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+		wantErr  bool
+	}{
+		{"512", 512, false},
+		{"100KB", 100 * 1024, false},
+		{"5MB", 5 * 1024 * 1024, false},
+		{"5m", 5 * 1024 * 1024, false},
+		{"10 kb", 10 * 1024, false},
+		{"abc", 0, true},
+		{"-1", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			size, err := ParseSize(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, size)
+		})
+	}
+}
+
+func TestFilterXMLRoundTrip(t *testing.T) {
+	filters := []FilterInfo{
+		{
+			Criteria: FilterCriteria{From: "news@example.com", HasWords: "unsubscribe"},
+			Action:   FilterAction{AddLabels: []string{"Newsletters"}, Archive: true, MarkRead: true},
+		},
+		{
+			Criteria: FilterCriteria{Subject: "invoice", SizeGreaterThan: 1024},
+			Action:   FilterAction{Forward: "billing@example.com", Star: true},
+		},
+		{
+			Criteria: FilterCriteria{From: "alerts@example.com"},
+			Action:   FilterAction{NeverImportant: true},
+		},
+	}
+
+	data, err := MarshalFilterXML(filters)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "<apps:property name=\"from\" value=\"news@example.com\"></apps:property>")
+	assert.Contains(t, string(data), "xmlns:apps=\"http://schemas.google.com/apps/2006\"")
+	assert.Contains(t, string(data), "<apps:property name=\"shouldNeverMarkAsImportant\" value=\"true\"></apps:property>")
+
+	parsed, err := ParseFilterXML(data)
+	require.NoError(t, err)
+	assert.Equal(t, filters, parsed)
+}
+
+func TestParseFilterXML_GmailExport(t *testing.T) {
+	data := []byte(`<?xml version='1.0' encoding='UTF-8'?>
+<feed xmlns='http://www.w3.org/2005/Atom' xmlns:apps='http://schemas.google.com/apps/2006'>
+	<title>Mail Filters</title>
+	<entry>
+		<category term='filter'></category>
+		<title>Mail Filter</title>
+		<content></content>
+		<apps:property name='from' value='deals@shop.example'/>
+		<apps:property name='size' value='2'/>
+		<apps:property name='sizeOperator' value='s_ss'/>
+		<apps:property name='sizeUnit' value='s_smb'/>
+		<apps:property name='shouldTrash' value='true'/>
+	</entry>
+</feed>`)
+
+	filters, err := ParseFilterXML(data)
+	require.NoError(t, err)
+	require.Len(t, filters, 1)
+	assert.Equal(t, "deals@shop.example", filters[0].Criteria.From)
+	assert.Equal(t, int64(2*1024*1024), filters[0].Criteria.SizeLessThan)
+	assert.True(t, filters[0].Action.Trash)
+}
+
+func TestParseFilter_SystemLabels(t *testing.T) {
+	f := &gmail.Filter{
+		Id:       "f1",
+		Criteria: &gmail.FilterCriteria{From: "a@example.com", Size: 100, SizeComparison: "larger"},
+		Action: &gmail.FilterAction{
+			AddLabelIds:    []string{"Label_1", "STARRED"},
+			RemoveLabelIds: []string{"INBOX", "UNREAD"},
+		},
+	}
+
+	info := parseFilter(f, map[string]string{"Label_1": "Receipts"})
+	assert.Equal(t, "f1", info.ID)
+	assert.Equal(t, int64(100), info.Criteria.SizeGreaterThan)
+	assert.Equal(t, []string{"Receipts"}, info.Action.AddLabels)
+	assert.True(t, info.Action.Star)
+	assert.True(t, info.Action.Archive)
+	assert.True(t, info.Action.MarkRead)
+
+	built := buildFilter(info, []string{"Label_1"}, nil)
+	assert.ElementsMatch(t, []string{"Label_1", "STARRED"}, built.Action.AddLabelIds)
+	assert.ElementsMatch(t, []string{"INBOX", "UNREAD"}, built.Action.RemoveLabelIds)
+	assert.Equal(t, "larger", built.Criteria.SizeComparison)
+}
+
+func TestParseFilter_Important(t *testing.T) {
+	info := parseFilter(&gmail.Filter{
+		Criteria: &gmail.FilterCriteria{From: "alerts@example.com"},
+		Action:   &gmail.FilterAction{RemoveLabelIds: []string{"IMPORTANT", "Label_1"}},
+	}, map[string]string{"Label_1": "Work"})
+
+	assert.True(t, info.Action.NeverImportant)
+	assert.Equal(t, []string{"Work"}, info.Action.RemoveLabels)
+	assert.Contains(t, filterXMLUnsupported(info), "Work")
+
+	info.Action.RemoveLabels = nil
+	assert.Empty(t, filterXMLUnsupported(info))
+	assert.Equal(t, []string{"IMPORTANT"}, buildFilter(info, nil, nil).Action.RemoveLabelIds)
+}
@@ -0,0 +1,312 @@
+package gmail
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/googleapi"
+)
+
+// DefaultFullSyncLimit is the number of recent messages returned when the
+// history cursor is missing or has expired.
+const DefaultFullSyncLimit = 500
+
+// MessageChange represents a message that was added to or deleted from
+// the mailbox.
+type MessageChange struct {
+	ID       string   `json:"id"`
+	ThreadID string   `json:"thread_id,omitempty"`
+	LabelIDs []string `json:"label_ids,omitempty"`
+}
+
+// LabelChange represents label changes applied to a single message.
+type LabelChange struct {
+	ID            string   `json:"id"`
+	ThreadID      string   `json:"thread_id,omitempty"`
+	AddedLabels   []string `json:"added_labels,omitempty"`
+	RemovedLabels []string `json:"removed_labels,omitempty"`
+}
+
+// ChangesResult represents the mailbox changes since a history ID.
+type ChangesResult struct {
+	StartHistoryID uint64          `json:"start_history_id,omitempty"`
+	HistoryID      uint64          `json:"history_id"`
+	Added          []MessageChange `json:"added"`
+	Deleted        []MessageChange `json:"deleted"`
+	LabelChanged   []LabelChange   `json:"label_changed"`
+	FullSync       bool            `json:"full_sync"`
+	FullSyncReason string          `json:"full_sync_reason,omitempty"`
+	MessageIDs     []string        `json:"message_ids,omitempty"`
+	Truncated      bool            `json:"truncated,omitempty"`
+}
+
+// HistoryState is the cursor persisted between runs of a change feed.
+type HistoryState struct {
+	HistoryID uint64 `json:"history_id"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// Changes returns the messages added, deleted and relabelled since
+// startHistoryID. If startHistoryID is zero or has expired, it falls back
+// to listing the fullSyncLimit most recent messages and returns the current
+// history ID as the new cursor.
+func (s *Service) Changes(startHistoryID uint64, fullSyncLimit int) (*ChangesResult, error) {
+	if startHistoryID == 0 {
+		return s.fullSync(fullSyncLimit, "no history ID provided")
+	}
+
+	var records []*gmail.History
+	var latest uint64
+	pageToken := ""
+
+	for {
+		call := s.svc.Users.History.List("me").StartHistoryId(startHistoryID).
+			HistoryTypes("messageAdded", "messageDeleted", "labelAdded", "labelRemoved").
+			MaxResults(listPageSize)
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+
+		resp, err := call.Do()
+		if err != nil {
+			if isHistoryExpired(err) {
+				return s.fullSync(fullSyncLimit, "history ID expired")
+			}
+			return nil, fmt.Errorf("failed to list history: %w", err)
+		}
+
+		records = append(records, resp.History...)
+		if resp.HistoryId > latest {
+			latest = resp.HistoryId
+		}
+
+		if resp.NextPageToken == "" {
+			break
+		}
+		pageToken = resp.NextPageToken
+	}
+
+	result := collectHistory(records)
+	result.StartHistoryID = startHistoryID
+	result.HistoryID = latest
+	if result.HistoryID == 0 {
+		result.HistoryID = startHistoryID
+	}
+
+	return result, nil
+}
+
+// fullSync lists the most recent messages and returns the current history
+// ID. The profile is read first so no change between the two calls is lost.
+func (s *Service) fullSync(limit int, reason string) (*ChangesResult, error) {
+	if limit <= 0 {
+		limit = DefaultFullSyncLimit
+	}
+
+	profile, err := s.svc.Users.GetProfile("me").Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profile: %w", err)
+	}
+
+	ids, truncated, err := s.ListIDs("", limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ChangesResult{
+		HistoryID:      profile.HistoryId,
+		Added:          []MessageChange{},
+		Deleted:        []MessageChange{},
+		LabelChanged:   []LabelChange{},
+		FullSync:       true,
+		FullSyncReason: reason,
+		MessageIDs:     ids,
+		Truncated:      truncated,
+	}, nil
+}
+
+// collectHistory folds history records into per-message changes. Messages
+// deleted within the window are removed from the added and label-changed
+// lists, and repeated label changes on the same message are merged into
+// the net change: a label added and removed again within the window is not
+// reported, and messages left without label changes are dropped.
+func collectHistory(records []*gmail.History) *ChangesResult {
+	result := &ChangesResult{
+		Added:        []MessageChange{},
+		Deleted:      []MessageChange{},
+		LabelChanged: []LabelChange{},
+	}
+
+	deleted := make(map[string]bool)
+	for _, h := range records {
+		for _, d := range h.MessagesDeleted {
+			if d.Message != nil && !deleted[d.Message.Id] {
+				deleted[d.Message.Id] = true
+				result.Deleted = append(result.Deleted, MessageChange{
+					ID:       d.Message.Id,
+					ThreadID: d.Message.ThreadId,
+				})
+			}
+		}
+	}
+
+	added := make(map[string]bool)
+	labelIndex := make(map[string]int)
+	labelChange := func(msg *gmail.Message) *LabelChange {
+		if i, ok := labelIndex[msg.Id]; ok {
+			return &result.LabelChanged[i]
+		}
+		labelIndex[msg.Id] = len(result.LabelChanged)
+		result.LabelChanged = append(result.LabelChanged, LabelChange{
+			ID:       msg.Id,
+			ThreadID: msg.ThreadId,
+		})
+		return &result.LabelChanged[len(result.LabelChanged)-1]
+	}
+
+	for _, h := range records {
+		for _, a := range h.MessagesAdded {
+			if a.Message == nil || deleted[a.Message.Id] || added[a.Message.Id] {
+				continue
+			}
+			added[a.Message.Id] = true
+			result.Added = append(result.Added, MessageChange{
+				ID:       a.Message.Id,
+				ThreadID: a.Message.ThreadId,
+				LabelIDs: a.Message.LabelIds,
+			})
+		}
+		for _, l := range h.LabelsAdded {
+			if l.Message == nil || deleted[l.Message.Id] || added[l.Message.Id] {
+				continue
+			}
+			c := labelChange(l.Message)
+			c.AddedLabels, c.RemovedLabels = netLabels(c.AddedLabels, c.RemovedLabels, l.LabelIds)
+		}
+		for _, l := range h.LabelsRemoved {
+			if l.Message == nil || deleted[l.Message.Id] || added[l.Message.Id] {
+				continue
+			}
+			c := labelChange(l.Message)
+			c.RemovedLabels, c.AddedLabels = netLabels(c.RemovedLabels, c.AddedLabels, l.LabelIds)
+		}
+	}
+
+	changed := result.LabelChanged[:0]
+	for _, c := range result.LabelChanged {
+		if len(c.AddedLabels) > 0 || len(c.RemovedLabels) > 0 {
+			changed = append(changed, c)
+		}
+	}
+	result.LabelChanged = changed
+
+	return result
+}
+
+// netLabels applies a change of labels to the net lists of a message:
+// labels pending in the opposite list cancel out, the rest are merged into
+// the same list.
+func netLabels(same, opposite, labels []string) ([]string, []string) {
+	var fresh []string
+	for _, label := range labels {
+		if containsLabel(opposite, label) {
+			opposite = subtractLabels(opposite, []string{label})
+		} else {
+			fresh = append(fresh, label)
+		}
+	}
+	return mergeLabels(same, fresh), opposite
+}
+
+func containsLabel(labels []string, label string) bool {
+	for _, l := range labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
+func mergeLabels(existing, labels []string) []string {
+	for _, label := range labels {
+		found := false
+		for _, e := range existing {
+			if e == label {
+				found = true
+				break
+			}
+		}
+		if !found {
+			existing = append(existing, label)
+		}
+	}
+	return existing
+}
+
+func subtractLabels(existing, labels []string) []string {
+	result := existing[:0]
+	for _, e := range existing {
+		keep := true
+		for _, label := range labels {
+			if e == label {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// isHistoryExpired reports whether err indicates an expired or invalid
+// start history ID.
+func isHistoryExpired(err error) bool {
+	var apiErr *googleapi.Error
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound
+}
+
+// LoadHistoryState reads a history cursor from path. A missing file returns
+// a zero state without error.
+func LoadHistoryState(path string) (*HistoryState, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &HistoryState{}, nil
+		}
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	var state HistoryState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse state file: %w", err)
+	}
+	return &state, nil
+}
+
+// SaveHistoryState writes a history cursor to path.
+func SaveHistoryState(path string, historyID uint64) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(HistoryState{
+		HistoryID: historyID,
+		UpdatedAt: time.Now().UTC().Format(time.RFC3339),
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package gmail
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/gmail/v1"
+)
+
+func TestCollectHistory(t *testing.T) {
+	records := []*gmail.History{
+		{
+			Id: 101,
+			MessagesAdded: []*gmail.HistoryMessageAdded{
+				{Message: &gmail.Message{Id: "m1", ThreadId: "t1", LabelIds: []string{"INBOX", "UNREAD"}}},
+				{Message: &gmail.Message{Id: "m2", ThreadId: "t2"}},
+			},
+		},
+		{
+			Id: 102,
+			LabelsRemoved: []*gmail.HistoryLabelRemoved{
+				{Message: &gmail.Message{Id: "m3", ThreadId: "t3"}, LabelIds: []string{"UNREAD"}},
+			},
+			LabelsAdded: []*gmail.HistoryLabelAdded{
+				{Message: &gmail.Message{Id: "m3", ThreadId: "t3"}, LabelIds: []string{"STARRED"}},
+				{Message: &gmail.Message{Id: "m1", ThreadId: "t1"}, LabelIds: []string{"STARRED"}},
+			},
+		},
+		{
+			Id: 103,
+			MessagesDeleted: []*gmail.HistoryMessageDeleted{
+				{Message: &gmail.Message{Id: "m2", ThreadId: "t2"}},
+				{Message: &gmail.Message{Id: "m4", ThreadId: "t4"}},
+			},
+			LabelsAdded: []*gmail.HistoryLabelAdded{
+				{Message: &gmail.Message{Id: "m3", ThreadId: "t3"}, LabelIds: []string{"UNREAD"}},
+			},
+		},
+	}
+
+	result := collectHistory(records)
+
+	require.Len(t, result.Added, 1)
+	assert.Equal(t, "m1", result.Added[0].ID)
+	assert.Equal(t, []string{"INBOX", "UNREAD"}, result.Added[0].LabelIDs)
+
+	require.Len(t, result.Deleted, 2)
+	assert.Equal(t, "m2", result.Deleted[0].ID)
+	assert.Equal(t, "m4", result.Deleted[1].ID)
+
+	// UNREAD was removed and added again, so only STARRED changed.
+	require.Len(t, result.LabelChanged, 1)
+	assert.Equal(t, "m3", result.LabelChanged[0].ID)
+	assert.Equal(t, []string{"STARRED"}, result.LabelChanged[0].AddedLabels)
+	assert.Empty(t, result.LabelChanged[0].RemovedLabels)
+}
+
+func TestCollectHistory_NetLabelChanges(t *testing.T) {
+	msg := &gmail.Message{Id: "m1", ThreadId: "t1"}
+	records := []*gmail.History{
+		{Id: 1, LabelsAdded: []*gmail.HistoryLabelAdded{{Message: msg, LabelIds: []string{"STARRED", "IMPORTANT"}}}},
+		{Id: 2, LabelsRemoved: []*gmail.HistoryLabelRemoved{{Message: msg, LabelIds: []string{"STARRED"}}}},
+		{Id: 3, LabelsRemoved: []*gmail.HistoryLabelRemoved{{Message: &gmail.Message{Id: "m2"}, LabelIds: []string{"INBOX"}}}},
+		{Id: 4, LabelsAdded: []*gmail.HistoryLabelAdded{{Message: &gmail.Message{Id: "m2"}, LabelIds: []string{"INBOX"}}}},
+	}
+
+	result := collectHistory(records)
+	require.Len(t, result.LabelChanged, 1)
+	assert.Equal(t, "m1", result.LabelChanged[0].ID)
+	assert.Equal(t, []string{"IMPORTANT"}, result.LabelChanged[0].AddedLabels)
+	assert.Empty(t, result.LabelChanged[0].RemovedLabels)
+}
+
+func TestHistoryStateRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "gmail.json")
+
+	state, err := LoadHistoryState(path)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), state.HistoryID)
+
+	require.NoError(t, SaveHistoryState(path, 4242))
+
+	state, err = LoadHistoryState(path)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4242), state.HistoryID)
+	assert.NotEmpty(t, state.UpdatedAt)
+}
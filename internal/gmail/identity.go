@@ -0,0 +1,233 @@
+package gmail
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+)
+
+// SendAsInfo represents a send-as alias of the mailbox.
+type SendAsInfo struct {
+	Email              string `json:"email"`
+	DisplayName        string `json:"display_name,omitempty"`
+	ReplyTo            string `json:"reply_to,omitempty"`
+	Signature          string `json:"signature,omitempty"`
+	IsPrimary          bool   `json:"is_primary,omitempty"`
+	IsDefault          bool   `json:"is_default,omitempty"`
+	VerificationStatus string `json:"verification_status,omitempty"`
+}
+
+// Identity describes the addresses that belong to the authenticated user.
+type Identity struct {
+	Email   string       `json:"email"`
+	Aliases []SendAsInfo `json:"aliases"`
+}
+
+// Identity returns the mailbox's primary address and its send-as aliases.
+// The result is cached for the lifetime of the Service.
+func (s *Service) Identity() (*Identity, error) {
+	if s.identity != nil {
+		return s.identity, nil
+	}
+
+	profile, err := s.svc.Users.GetProfile("me").Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profile: %w", err)
+	}
+
+	aliases, err := s.SendAs()
+	if err != nil {
+		return nil, err
+	}
+
+	s.identity = &Identity{
+		Email:   profile.EmailAddress,
+		Aliases: aliases,
+	}
+	return s.identity, nil
+}
+
+// SendAs returns the mailbox's send-as aliases.
+func (s *Service) SendAs() ([]SendAsInfo, error) {
+	resp, err := s.svc.Users.Settings.SendAs.List("me").Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list send-as aliases: %w", err)
+	}
+
+	aliases := make([]SendAsInfo, 0, len(resp.SendAs))
+	for _, a := range resp.SendAs {
+		aliases = append(aliases, SendAsInfo{
+			Email:              a.SendAsEmail,
+			DisplayName:        a.DisplayName,
+			ReplyTo:            a.ReplyToAddress,
+			Signature:          a.Signature,
+			IsPrimary:          a.IsPrimary,
+			IsDefault:          a.IsDefault,
+			VerificationStatus: a.VerificationStatus,
+		})
+	}
+
+	return aliases, nil
+}
+
+// Owns reports whether addr is the primary address or one of the aliases.
+func (id *Identity) Owns(addr string) bool {
+	addr = normalizeAddress(addr)
+	if addr == "" {
+		return false
+	}
+	if strings.EqualFold(addr, id.Email) {
+		return true
+	}
+	for _, a := range id.Aliases {
+		if strings.EqualFold(addr, a.Email) {
+			return true
+		}
+	}
+	return false
+}
+
+// Alias returns the alias with the given address.
+func (id *Identity) Alias(addr string) (*SendAsInfo, bool) {
+	addr = normalizeAddress(addr)
+	for i := range id.Aliases {
+		if strings.EqualFold(addr, id.Aliases[i].Email) {
+			return &id.Aliases[i], true
+		}
+	}
+	return nil, false
+}
+
+// FromHeader formats the alias as a From header value.
+func (a *SendAsInfo) FromHeader() string {
+	return (&mail.Address{Name: a.DisplayName, Address: a.Email}).String()
+}
+
+// normalizeAddress returns the lower-cased bare address of a header value
+// such as "Jane Doe <jane@example.com>".
+func normalizeAddress(s string) string {
+	s = strings.TrimSpace(s)
+	if addr, err := mail.ParseAddress(s); err == nil {
+		return strings.ToLower(addr.Address)
+	}
+	return strings.ToLower(strings.Trim(s, "<>"))
+}
+
+// parseAddressHeader splits an address header into individual addresses,
+// honouring quoted display names that contain commas.
+func parseAddressHeader(header string) []string {
+	if strings.TrimSpace(header) == "" {
+		return nil
+	}
+
+	if list, err := mail.ParseAddressList(header); err == nil {
+		result := make([]string, 0, len(list))
+		for _, a := range list {
+			result = append(result, a.String())
+		}
+		return result
+	}
+
+	var result []string
+	for _, p := range strings.Split(header, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// replyRecipients computes the To and Cc lists for a reply. The sender is
+// replied to unless the original was sent by the user, in which case the
+// original recipients are. For reply-all, the remaining To and Cc
+// recipients are added to Cc. The user's own addresses are dropped and
+// recipients are de-duplicated by address.
+func replyRecipients(headers map[string]string, id *Identity, replyAll bool) (to, cc []string) {
+	seen := make(map[string]bool)
+	add := func(list []string, addrs []string) []string {
+		for _, a := range addrs {
+			key := normalizeAddress(a)
+			if key == "" || seen[key] || id.Owns(a) {
+				continue
+			}
+			seen[key] = true
+			list = append(list, a)
+		}
+		return list
+	}
+
+	sender := parseAddressHeader(headerValue(headers, "Reply-To"))
+	if len(sender) == 0 {
+		sender = parseAddressHeader(headerValue(headers, "From"))
+	}
+	origTo := parseAddressHeader(headerValue(headers, "To"))
+	origCc := parseAddressHeader(headerValue(headers, "Cc"))
+
+	fromSelf := len(sender) > 0 && id.Owns(sender[0])
+	if fromSelf {
+		to = add(to, origTo)
+	} else {
+		to = add(to, sender)
+	}
+
+	if replyAll {
+		if !fromSelf {
+			cc = add(cc, origTo)
+		}
+		cc = add(cc, origCc)
+	}
+
+	// A reply to one's own message sent only to oneself still needs a
+	// recipient.
+	if len(to) == 0 && len(sender) > 0 {
+		to = sender[:1]
+	}
+
+	return to, cc
+}
+
+// replyAlias picks the alias a reply should be sent from: the explicitly
+// requested one, otherwise the alias the original message was addressed
+// to, otherwise none (Gmail uses the default address).
+func replyAlias(headers map[string]string, id *Identity, requested string) (*SendAsInfo, error) {
+	if requested != "" {
+		alias, ok := id.Alias(requested)
+		if !ok {
+			return nil, fmt.Errorf("not a send-as alias of this account: %s", requested)
+		}
+		return alias, nil
+	}
+
+	for _, key := range []string{"Delivered-To", "To", "Cc"} {
+		for _, addr := range parseAddressHeader(headerValue(headers, key)) {
+			if alias, ok := id.Alias(addr); ok && !alias.IsDefault {
+				return alias, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// headerValue looks up a header by case-insensitive name, since senders
+// disagree on the casing of names such as Cc and Message-ID.
+func headerValue(headers map[string]string, name string) string {
+	if v, ok := headers[name]; ok {
+		return v
+	}
+	for k, v := range headers {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return ""
+}
+
+// signatureText converts an HTML alias signature to a plain-text block.
+func signatureText(signature string) string {
+	text := stripHTML(signature)
+	if text == "" {
+		return ""
+	}
+	return "\n\n-- \n" + text
+}
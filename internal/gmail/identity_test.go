@@ -0,0 +1,99 @@
+package gmail
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testIdentity() *Identity {
+	return &Identity{
+		Email: "me@example.com",
+		Aliases: []SendAsInfo{
+			{Email: "me@example.com", IsPrimary: true, IsDefault: true},
+			{Email: "support@example.com", DisplayName: "Support", Signature: "<b>Support</b><br>Example Inc."},
+		},
+	}
+}
+
+func TestReplyRecipients(t *testing.T) {
+	id := testIdentity()
+
+	tests := []struct {
+		name     string
+		headers  map[string]string
+		replyAll bool
+		to, cc   []string
+	}{
+		{
+			name:    "reply to sender",
+			headers: map[string]string{"From": "Alice <alice@example.com>", "To": "me@example.com"},
+			to:      []string{`"Alice" <alice@example.com>`},
+		},
+		{
+			name: "reply-all drops own addresses and duplicates",
+			headers: map[string]string{
+				"From": "alice@example.com",
+				"To":   "Me <ME@example.com>, bob@example.com, \"Smith, Carol\" <carol@example.com>",
+				"CC":   "support@example.com, Bob <bob@example.com>, alice@example.com",
+			},
+			replyAll: true,
+			to:       []string{"<alice@example.com>"},
+			cc:       []string{"<bob@example.com>", `"Smith, Carol" <carol@example.com>`},
+		},
+		{
+			name:     "address containing me is kept",
+			headers:  map[string]string{"From": "alice@example.com", "To": "me@example.com, james@example.com"},
+			replyAll: true,
+			to:       []string{"<alice@example.com>"},
+			cc:       []string{"<james@example.com>"},
+		},
+		{
+			name:    "reply-to wins",
+			headers: map[string]string{"From": "alice@example.com", "Reply-To": "list@example.com"},
+			to:      []string{"<list@example.com>"},
+		},
+		{
+			name:     "own message replies to original recipients",
+			headers:  map[string]string{"From": "support@example.com", "To": "bob@example.com", "Cc": "carol@example.com"},
+			replyAll: true,
+			to:       []string{"<bob@example.com>"},
+			cc:       []string{"<carol@example.com>"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			to, cc := replyRecipients(tt.headers, id, tt.replyAll)
+			assert.Equal(t, tt.to, to)
+			assert.Equal(t, tt.cc, cc)
+		})
+	}
+}
+
+func TestReplyAlias(t *testing.T) {
+	id := testIdentity()
+
+	alias, err := replyAlias(map[string]string{"To": "Support <support@example.com>"}, id, "")
+	require.NoError(t, err)
+	require.NotNil(t, alias)
+	assert.Equal(t, "support@example.com", alias.Email)
+	assert.Equal(t, `"Support" <support@example.com>`, alias.FromHeader())
+
+	alias, err = replyAlias(map[string]string{"To": "me@example.com"}, id, "")
+	require.NoError(t, err)
+	assert.Nil(t, alias)
+
+	alias, err = replyAlias(nil, id, "SUPPORT@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "support@example.com", alias.Email)
+
+	_, err = replyAlias(nil, id, "someone@else.com")
+	assert.Error(t, err)
+}
+
+func TestSignatureText(t *testing.T) {
+	assert.Equal(t, "\n\n-- \nSupport\nExample Inc.", signatureText("<b>Support</b><br>Example Inc."))
+	assert.Equal(t, "", signatureText(""))
+}
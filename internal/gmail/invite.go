@@ -0,0 +1,227 @@
+package gmail
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ulfhaga/gagent-cli/internal/ical"
+	"google.golang.org/api/gmail/v1"
+)
+
+// InviteAttendee is an attendee of a calendar invitation. ResponseStatus
+// uses the Calendar API values (needsAction, accepted, declined,
+// tentative).
+type InviteAttendee struct {
+	Email          string `json:"email"`
+	DisplayName    string `json:"display_name,omitempty"`
+	Role           string `json:"role,omitempty"`
+	ResponseStatus string `json:"response_status"`
+	Self           bool   `json:"self,omitempty"`
+}
+
+// InviteInfo is a calendar invitation parsed from a text/calendar part or
+// .ics attachment.
+type InviteInfo struct {
+	Method     string           `json:"method,omitempty"` // REQUEST, CANCEL, REPLY, ...
+	UID        string           `json:"uid"`
+	Summary    string           `json:"summary"`
+	Location   string           `json:"location,omitempty"`
+	Start      string           `json:"start"`
+	End        string           `json:"end,omitempty"`
+	TimeZone   string           `json:"time_zone,omitempty"`
+	AllDay     bool             `json:"all_day,omitempty"`
+	Status     string           `json:"status,omitempty"`
+	Sequence   int              `json:"sequence,omitempty"`
+	Recurrence []string         `json:"recurrence,omitempty"`
+	Organizer  string           `json:"organizer,omitempty"`
+	Attendees  []InviteAttendee `json:"attendees,omitempty"`
+	MyStatus   string           `json:"my_status,omitempty"`
+}
+
+// partstatValues maps iCalendar PARTSTAT values to Calendar API response
+// statuses.
+var partstatValues = map[string]string{
+	"NEEDS-ACTION": "needsAction",
+	"ACCEPTED":     "accepted",
+	"DECLINED":     "declined",
+	"TENTATIVE":    "tentative",
+}
+
+// ParseInvite parses the first VEVENT of iCalendar data.
+func ParseInvite(data []byte) (*InviteInfo, error) {
+	cal, err := ical.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse invite: %w", err)
+	}
+
+	events := cal.Find("VEVENT")
+	if len(events) == 0 {
+		return nil, fmt.Errorf("failed to parse invite: no VEVENT found")
+	}
+	ev := events[0]
+
+	invite := &InviteInfo{
+		Method:   strings.ToUpper(cal.Text("METHOD")),
+		UID:      ev.Text("UID"),
+		Summary:  ev.Text("SUMMARY"),
+		Location: ev.Text("LOCATION"),
+		Status:   strings.ToUpper(ev.Text("STATUS")),
+	}
+	invite.Sequence, _ = strconv.Atoi(ev.Text("SEQUENCE"))
+
+	for _, name := range []string{"RRULE", "EXDATE", "RDATE"} {
+		for _, p := range ev.Props(name) {
+			invite.Recurrence = append(invite.Recurrence, name+":"+p.Value)
+		}
+	}
+
+	if p := ev.Prop("DTSTART"); p != nil {
+		start, allDay, err := cal.Time(p, time.UTC)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse invite start: %w", err)
+		}
+		invite.Start = formatInviteTime(start, allDay)
+		invite.AllDay = allDay
+		if !allDay {
+			invite.TimeZone = start.Location().String()
+		}
+	}
+	if p := ev.Prop("DTEND"); p != nil {
+		end, allDay, err := cal.Time(p, time.UTC)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse invite end: %w", err)
+		}
+		invite.End = formatInviteTime(end, allDay)
+	}
+
+	if p := ev.Prop("ORGANIZER"); p != nil {
+		invite.Organizer = ical.CalAddress(p.Value)
+	}
+
+	for _, p := range ev.Props("ATTENDEE") {
+		status := partstatValues[strings.ToUpper(p.Params["PARTSTAT"])]
+		if status == "" {
+			status = "needsAction"
+		}
+		invite.Attendees = append(invite.Attendees, InviteAttendee{
+			Email:          ical.CalAddress(p.Value),
+			DisplayName:    p.Params["CN"],
+			Role:           p.Params["ROLE"],
+			ResponseStatus: status,
+		})
+	}
+
+	return invite, nil
+}
+
+// markSelf flags the user's own attendee entry and records its status.
+func (i *InviteInfo) markSelf(id *Identity) {
+	for k := range i.Attendees {
+		if id.Owns(i.Attendees[k].Email) {
+			i.Attendees[k].Self = true
+			i.MyStatus = i.Attendees[k].ResponseStatus
+		}
+	}
+}
+
+func formatInviteTime(t time.Time, allDay bool) string {
+	if allDay {
+		return t.Format("2006-01-02")
+	}
+	return t.Format(time.RFC3339)
+}
+
+// hasCalendarPart reports whether a payload, which need not include part
+// bodies, has a part that invitePart would pick: a text/calendar part or
+// an .ics attachment.
+func hasCalendarPart(part *gmail.MessagePart) bool {
+	if part == nil {
+		return false
+	}
+	if strings.EqualFold(part.MimeType, "text/calendar") || strings.HasSuffix(strings.ToLower(part.Filename), ".ics") {
+		return true
+	}
+	for _, p := range part.Parts {
+		if hasCalendarPart(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// invitePart returns the part of a payload carrying the invitation: a
+// text/calendar part, otherwise an .ics attachment.
+func invitePart(payload *gmail.MessagePart) *gmail.MessagePart {
+	var calendarPart, icsPart *gmail.MessagePart
+
+	var walk func(part *gmail.MessagePart)
+	walk = func(part *gmail.MessagePart) {
+		if part == nil || part.Body == nil && len(part.Parts) == 0 {
+			return
+		}
+		hasData := part.Body != nil && (part.Body.Data != "" || part.Body.AttachmentId != "")
+		if hasData {
+			if calendarPart == nil && strings.EqualFold(part.MimeType, "text/calendar") {
+				calendarPart = part
+			}
+			if icsPart == nil && strings.HasSuffix(strings.ToLower(part.Filename), ".ics") {
+				icsPart = part
+			}
+		}
+		for _, p := range part.Parts {
+			walk(p)
+		}
+	}
+	walk(payload)
+
+	if calendarPart != nil {
+		return calendarPart
+	}
+	return icsPart
+}
+
+// messageInvite parses the invitation of a message, if it has one. The
+// user's own attendee entry is resolved against the mailbox identity.
+func (s *Service) messageInvite(msg *gmail.Message) (*InviteInfo, error) {
+	part := invitePart(msg.Payload)
+	if part == nil {
+		return nil, nil
+	}
+
+	data, err := s.partData(msg.Id, part)
+	if err != nil {
+		return nil, err
+	}
+
+	invite, err := ParseInvite(data)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := s.Identity()
+	if err != nil {
+		return nil, err
+	}
+	invite.markSelf(id)
+
+	return invite, nil
+}
+
+// Invite returns the calendar invitation contained in a message.
+func (s *Service) Invite(messageID string) (*InviteInfo, error) {
+	msg, err := s.svc.Users.Messages.Get("me", messageID).Format("full").Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get message: %w", err)
+	}
+
+	invite, err := s.messageInvite(msg)
+	if err != nil {
+		return nil, err
+	}
+	if invite == nil {
+		return nil, fmt.Errorf("message %s contains no calendar invitation", messageID)
+	}
+	return invite, nil
+}
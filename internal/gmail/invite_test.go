@@ -0,0 +1,83 @@
+package gmail
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/gmail/v1"
+)
+
+const testInvite = "BEGIN:VCALENDAR\r\n" +
+	"METHOD:REQUEST\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:evt42@google.com\r\n" +
+	"SEQUENCE:1\r\n" +
+	"SUMMARY:Design review\r\n" +
+	"LOCATION:Room 4\r\n" +
+	"DTSTART;TZID=Europe/Berlin:20261020T153000\r\n" +
+	"DTEND;TZID=Europe/Berlin:20261020T163000\r\n" +
+	"RRULE:FREQ=WEEKLY;BYDAY=TU\r\n" +
+	"ORGANIZER;CN=Alice:mailto:alice@example.com\r\n" +
+	"ATTENDEE;CN=Alice;PARTSTAT=ACCEPTED;ROLE=CHAIR:mailto:alice@example.com\r\n" +
+	"ATTENDEE;PARTSTAT=NEEDS-ACTION;RSVP=TRUE:mailto:support@example.com\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParseInvite(t *testing.T) {
+	invite, err := ParseInvite([]byte(testInvite))
+	require.NoError(t, err)
+
+	assert.Equal(t, "REQUEST", invite.Method)
+	assert.Equal(t, "evt42@google.com", invite.UID)
+	assert.Equal(t, "Design review", invite.Summary)
+	assert.Equal(t, "2026-10-20T15:30:00+02:00", invite.Start)
+	assert.Equal(t, "2026-10-20T16:30:00+02:00", invite.End)
+	assert.Equal(t, "Europe/Berlin", invite.TimeZone)
+	assert.Equal(t, 1, invite.Sequence)
+	assert.Equal(t, []string{"RRULE:FREQ=WEEKLY;BYDAY=TU"}, invite.Recurrence)
+	assert.Equal(t, "alice@example.com", invite.Organizer)
+	require.Len(t, invite.Attendees, 2)
+	assert.Equal(t, "accepted", invite.Attendees[0].ResponseStatus)
+
+	invite.markSelf(testIdentity())
+	assert.True(t, invite.Attendees[1].Self)
+	assert.Equal(t, "needsAction", invite.MyStatus)
+
+	_, err = ParseInvite([]byte("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"))
+	assert.Error(t, err)
+}
+
+func TestParseInvite_Zones(t *testing.T) {
+	outlook := strings.ReplaceAll(testInvite, "TZID=Europe/Berlin", `TZID="W. Europe Standard Time"`)
+	invite, err := ParseInvite([]byte(outlook))
+	require.NoError(t, err)
+	assert.Equal(t, "2026-10-20T15:30:00+02:00", invite.Start)
+	assert.Equal(t, "Europe/Berlin", invite.TimeZone)
+
+	unknown := strings.ReplaceAll(testInvite, "TZID=Europe/Berlin", "TZID=Nowhere")
+	_, err = ParseInvite([]byte(unknown))
+	assert.ErrorContains(t, err, "unknown time zone")
+}
+
+func TestInvitePart(t *testing.T) {
+	data := base64.URLEncoding.EncodeToString([]byte(testInvite))
+	ics := &gmail.MessagePart{Filename: "invite.ics", MimeType: "application/ics", Body: &gmail.MessagePartBody{AttachmentId: "att1"}}
+	calendarPart := &gmail.MessagePart{MimeType: "text/calendar", Body: &gmail.MessagePartBody{Data: data}}
+
+	payload := &gmail.MessagePart{MimeType: "multipart/mixed", Parts: []*gmail.MessagePart{
+		{MimeType: "multipart/alternative", Parts: []*gmail.MessagePart{
+			{MimeType: "text/plain", Body: &gmail.MessagePartBody{Data: "aGk="}},
+			calendarPart,
+		}},
+		ics,
+	}}
+	assert.Same(t, calendarPart, invitePart(payload))
+
+	payload.Parts = payload.Parts[1:]
+	assert.Same(t, ics, invitePart(payload))
+
+	assert.Nil(t, invitePart(&gmail.MessagePart{MimeType: "text/plain", Body: &gmail.MessagePartBody{Data: "aGk="}}))
+}
@@ -0,0 +1,448 @@
+package gmail
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+// Merge statuses written to the status column.
+const (
+	MergeStatusSent    = "sent"
+	MergeStatusDrafted = "drafted"
+	MergeStatusError   = "error"
+)
+
+// MergeSource identifies where mail merge rows come from: a sheet of a
+// Google Spreadsheet or a local CSV file.
+type MergeSource struct {
+	SpreadsheetID string `json:"spreadsheet_id,omitempty"`
+	Sheet         string `json:"sheet,omitempty"`
+	File          string `json:"file,omitempty"`
+}
+
+// ParseMergeSource parses "sheets:<id>/<sheet>" or a CSV file path.
+func ParseMergeSource(s string) (MergeSource, error) {
+	if rest, ok := strings.CutPrefix(s, "sheets:"); ok {
+		id, sheet, _ := strings.Cut(rest, "/")
+		if id == "" {
+			return MergeSource{}, fmt.Errorf("missing spreadsheet ID in %q (use sheets:<id>/<sheet>)", s)
+		}
+		return MergeSource{SpreadsheetID: id, Sheet: sheet}, nil
+	}
+	if s == "" {
+		return MergeSource{}, fmt.Errorf("data source is required")
+	}
+	return MergeSource{File: s}, nil
+}
+
+// MergeData holds the rows of a mail merge. The first row of the source is
+// the header; each following row is keyed by header name.
+type MergeData struct {
+	Headers []string
+	Rows    []map[string]string
+}
+
+// NewMergeData builds merge data from records whose first row is the header.
+func NewMergeData(records [][]string) (*MergeData, error) {
+	if len(records) == 0 {
+		return nil, fmt.Errorf("data has no header row")
+	}
+
+	data := &MergeData{}
+	for _, h := range records[0] {
+		data.Headers = append(data.Headers, strings.TrimSpace(h))
+	}
+
+	for _, rec := range records[1:] {
+		row := make(map[string]string, len(data.Headers))
+		for i, h := range data.Headers {
+			if h == "" {
+				continue
+			}
+			if i < len(rec) {
+				row[h] = strings.TrimSpace(rec[i])
+			} else {
+				row[h] = ""
+			}
+		}
+		data.Rows = append(data.Rows, row)
+	}
+
+	return data, nil
+}
+
+// ReadMergeCSV reads merge data from CSV.
+func ReadMergeCSV(r io.Reader) (*MergeData, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV: %w", err)
+	}
+	return NewMergeData(records)
+}
+
+// Column returns the index of the header matching name case-insensitively,
+// or -1.
+func (d *MergeData) Column(name string) int {
+	for i, h := range d.Headers {
+		if strings.EqualFold(h, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// field returns the value of a column matched case-insensitively.
+func (d *MergeData) field(row map[string]string, name string) string {
+	if i := d.Column(name); i >= 0 {
+		return row[d.Headers[i]]
+	}
+	return ""
+}
+
+// MergeTemplate is a parsed mail merge template. The file starts with
+// optional header lines (Subject, Cc, Bcc) followed by a blank line and a
+// Markdown body. Every part may use text/template placeholders such as
+// {{.FirstName}}.
+type MergeTemplate struct {
+	tmpl *template.Template
+}
+
+var mergeTemplateParts = []string{"subject", "cc", "bcc", "body"}
+
+// ParseMergeTemplate parses a template. A non-empty subject overrides the
+// Subject header of the template.
+func ParseMergeTemplate(src, subject string) (*MergeTemplate, error) {
+	parts := map[string]string{}
+
+	lines := strings.SplitAfter(src, "\n")
+	n := 0
+	for ; n < len(lines); n++ {
+		key, value, ok := strings.Cut(lines[n], ":")
+		key = strings.ToLower(strings.TrimSpace(key))
+		if !ok || (key != "subject" && key != "cc" && key != "bcc") {
+			break
+		}
+		parts[key] = strings.TrimSpace(value)
+	}
+	body := strings.Join(lines[n:], "")
+	if n > 0 {
+		body = strings.TrimLeft(body, "\r\n")
+	}
+	parts["body"] = body
+
+	if subject != "" {
+		parts["subject"] = subject
+	}
+	if parts["subject"] == "" {
+		return nil, fmt.Errorf("template has no subject (add a \"Subject:\" line or use --subject)")
+	}
+
+	root := template.New("merge").Option("missingkey=error")
+	for _, name := range mergeTemplateParts {
+		if _, err := root.New(name).Parse(parts[name]); err != nil {
+			return nil, fmt.Errorf("failed to parse template %s: %w", name, err)
+		}
+	}
+
+	return &MergeTemplate{tmpl: root}, nil
+}
+
+func (t *MergeTemplate) execute(name string, row map[string]string) (string, error) {
+	var buf bytes.Buffer
+	if err := t.tmpl.ExecuteTemplate(&buf, name, row); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// MergeMessage is the message rendered for one data row.
+type MergeMessage struct {
+	Row         int      `json:"row"` // Row number in the source, counting the header as row 1
+	To          []string `json:"to"`
+	Cc          []string `json:"cc,omitempty"`
+	Bcc         []string `json:"bcc,omitempty"`
+	Subject     string   `json:"subject"`
+	Body        string   `json:"body,omitempty"`
+	Attachments []string `json:"attachments,omitempty"`
+	Skipped     bool     `json:"skipped,omitempty"`
+	Error       string   `json:"error,omitempty"`
+}
+
+// Render renders one message per row. The to, cc, bcc and attachments
+// columns (case-insensitive, separated by ";" or ",") are added to the
+// rendered message. Attachments are paths relative to attachmentsDir; rows
+// naming attachments fail when attachmentsDir is empty. Rows whose status
+// column already records a successful send or draft are marked skipped so
+// an interrupted merge can be re-run. Rows that fail to render carry an
+// Error instead.
+func (t *MergeTemplate) Render(data *MergeData, attachmentsDir string) []MergeMessage {
+	messages := make([]MergeMessage, 0, len(data.Rows))
+
+	for i, row := range data.Rows {
+		m := MergeMessage{Row: i + 2}
+
+		status := strings.ToLower(data.field(row, "status"))
+		if strings.HasPrefix(status, MergeStatusSent) || strings.HasPrefix(status, MergeStatusDrafted) {
+			m.To = splitList(data.field(row, "to"))
+			m.Skipped = true
+			messages = append(messages, m)
+			continue
+		}
+
+		if err := t.renderRow(data, row, attachmentsDir, &m); err != nil {
+			m.Error = err.Error()
+		}
+		messages = append(messages, m)
+	}
+
+	return messages
+}
+
+func (t *MergeTemplate) renderRow(data *MergeData, row map[string]string, attachmentsDir string, m *MergeMessage) error {
+	rendered := map[string]string{}
+	for _, name := range mergeTemplateParts {
+		out, err := t.execute(name, row)
+		if err != nil {
+			return err
+		}
+		rendered[name] = out
+	}
+
+	m.Subject = strings.TrimSpace(rendered["subject"])
+	m.Body = rendered["body"]
+	var err error
+	if m.Attachments, err = resolveAttachments(attachmentsDir, splitList(data.field(row, "attachments"))); err != nil {
+		return err
+	}
+	if m.To, err = parseAddresses("to", splitList(data.field(row, "to"))); err != nil {
+		return err
+	}
+	if m.Cc, err = parseAddresses("cc", append(splitList(data.field(row, "cc")), splitList(rendered["cc"])...)); err != nil {
+		return err
+	}
+	if m.Bcc, err = parseAddresses("bcc", append(splitList(data.field(row, "bcc")), splitList(rendered["bcc"])...)); err != nil {
+		return err
+	}
+
+	if len(m.To) == 0 {
+		return fmt.Errorf("no recipient in the to column")
+	}
+
+	return nil
+}
+
+// resolveAttachments resolves attachment paths from the data relative to
+// dir. Since the data decides which files are read, absolute paths, ".."
+// and symlinks leading outside dir are rejected.
+func resolveAttachments(dir string, paths []string) ([]string, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+	if dir == "" {
+		return nil, fmt.Errorf("the attachments column requires an attachments directory")
+	}
+
+	base, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid attachments directory: %w", err)
+	}
+	base, err = filepath.Abs(base)
+	if err != nil {
+		return nil, fmt.Errorf("invalid attachments directory: %w", err)
+	}
+
+	var result []string
+	for _, p := range paths {
+		if filepath.IsAbs(p) || !filepath.IsLocal(p) {
+			return nil, fmt.Errorf("attachment %s must be a relative path inside the attachments directory", p)
+		}
+		resolved, err := filepath.EvalSymlinks(filepath.Join(base, p))
+		if err != nil {
+			return nil, fmt.Errorf("attachment not found: %s", p)
+		}
+		if rel, err := filepath.Rel(base, resolved); err != nil || !filepath.IsLocal(rel) {
+			return nil, fmt.Errorf("attachment %s resolves outside the attachments directory", p)
+		}
+		if info, err := os.Stat(resolved); err != nil || !info.Mode().IsRegular() {
+			return nil, fmt.Errorf("attachment %s is not a regular file", p)
+		}
+		result = append(result, resolved)
+	}
+	return result, nil
+}
+
+// splitList splits a ";" or "," separated list.
+func splitList(s string) []string {
+	var result []string
+	for _, p := range strings.FieldsFunc(s, func(r rune) bool { return r == ';' || r == ',' }) {
+		if p = strings.TrimSpace(p); p != "" {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// parseAddresses parses each address of a to, cc or bcc list and returns
+// them in canonical form, so that no raw cell text reaches a header.
+func parseAddresses(field string, list []string) ([]string, error) {
+	var result []string
+	for _, s := range list {
+		addr, err := mail.ParseAddress(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s address %q: %w", field, s, err)
+		}
+		if addr.Name == "" {
+			result = append(result, addr.Address)
+		} else {
+			result = append(result, addr.String())
+		}
+	}
+	return result, nil
+}
+
+// SendMerge sends a rendered merge message, or creates a draft of it when
+// asDraft is set. It returns the ID of the sent message or created draft.
+func (s *Service) SendMerge(m MergeMessage, asDraft bool) (string, error) {
+	raw, err := buildMergeMessage(m)
+	if err != nil {
+		return "", err
+	}
+
+	msg := &gmail.Message{
+		Raw: base64.URLEncoding.EncodeToString(raw),
+	}
+
+	if asDraft {
+		draft, err := s.svc.Users.Drafts.Create("me", &gmail.Draft{Message: msg}).Do()
+		if err != nil {
+			return "", fmt.Errorf("failed to create draft: %w", err)
+		}
+		return draft.Id, nil
+	}
+
+	sent, err := s.svc.Users.Messages.Send("me", msg).Do()
+	if err != nil {
+		return "", fmt.Errorf("failed to send message: %w", err)
+	}
+	return sent.Id, nil
+}
+
+// buildMergeMessage builds a MIME message with a plain-text (Markdown) and
+// HTML alternative and any attachments.
+func buildMergeMessage(m MergeMessage) ([]byte, error) {
+	html, err := MarkdownToHTML(m.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	for _, h := range []struct {
+		name string
+		list []string
+	}{{"To", m.To}, {"Cc", m.Cc}, {"Bcc", m.Bcc}} {
+		addrs, err := parseAddresses(strings.ToLower(h.name), h.list)
+		if err != nil {
+			return nil, err
+		}
+		if len(addrs) > 0 {
+			buf.WriteString(h.name + ": " + strings.Join(addrs, ", ") + "\r\n")
+		}
+	}
+	buf.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", m.Subject) + "\r\n")
+	buf.WriteString("MIME-Version: 1.0\r\n")
+
+	alternative := func(w *multipart.Writer) error {
+		for _, part := range []struct{ contentType, content string }{
+			{"text/plain; charset=\"UTF-8\"", m.Body},
+			{"text/html; charset=\"UTF-8\"", html},
+		} {
+			pw, err := w.CreatePart(textproto.MIMEHeader{"Content-Type": {part.contentType}})
+			if err != nil {
+				return err
+			}
+			if _, err := io.WriteString(pw, part.content); err != nil {
+				return err
+			}
+		}
+		return w.Close()
+	}
+
+	if len(m.Attachments) == 0 {
+		w := multipart.NewWriter(&buf)
+		buf.WriteString("Content-Type: multipart/alternative; boundary=" + w.Boundary() + "\r\n\r\n")
+		if err := alternative(w); err != nil {
+			return nil, fmt.Errorf("failed to build message: %w", err)
+		}
+		return buf.Bytes(), nil
+	}
+
+	mixed := multipart.NewWriter(&buf)
+	buf.WriteString("Content-Type: multipart/mixed; boundary=" + mixed.Boundary() + "\r\n\r\n")
+
+	var alt bytes.Buffer
+	altWriter := multipart.NewWriter(&alt)
+	if err := alternative(altWriter); err != nil {
+		return nil, fmt.Errorf("failed to build message: %w", err)
+	}
+	pw, err := mixed.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {"multipart/alternative; boundary=" + altWriter.Boundary()},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build message: %w", err)
+	}
+	pw.Write(alt.Bytes())
+
+	for _, path := range m.Attachments {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read attachment: %w", err)
+		}
+
+		name := filepath.Base(path)
+		contentType := mime.TypeByExtension(filepath.Ext(name))
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+
+		pw, err := mixed.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {mime.FormatMediaType(contentType, map[string]string{"name": name})},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": name})},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to build message: %w", err)
+		}
+		writeBase64Lines(pw, data)
+	}
+
+	if err := mixed.Close(); err != nil {
+		return nil, fmt.Errorf("failed to build message: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// writeBase64Lines writes data base64-encoded in 76-character lines.
+func writeBase64Lines(w io.Writer, data []byte) {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 76 {
+		io.WriteString(w, encoded[:76]+"\r\n")
+		encoded = encoded[76:]
+	}
+	io.WriteString(w, encoded+"\r\n")
+}
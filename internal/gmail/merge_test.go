@@ -0,0 +1,141 @@
+package gmail
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMergeSource(t *testing.T) {
+	src, err := ParseMergeSource("sheets:abc123/Outreach")
+	require.NoError(t, err)
+	assert.Equal(t, MergeSource{SpreadsheetID: "abc123", Sheet: "Outreach"}, src)
+
+	src, err = ParseMergeSource("contacts.csv")
+	require.NoError(t, err)
+	assert.Equal(t, MergeSource{File: "contacts.csv"}, src)
+
+	_, err = ParseMergeSource("sheets:/Sheet1")
+	assert.Error(t, err)
+}
+
+func TestParseMergeTemplate(t *testing.T) {
+	tmpl, err := ParseMergeTemplate("Subject: Hi {{.FirstName}}\r\nCc: {{.Manager}}\r\n\r\nHello **{{.FirstName}}**,\n", "")
+	require.NoError(t, err)
+
+	data, err := ReadMergeCSV(strings.NewReader(
+		"FirstName,Manager,To,Attachments,Status\n" +
+			"Ada,boss@example.com,ada@example.com; ada2@example.com,,\n" +
+			"Bob,,bob@example.com,,sent 2026-10-18T10:00:00Z\n" +
+			"Cy,,,,\n"))
+	require.NoError(t, err)
+
+	messages := tmpl.Render(data, "")
+	require.Len(t, messages, 3)
+
+	assert.Equal(t, 2, messages[0].Row)
+	assert.Equal(t, "Hi Ada", messages[0].Subject)
+	assert.Equal(t, "Hello **Ada**,\n", messages[0].Body)
+	assert.Equal(t, []string{"ada@example.com", "ada2@example.com"}, messages[0].To)
+	assert.Equal(t, []string{"boss@example.com"}, messages[0].Cc)
+	assert.Empty(t, messages[0].Error)
+
+	assert.True(t, messages[1].Skipped)
+	assert.Equal(t, 3, messages[1].Row)
+
+	assert.Contains(t, messages[2].Error, "no recipient")
+}
+
+func TestRender_InvalidAddress(t *testing.T) {
+	tmpl, err := ParseMergeTemplate("Cc: {{.Manager}}\n\nHello", "Hi")
+	require.NoError(t, err)
+
+	data, err := NewMergeData([][]string{
+		{"to", "Manager"},
+		{"ada@example.com\r\nBcc: evil@example.com", ""},
+		{"Ada Lovelace <ada@example.com>", "boss@example.com\nBcc: evil@example.com"},
+		{"Ada Lovelace <ada@example.com>", ""},
+	})
+	require.NoError(t, err)
+
+	messages := tmpl.Render(data, "")
+	require.Len(t, messages, 3)
+	assert.Contains(t, messages[0].Error, "invalid to address")
+	assert.Contains(t, messages[1].Error, "invalid cc address")
+	assert.Empty(t, messages[2].Error)
+	assert.Equal(t, []string{`"Ada Lovelace" <ada@example.com>`}, messages[2].To)
+
+	_, err = buildMergeMessage(MergeMessage{To: []string{"ada@example.com\r\nBcc: evil@example.com"}, Subject: "Hi"})
+	assert.Error(t, err)
+}
+
+func TestParseMergeTemplate_Errors(t *testing.T) {
+	_, err := ParseMergeTemplate("Hello {{.Name}}", "")
+	assert.Error(t, err, "subject required")
+
+	tmpl, err := ParseMergeTemplate("Hello {{.Name}}", "Subject override")
+	require.NoError(t, err)
+
+	data, err := NewMergeData([][]string{{"to"}, {"a@example.com"}})
+	require.NoError(t, err)
+	messages := tmpl.Render(data, "")
+	assert.Contains(t, messages[0].Error, "Name")
+}
+
+func TestRender_Attachments(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "brochure.pdf"), []byte("%PDF-1.4"), 0644))
+	outside := filepath.Join(t.TempDir(), "id_rsa")
+	require.NoError(t, os.WriteFile(outside, []byte("secret"), 0600))
+	require.NoError(t, os.Symlink(outside, filepath.Join(dir, "link")))
+
+	tmpl, err := ParseMergeTemplate("Hello", "Hi")
+	require.NoError(t, err)
+	data, err := NewMergeData([][]string{
+		{"to", "attachments"},
+		{"a@example.com", "brochure.pdf"},
+		{"a@example.com", outside},
+		{"a@example.com", "../id_rsa"},
+		{"a@example.com", "link"},
+		{"a@example.com", "missing.pdf"},
+	})
+	require.NoError(t, err)
+
+	messages := tmpl.Render(data, dir)
+	require.Len(t, messages, 5)
+	assert.Empty(t, messages[0].Error)
+	require.Len(t, messages[0].Attachments, 1)
+	assert.Equal(t, "brochure.pdf", filepath.Base(messages[0].Attachments[0]))
+	assert.Contains(t, messages[1].Error, "relative path")
+	assert.Contains(t, messages[2].Error, "relative path")
+	assert.Contains(t, messages[3].Error, "outside")
+	assert.Contains(t, messages[4].Error, "not found")
+
+	messages = tmpl.Render(data, "")
+	assert.Contains(t, messages[0].Error, "attachments directory")
+}
+
+func TestBuildMergeMessage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "brochure.pdf")
+	require.NoError(t, os.WriteFile(path, []byte("%PDF-1.4"), 0644))
+
+	raw, err := buildMergeMessage(MergeMessage{
+		To:          []string{"ada@example.com"},
+		Subject:     "Hej Åsa",
+		Body:        "Hello **Ada**",
+		Attachments: []string{path},
+	})
+	require.NoError(t, err)
+
+	msg := string(raw)
+	assert.Contains(t, msg, "To: ada@example.com\r\n")
+	assert.Contains(t, msg, "Subject: =?utf-8?q?Hej_=C3=85sa?=\r\n")
+	assert.Contains(t, msg, "Content-Type: multipart/mixed; boundary=")
+	assert.Contains(t, msg, "<strong>Ada</strong>")
+	assert.Contains(t, msg, `filename=brochure.pdf`)
+	assert.Contains(t, msg, "JVBERi0xLjQ=")
+}
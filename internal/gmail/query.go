@@ -0,0 +1,203 @@
+package gmail
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// QueryOptions contains structured search criteria that compile to a Gmail
+// query string.
+type QueryOptions struct {
+	Raw           string   // Raw Gmail query, appended as-is
+	From          []string // Any of these senders
+	To            []string // Any of these recipients
+	Subject       string
+	After         string // RFC3339, YYYY-MM-DD or relative (7d, 12h, 2w, 3m, 1y)
+	Before        string // Same forms as After
+	HasAttachment bool
+	Labels        []string // Label names; all must match
+	Larger        string   // Size such as 5MB
+	Is            []string // unread, read, starred, important, ...
+	In            string   // inbox, sent, trash, spam, anywhere, ...
+}
+
+var validIs = map[string]bool{
+	"unread": true, "read": true, "starred": true, "important": true,
+	"snoozed": true, "muted": true,
+}
+
+var validIn = map[string]bool{
+	"inbox": true, "sent": true, "drafts": true, "trash": true, "spam": true,
+	"anywhere": true, "chats": true, "scheduled": true, "snoozed": true,
+}
+
+// IsEmpty reports whether no criteria are set.
+func (o QueryOptions) IsEmpty() bool {
+	return o.Raw == "" && len(o.From) == 0 && len(o.To) == 0 && o.Subject == "" &&
+		o.After == "" && o.Before == "" && !o.HasAttachment && len(o.Labels) == 0 &&
+		o.Larger == "" && len(o.Is) == 0 && o.In == ""
+}
+
+// BuildQuery compiles structured criteria into a Gmail query string.
+// Relative dates are resolved against now and all dates are emitted as
+// Unix timestamps so they do not depend on the mailbox time zone.
+func BuildQuery(opts QueryOptions, now time.Time) (string, error) {
+	var terms []string
+
+	for _, a := range []struct {
+		op     string
+		values []string
+	}{
+		{"from", opts.From},
+		{"to", opts.To},
+	} {
+		t, err := anyOf(a.op, a.values)
+		if err != nil {
+			return "", err
+		}
+		if t != "" {
+			terms = append(terms, t)
+		}
+	}
+	if opts.Subject != "" {
+		subject, err := quoteTerm("subject", opts.Subject)
+		if err != nil {
+			return "", err
+		}
+		terms = append(terms, "subject:"+subject)
+	}
+
+	for _, d := range []struct {
+		op, value string
+	}{
+		{"after", opts.After},
+		{"before", opts.Before},
+	} {
+		if d.value == "" {
+			continue
+		}
+		t, err := ParseQueryDate(d.value, now)
+		if err != nil {
+			return "", fmt.Errorf("invalid --%s: %w", d.op, err)
+		}
+		terms = append(terms, fmt.Sprintf("%s:%d", d.op, t.Unix()))
+	}
+
+	if opts.HasAttachment {
+		terms = append(terms, "has:attachment")
+	}
+	for _, label := range opts.Labels {
+		terms = append(terms, "label:"+labelTerm(label))
+	}
+	if opts.Larger != "" {
+		size, err := ParseSize(opts.Larger)
+		if err != nil {
+			return "", err
+		}
+		terms = append(terms, fmt.Sprintf("larger:%d", size))
+	}
+	for _, is := range opts.Is {
+		is = strings.ToLower(strings.TrimSpace(is))
+		if !validIs[is] {
+			return "", fmt.Errorf("invalid --is value: %s (use: unread, read, starred, important, snoozed, muted)", is)
+		}
+		terms = append(terms, "is:"+is)
+	}
+	if opts.In != "" {
+		in := strings.ToLower(strings.TrimSpace(opts.In))
+		if !validIn[in] {
+			return "", fmt.Errorf("invalid --in value: %s (use: inbox, sent, drafts, trash, spam, anywhere, ...)", in)
+		}
+		terms = append(terms, "in:"+in)
+	}
+
+	if raw := strings.TrimSpace(opts.Raw); raw != "" {
+		// Group the raw query so OR inside it cannot bind to the
+		// structured terms.
+		if len(terms) > 0 && strings.Contains(strings.ToUpper(raw), " OR ") {
+			raw = "(" + raw + ")"
+		}
+		terms = append(terms, raw)
+	}
+
+	return strings.Join(terms, " "), nil
+}
+
+// anyOf builds a term matching any of values, using Gmail's {a b} OR group.
+func anyOf(op string, values []string) (string, error) {
+	var parts []string
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			q, err := quoteTerm(op, v)
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, op+":"+q)
+		}
+	}
+	switch len(parts) {
+	case 0:
+		return "", nil
+	case 1:
+		return parts[0], nil
+	default:
+		return "{" + strings.Join(parts, " ") + "}", nil
+	}
+}
+
+// quoteTerm quotes a value that contains whitespace or query syntax.
+// Gmail queries cannot escape a double quote inside a quoted term, so
+// values containing one are rejected rather than silently changed.
+func quoteTerm(op, v string) (string, error) {
+	if strings.Contains(v, `"`) {
+		return "", fmt.Errorf("invalid --%s: double quotes are not supported in %q (use the raw query instead)", op, v)
+	}
+	if strings.ContainsAny(v, " \t(){}:-") {
+		return `"` + v + `"`, nil
+	}
+	return v, nil
+}
+
+// labelTerm converts a label name to Gmail's query form, where spaces and
+// nesting slashes become hyphens.
+func labelTerm(name string) string {
+	name = strings.TrimSpace(name)
+	return strings.NewReplacer(" ", "-", "/", "-").Replace(name)
+}
+
+// ParseQueryDate parses an absolute (RFC3339 or YYYY-MM-DD) or relative
+// (e.g. 7d) date.
+func ParseQueryDate(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02", "2006/01/02"} {
+		if t, err := time.ParseInLocation(layout, value, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+
+	if len(value) >= 2 {
+		n, err := strconv.Atoi(value[:len(value)-1])
+		if err == nil && n >= 0 {
+			switch value[len(value)-1] {
+			case 'h':
+				return now.Add(-time.Duration(n) * time.Hour), nil
+			case 'd':
+				return now.AddDate(0, 0, -n), nil
+			case 'w':
+				return now.AddDate(0, 0, -7*n), nil
+			case 'm':
+				return now.AddDate(0, -n, 0), nil
+			case 'y':
+				return now.AddDate(-n, 0, 0), nil
+			}
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unrecognized date %q (use RFC3339, YYYY-MM-DD or relative like 7d)", value)
+}
@@ -0,0 +1,101 @@
+package gmail
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildQuery(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		opts     QueryOptions
+		expected string
+	}{
+		{"raw only", QueryOptions{Raw: "is:unread"}, "is:unread"},
+		{"single sender", QueryOptions{From: []string{"boss@example.com"}}, "from:boss@example.com"},
+		{
+			"multiple senders grouped",
+			QueryOptions{From: []string{"a@example.com", "b@example.com"}, Is: []string{"unread"}},
+			"{from:a@example.com from:b@example.com} is:unread",
+		},
+		{"subject with spaces quoted", QueryOptions{Subject: "Q3 final report"}, `subject:"Q3 final report"`},
+		{
+			"relative and absolute dates",
+			QueryOptions{After: "7d", Before: "2026-10-18T00:00:00Z"},
+			"after:1791720000 before:1792281600",
+		},
+		{"label with spaces", QueryOptions{Labels: []string{"Clients/Big Co"}}, "label:Clients-Big-Co"},
+		{"size and attachment", QueryOptions{HasAttachment: true, Larger: "5MB"}, "has:attachment larger:5242880"},
+		{"in", QueryOptions{In: "Anywhere"}, "in:anywhere"},
+		{
+			"raw OR is grouped",
+			QueryOptions{To: []string{"me@example.com"}, Raw: "from:a OR from:b"},
+			"to:me@example.com (from:a OR from:b)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := BuildQuery(tt.opts, now)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, query)
+		})
+	}
+}
+
+func TestBuildQuery_Invalid(t *testing.T) {
+	now := time.Now()
+
+	_, err := BuildQuery(QueryOptions{Is: []string{"urgent"}}, now)
+	assert.Error(t, err)
+
+	_, err = BuildQuery(QueryOptions{In: "nowhere"}, now)
+	assert.Error(t, err)
+
+	_, err = BuildQuery(QueryOptions{After: "last tuesday"}, now)
+	assert.Error(t, err)
+
+	_, err = BuildQuery(QueryOptions{Larger: "big"}, now)
+	assert.Error(t, err)
+
+	_, err = BuildQuery(QueryOptions{Subject: `say "hi"`}, now)
+	assert.ErrorContains(t, err, "--subject")
+
+	_, err = BuildQuery(QueryOptions{From: []string{"a@example.com", `"Bob" <b@example.com>`}}, now)
+	assert.ErrorContains(t, err, "--from")
+}
+
+func TestParseQueryDate(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		input    string
+		expected time.Time
+	}{
+		{"12h", now.Add(-12 * time.Hour)},
+		{"2w", time.Date(2026, 10, 4, 12, 0, 0, 0, time.UTC)},
+		{"1m", time.Date(2026, 9, 18, 12, 0, 0, 0, time.UTC)},
+		{"1y", time.Date(2025, 10, 18, 12, 0, 0, 0, time.UTC)},
+		{"2026-01-02", time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{"2026/01/02", time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			parsed, err := ParseQueryDate(tt.input, now)
+			require.NoError(t, err)
+			assert.True(t, tt.expected.Equal(parsed), "got %s", parsed)
+		})
+	}
+}
+
+func TestQueryOptionsIsEmpty(t *testing.T) {
+	assert.True(t, QueryOptions{}.IsEmpty())
+	assert.False(t, QueryOptions{HasAttachment: true}.IsEmpty())
+	assert.False(t, QueryOptions{Raw: "x"}.IsEmpty())
+}
@@ -8,6 +8,9 @@ import (
 	"google.golang.org/api/gmail/v1"
 )
 
+// summaryHeaders are the headers fetched for message summaries.
+var summaryHeaders = []string{"From", "To", "Subject", "Date"}
+
 // ListOptions contains options for listing messages.
 type ListOptions struct {
 	LabelIDs   []string
@@ -15,16 +18,20 @@ type ListOptions struct {
 	MaxResults int64
 	PageToken  string
 	UnreadOnly bool
+	Workers    int // Concurrent summary fetches (default DefaultFetchWorkers)
 }
 
-// List returns a list of messages matching the criteria.
-func (s *Service) List(opts ListOptions) ([]MessageSummary, string, error) {
-	call := s.svc.Users.Messages.List("me")
-
-	if len(opts.LabelIDs) > 0 {
-		call = call.LabelIds(opts.LabelIDs...)
-	}
+// ListResult is a page of message summaries.
+type ListResult struct {
+	Messages      []MessageSummary
+	NextPageToken string
+	Failed        []FetchError // Messages whose summary could not be fetched
+}
 
+// List returns a list of messages matching the criteria. Summaries are
+// fetched concurrently and returned in list order; messages that fail to
+// load are reported in Failed.
+func (s *Service) List(opts ListOptions) (*ListResult, error) {
 	query := opts.Query
 	if opts.UnreadOnly {
 		if query != "" {
@@ -33,58 +40,72 @@ func (s *Service) List(opts ListOptions) ([]MessageSummary, string, error) {
 			query = "is:unread"
 		}
 	}
-	if query != "" {
-		call = call.Q(query)
-	}
-
-	if opts.MaxResults > 0 {
-		call = call.MaxResults(opts.MaxResults)
-	} else {
-		call = call.MaxResults(10) // Default
-	}
 
-	if opts.PageToken != "" {
-		call = call.PageToken(opts.PageToken)
+	limit := opts.MaxResults
+	if limit <= 0 {
+		limit = 10 // Default
 	}
 
-	resp, err := call.Do()
-	if err != nil {
-		return nil, "", fmt.Errorf("failed to list messages: %w", err)
-	}
+	// The API returns at most 500 IDs per page.
+	var ids []string
+	pageToken := opts.PageToken
+	for {
+		call := s.svc.Users.Messages.List("me").MaxResults(min(limit-int64(len(ids)), listPageSize))
+		if len(opts.LabelIDs) > 0 {
+			call = call.LabelIds(opts.LabelIDs...)
+		}
+		if query != "" {
+			call = call.Q(query)
+		}
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
 
-	summaries := make([]MessageSummary, 0, len(resp.Messages))
-	for _, msg := range resp.Messages {
-		// Get message metadata for each message
-		fullMsg, err := s.svc.Users.Messages.Get("me", msg.Id).Format("metadata").
-			MetadataHeaders("From", "To", "Subject", "Date").Do()
+		resp, err := call.Do()
 		if err != nil {
-			continue // Skip messages that fail to load
+			return nil, fmt.Errorf("failed to list messages: %w", err)
+		}
+
+		for _, msg := range resp.Messages {
+			ids = append(ids, msg.Id)
 		}
+		pageToken = resp.NextPageToken
+		if pageToken == "" || int64(len(ids)) >= limit {
+			break
+		}
+	}
+
+	messages, failed := s.fetchMetadata(ids, summaryHeaders, opts.Workers)
 
-		summary := parseMessageToSummary(fullMsg)
-		summaries = append(summaries, summary)
+	summaries := make([]MessageSummary, 0, len(messages))
+	for _, msg := range messages {
+		if msg != nil {
+			summaries = append(summaries, parseMessageToSummary(msg))
+		}
 	}
 
-	return summaries, resp.NextPageToken, nil
+	return &ListResult{
+		Messages:      summaries,
+		NextPageToken: pageToken,
+		Failed:        failed,
+	}, nil
 }
 
 // Inbox returns messages from the inbox.
-func (s *Service) Inbox(limit int64, unreadOnly bool) ([]MessageSummary, error) {
-	messages, _, err := s.List(ListOptions{
+func (s *Service) Inbox(limit int64, unreadOnly bool) (*ListResult, error) {
+	return s.List(ListOptions{
 		LabelIDs:   []string{"INBOX"},
 		MaxResults: limit,
 		UnreadOnly: unreadOnly,
 	})
-	return messages, err
 }
 
 // Search searches for messages matching the query.
-func (s *Service) Search(query string, limit int64) ([]MessageSummary, error) {
-	messages, _, err := s.List(ListOptions{
+func (s *Service) Search(query string, limit int64) (*ListResult, error) {
+	return s.List(ListOptions{
 		Query:      query,
 		MaxResults: limit,
 	})
-	return messages, err
 }
 
 // Get returns a full message by ID.
@@ -97,6 +118,25 @@ func (s *Service) Get(messageID string) (*MessageFull, error) {
 	return parseMessageToFull(msg), nil
 }
 
+// Read returns a full message like Get, and also parses its calendar
+// invitation if it has one. A malformed invitation is reported in
+// InviteError instead of failing the read.
+func (s *Service) Read(messageID string) (*MessageFull, error) {
+	msg, err := s.svc.Users.Messages.Get("me", messageID).Format("full").Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get message: %w", err)
+	}
+
+	full := parseMessageToFull(msg)
+	if invite, err := s.messageInvite(msg); err != nil {
+		full.InviteError = err.Error()
+	} else {
+		full.Invite = invite
+	}
+
+	return full, nil
+}
+
 // GetRaw returns a message in raw format (RFC 2822).
 func (s *Service) GetRaw(messageID string) ([]byte, error) {
 	msg, err := s.svc.Users.Messages.Get("me", messageID).Format("raw").Do()
@@ -114,8 +154,9 @@ func (s *Service) GetRaw(messageID string) ([]byte, error) {
 
 // GetThread returns all messages in a thread.
 func (s *Service) GetThread(threadID string) (*ThreadSummary, error) {
+	// A single threads.get returns the metadata of every message.
 	thread, err := s.svc.Users.Threads.Get("me", threadID).Format("metadata").
-		MetadataHeaders("From", "To", "Subject", "Date").Do()
+		MetadataHeaders(summaryHeaders...).Do()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get thread: %w", err)
 	}
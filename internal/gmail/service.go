@@ -12,7 +12,8 @@ import (
 
 // Service wraps the Gmail API service.
 type Service struct {
-	svc *gmail.Service
+	svc      *gmail.Service
+	identity *Identity // Cached by Identity
 }
 
 // NewService creates a new Gmail service.
@@ -52,6 +53,8 @@ type MessageFull struct {
 	Attachments []AttachmentInfo  `json:"attachments"`
 	LabelIDs    []string          `json:"label_ids"`
 	Headers     map[string]string `json:"headers,omitempty"`
+	Invite      *InviteInfo       `json:"invite,omitempty"`
+	InviteError string            `json:"invite_error,omitempty"`
 }
 
 // AttachmentInfo represents information about a message attachment.
@@ -0,0 +1,210 @@
+package gmail
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yuin/goldmark"
+	"google.golang.org/api/gmail/v1"
+)
+
+// VacationInfo represents the vacation responder (out-of-office) settings.
+type VacationInfo struct {
+	Enabled      bool   `json:"enabled"`
+	Subject      string `json:"subject,omitempty"`
+	MessageHTML  string `json:"message_html,omitempty"`
+	MessageText  string `json:"message_text,omitempty"`
+	Start        string `json:"start,omitempty"`
+	End          string `json:"end,omitempty"`
+	ContactsOnly bool   `json:"contacts_only"`
+	DomainOnly   bool   `json:"domain_only"`
+}
+
+// VacationOptions contains options for enabling the vacation responder.
+type VacationOptions struct {
+	Subject      string
+	Message      string // Markdown, rendered to HTML
+	Start        time.Time
+	End          time.Time
+	ContactsOnly bool
+	DomainOnly   bool
+}
+
+// Signature returns the signature of a send-as alias. An empty email means
+// the default alias.
+func (s *Service) Signature(email string) (*SendAsInfo, error) {
+	aliases, err := s.SendAs()
+	if err != nil {
+		return nil, err
+	}
+	return findAlias(aliases, email)
+}
+
+// SetSignature replaces the HTML signature of a send-as alias. An empty
+// email means the default alias.
+func (s *Service) SetSignature(email, html string) (*SendAsInfo, error) {
+	alias, err := s.Signature(email)
+	if err != nil {
+		return nil, err
+	}
+
+	sendAs := &gmail.SendAs{
+		Signature:       html,
+		ForceSendFields: []string{"Signature"},
+	}
+
+	updated, err := s.svc.Users.Settings.SendAs.Patch("me", alias.Email, sendAs).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to update signature: %w", err)
+	}
+
+	alias.Signature = updated.Signature
+	return alias, nil
+}
+
+// Vacation returns the vacation responder settings.
+func (s *Service) Vacation() (*VacationInfo, error) {
+	v, err := s.svc.Users.Settings.GetVacation("me").Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vacation settings: %w", err)
+	}
+	return parseVacation(v), nil
+}
+
+// SetVacation enables the vacation responder.
+func (s *Service) SetVacation(opts VacationOptions) (*VacationInfo, error) {
+	settings, err := buildVacation(opts)
+	if err != nil {
+		return nil, err
+	}
+	return s.updateVacation(settings)
+}
+
+// DisableVacation turns the vacation responder off, keeping its message.
+func (s *Service) DisableVacation() (*VacationInfo, error) {
+	current, err := s.svc.Users.Settings.GetVacation("me").Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vacation settings: %w", err)
+	}
+
+	current.EnableAutoReply = false
+	current.ForceSendFields = []string{"EnableAutoReply"}
+	return s.updateVacation(current)
+}
+
+func (s *Service) updateVacation(settings *gmail.VacationSettings) (*VacationInfo, error) {
+	v, err := s.svc.Users.Settings.UpdateVacation("me", settings).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to update vacation settings: %w", err)
+	}
+	return parseVacation(v), nil
+}
+
+// PreviewVacation returns the settings SetVacation would apply.
+func PreviewVacation(opts VacationOptions) (*VacationInfo, error) {
+	settings, err := buildVacation(opts)
+	if err != nil {
+		return nil, err
+	}
+	return parseVacation(settings), nil
+}
+
+// buildVacation converts options to the API representation.
+func buildVacation(opts VacationOptions) (*gmail.VacationSettings, error) {
+	if strings.TrimSpace(opts.Message) == "" {
+		return nil, fmt.Errorf("vacation message is required")
+	}
+	if !opts.Start.IsZero() && !opts.End.IsZero() && !opts.End.After(opts.Start) {
+		return nil, fmt.Errorf("vacation end must be after start")
+	}
+
+	html, err := MarkdownToHTML(opts.Message)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &gmail.VacationSettings{
+		EnableAutoReply:    true,
+		ResponseSubject:    opts.Subject,
+		ResponseBodyHtml:   html,
+		RestrictToContacts: opts.ContactsOnly,
+		RestrictToDomain:   opts.DomainOnly,
+		ForceSendFields:    []string{"RestrictToContacts", "RestrictToDomain"},
+	}
+	if !opts.Start.IsZero() {
+		v.StartTime = opts.Start.UnixMilli()
+	}
+	if !opts.End.IsZero() {
+		v.EndTime = opts.End.UnixMilli()
+	}
+
+	return v, nil
+}
+
+// parseVacation converts the API representation to a VacationInfo.
+func parseVacation(v *gmail.VacationSettings) *VacationInfo {
+	info := &VacationInfo{
+		Enabled:      v.EnableAutoReply,
+		Subject:      v.ResponseSubject,
+		MessageHTML:  v.ResponseBodyHtml,
+		MessageText:  v.ResponseBodyPlainText,
+		ContactsOnly: v.RestrictToContacts,
+		DomainOnly:   v.RestrictToDomain,
+	}
+	if info.MessageText == "" && info.MessageHTML != "" {
+		info.MessageText = stripHTML(info.MessageHTML)
+	}
+	if v.StartTime > 0 {
+		info.Start = time.UnixMilli(v.StartTime).Format(time.RFC3339)
+	}
+	if v.EndTime > 0 {
+		info.End = time.UnixMilli(v.EndTime).Format(time.RFC3339)
+	}
+	return info
+}
+
+// MarkdownToHTML renders Markdown to HTML.
+func MarkdownToHTML(markdown string) (string, error) {
+	var buf bytes.Buffer
+	if err := goldmark.Convert([]byte(markdown), &buf); err != nil {
+		return "", fmt.Errorf("failed to render markdown: %w", err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// findAlias returns the alias with the given address, or the default alias
+// when email is empty.
+func findAlias(aliases []SendAsInfo, email string) (*SendAsInfo, error) {
+	for i := range aliases {
+		a := &aliases[i]
+		if (email == "" && a.IsDefault) || (email != "" && strings.EqualFold(a.Email, normalizeAddress(email))) {
+			return a, nil
+		}
+	}
+	if email == "" {
+		return nil, fmt.Errorf("no default send-as alias found")
+	}
+	return nil, fmt.Errorf("not a send-as alias of this account: %s", email)
+}
+
+// ParseVacationTime parses an RFC3339 time or a local YYYY-MM-DD date. With
+// endOfDay, a date-only value means the end of that day.
+func ParseVacationTime(value string, endOfDay bool) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02T15:04", value, time.Local); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unrecognized time %q (use RFC3339 or YYYY-MM-DD)", value)
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
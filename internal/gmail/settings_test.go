@@ -0,0 +1,74 @@
+package gmail
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildVacation(t *testing.T) {
+	start := time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 10, 24, 0, 0, 0, 0, time.UTC)
+
+	v, err := buildVacation(VacationOptions{
+		Subject:      "Out of office",
+		Message:      "I'm travelling until **Friday**.",
+		Start:        start,
+		End:          end,
+		ContactsOnly: true,
+	})
+	require.NoError(t, err)
+	assert.True(t, v.EnableAutoReply)
+	assert.Equal(t, "<p>I'm travelling until <strong>Friday</strong>.</p>", v.ResponseBodyHtml)
+	assert.Equal(t, start.UnixMilli(), v.StartTime)
+	assert.Equal(t, end.UnixMilli(), v.EndTime)
+	assert.True(t, v.RestrictToContacts)
+	assert.Contains(t, v.ForceSendFields, "RestrictToDomain")
+
+	info := parseVacation(v)
+	assert.Equal(t, "I'm travelling until Friday.", info.MessageText)
+	assert.Equal(t, start.Local().Format(time.RFC3339), info.Start)
+}
+
+func TestBuildVacation_Invalid(t *testing.T) {
+	_, err := buildVacation(VacationOptions{})
+	assert.Error(t, err)
+
+	now := time.Now()
+	_, err = buildVacation(VacationOptions{Message: "Away", Start: now, End: now.Add(-time.Hour)})
+	assert.Error(t, err)
+}
+
+func TestParseVacationTime(t *testing.T) {
+	start, err := ParseVacationTime("2026-10-20", false)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 10, 20, 0, 0, 0, 0, time.Local), start)
+
+	end, err := ParseVacationTime("2026-10-24", true)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 10, 25, 0, 0, 0, 0, time.Local), end)
+
+	exact, err := ParseVacationTime("2026-10-24T17:00:00+02:00", true)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1792854000), exact.Unix())
+
+	_, err = ParseVacationTime("next week", false)
+	assert.Error(t, err)
+}
+
+func TestFindAlias(t *testing.T) {
+	aliases := testIdentity().Aliases
+
+	a, err := findAlias(aliases, "")
+	require.NoError(t, err)
+	assert.Equal(t, "me@example.com", a.Email)
+
+	a, err = findAlias(aliases, "Support <support@example.com>")
+	require.NoError(t, err)
+	assert.Equal(t, "support@example.com", a.Email)
+
+	_, err = findAlias(aliases, "other@example.com")
+	assert.Error(t, err)
+}
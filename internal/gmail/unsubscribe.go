@@ -0,0 +1,265 @@
+package gmail
+
+import (
+	"fmt"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Unsubscribe methods, in order of preference.
+const (
+	UnsubscribeOneClick = "one-click" // RFC 8058 HTTPS POST
+	UnsubscribeMailto   = "mailto"    // Email to the list's unsubscribe address
+	UnsubscribeLink     = "link"      // Web page that must be opened manually
+	UnsubscribeNone     = "none"
+)
+
+// Unsubscribe statuses.
+const (
+	UnsubscribeStatusDone      = "unsubscribed"
+	UnsubscribeStatusRequested = "requested"
+	UnsubscribeStatusManual    = "manual"
+	UnsubscribeStatusFailed    = "failed"
+)
+
+// unsubscribeHeaders are the headers needed to plan an unsubscribe.
+var unsubscribeHeaders = []string{"From", "List-Unsubscribe", "List-Unsubscribe-Post"}
+
+// unsubscribeClient performs one-click POSTs. It is not the OAuth client:
+// the request goes to the sender's server. Redirects are not followed.
+var unsubscribeClient = &http.Client{
+	Timeout: 30 * time.Second,
+	CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+// ListUnsubscribe holds the targets of a List-Unsubscribe header.
+type ListUnsubscribe struct {
+	HTTP     []string
+	Mailto   []string
+	OneClick bool
+}
+
+// ParseListUnsubscribe parses List-Unsubscribe and List-Unsubscribe-Post
+// header values. Targets are the <...> entries of the header, so URLs may
+// contain commas; text outside angle brackets is ignored.
+func ParseListUnsubscribe(header, post string) ListUnsubscribe {
+	var lu ListUnsubscribe
+	rest := header
+	for {
+		open := strings.IndexByte(rest, '<')
+		if open < 0 {
+			break
+		}
+		end := strings.IndexByte(rest[open:], '>')
+		if end < 0 {
+			break
+		}
+		target := strings.TrimSpace(rest[open+1 : open+end])
+		rest = rest[open+end+1:]
+		switch lower := strings.ToLower(target); {
+		case strings.HasPrefix(lower, "mailto:"):
+			lu.Mailto = append(lu.Mailto, target)
+		case strings.HasPrefix(lower, "https://"), strings.HasPrefix(lower, "http://"):
+			lu.HTTP = append(lu.HTTP, target)
+		}
+	}
+	lu.OneClick = strings.Contains(strings.ToLower(post), "list-unsubscribe=one-click")
+	return lu
+}
+
+// UnsubscribeTarget is the unsubscribe plan and outcome for one sender.
+type UnsubscribeTarget struct {
+	Sender        string `json:"sender"`
+	MessageID     string `json:"message_id"`
+	Messages      int    `json:"messages"`
+	Method        string `json:"method"`
+	URL           string `json:"url,omitempty"`
+	Mailto        string `json:"mailto,omitempty"`
+	Status        string `json:"status,omitempty"`
+	FilterID      string `json:"filter_id,omitempty"`
+	FilterSkipped bool   `json:"filter_skipped,omitempty"` // No unsubscribe method, so no filter was created
+	Error         string `json:"error,omitempty"`
+}
+
+// UnsubscribeOptions contains options for carrying out an unsubscribe.
+type UnsubscribeOptions struct {
+	Filter        bool // Create a filter archiving future mail from the sender
+	ArchiveAnyway bool // Create the filter even if the sender has no unsubscribe method
+}
+
+// planUnsubscribe picks the best unsubscribe method for a message. One-click
+// is only used for HTTPS targets.
+func planUnsubscribe(messageID string, headers map[string]string) UnsubscribeTarget {
+	t := UnsubscribeTarget{
+		Sender:    headerValue(headers, "From"),
+		MessageID: messageID,
+		Method:    UnsubscribeNone,
+	}
+
+	lu := ParseListUnsubscribe(headerValue(headers, "List-Unsubscribe"), headerValue(headers, "List-Unsubscribe-Post"))
+	var httpsURL string
+	for _, u := range lu.HTTP {
+		if strings.HasPrefix(strings.ToLower(u), "https://") {
+			httpsURL = u
+			break
+		}
+	}
+
+	switch {
+	case lu.OneClick && httpsURL != "":
+		t.Method = UnsubscribeOneClick
+		t.URL = httpsURL
+	case len(lu.Mailto) > 0:
+		t.Method = UnsubscribeMailto
+		t.Mailto = lu.Mailto[0]
+		if len(lu.HTTP) > 0 {
+			t.URL = lu.HTTP[0]
+		}
+	case len(lu.HTTP) > 0:
+		t.Method = UnsubscribeLink
+		t.URL = lu.HTTP[0]
+	}
+
+	return t
+}
+
+// UnsubscribeTargets fetches the messages and returns one unsubscribe plan
+// per sender, based on the first message listed for that sender (the
+// newest when ids come from a search).
+func (s *Service) UnsubscribeTargets(ids []string, workers int) ([]UnsubscribeTarget, []FetchError) {
+	messages, failed := s.fetchMetadata(ids, unsubscribeHeaders, workers)
+
+	var targets []UnsubscribeTarget
+	index := map[string]int{}
+	for _, m := range messages {
+		if m == nil {
+			continue
+		}
+		headers := extractHeaders(m.Payload)
+		key := normalizeAddress(headerValue(headers, "From"))
+		if i, ok := index[key]; ok {
+			targets[i].Messages++
+			continue
+		}
+		t := planUnsubscribe(m.Id, headers)
+		t.Messages = 1
+		index[key] = len(targets)
+		targets = append(targets, t)
+	}
+
+	return targets, failed
+}
+
+// Unsubscribe carries out the plan of t and records the outcome in it.
+// With opts.Filter, a filter archiving future mail from the sender is
+// created, but only for senders without an unsubscribe method if
+// opts.ArchiveAnyway is also set.
+func (s *Service) Unsubscribe(t *UnsubscribeTarget, opts UnsubscribeOptions) {
+	var err error
+	switch t.Method {
+	case UnsubscribeOneClick:
+		if err = oneClickUnsubscribe(t.URL); err == nil {
+			t.Status = UnsubscribeStatusDone
+		}
+	case UnsubscribeMailto:
+		if err = s.mailtoUnsubscribe(t.Mailto); err == nil {
+			t.Status = UnsubscribeStatusRequested
+		}
+	default:
+		t.Status = UnsubscribeStatusManual
+	}
+	if err != nil {
+		t.Status = UnsubscribeStatusFailed
+		t.Error = err.Error()
+	}
+
+	if opts.Filter && t.Method == UnsubscribeNone && !opts.ArchiveAnyway {
+		t.FilterSkipped = true
+		return
+	}
+	if opts.Filter {
+		filter, err := s.CreateFilter(FilterInfo{
+			Criteria: FilterCriteria{From: normalizeAddress(t.Sender)},
+			Action:   FilterAction{Archive: true},
+		})
+		if err != nil {
+			if t.Error == "" {
+				t.Error = err.Error()
+			}
+			return
+		}
+		t.FilterID = filter.ID
+	}
+}
+
+// oneClickUnsubscribe performs an RFC 8058 one-click unsubscribe POST.
+func oneClickUnsubscribe(target string) error {
+	if !strings.HasPrefix(strings.ToLower(target), "https://") {
+		return fmt.Errorf("one-click unsubscribe requires an HTTPS URL: %s", target)
+	}
+
+	resp, err := unsubscribeClient.Post(target, "application/x-www-form-urlencoded",
+		strings.NewReader("List-Unsubscribe=One-Click"))
+	if err != nil {
+		return fmt.Errorf("failed to unsubscribe: %w", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("failed to unsubscribe: %s returned %s", target, resp.Status)
+	}
+	return nil
+}
+
+// mailtoUnsubscribe sends the unsubscribe email described by a mailto URL.
+func (s *Service) mailtoUnsubscribe(target string) error {
+	opts, err := parseMailto(target)
+	if err != nil {
+		return err
+	}
+	_, err = s.Send(opts)
+	return err
+}
+
+// parseMailto converts a mailto: URL to send options. The URL comes from
+// the sender, so it must name exactly one address and a subject without
+// line breaks. The subject defaults to "unsubscribe"; any body= parameter
+// is ignored and the body is always "unsubscribe".
+func parseMailto(target string) (SendOptions, error) {
+	u, err := url.Parse(target)
+	if err != nil || !strings.EqualFold(u.Scheme, "mailto") {
+		return SendOptions{}, fmt.Errorf("invalid mailto URL: %s", target)
+	}
+
+	addr := u.Opaque
+	if addr == "" {
+		addr = u.Path
+	}
+	addr, err = url.PathUnescape(addr)
+	if err != nil || addr == "" {
+		return SendOptions{}, fmt.Errorf("invalid mailto URL: %s", target)
+	}
+
+	parsed, err := mail.ParseAddress(addr)
+	if err != nil || strings.ContainsAny(addr, ",;") {
+		return SendOptions{}, fmt.Errorf("mailto URL must name exactly one address: %s", target)
+	}
+
+	subject := u.Query().Get("subject")
+	if strings.ContainsAny(subject, "\r\n") {
+		return SendOptions{}, fmt.Errorf("mailto subject contains a line break: %s", target)
+	}
+	if subject == "" {
+		subject = "unsubscribe"
+	}
+	return SendOptions{
+		To:      []string{parsed.Address},
+		Subject: subject,
+		Body:    "unsubscribe",
+	}, nil
+}
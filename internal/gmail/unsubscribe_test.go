@@ -0,0 +1,125 @@
+package gmail
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseListUnsubscribe(t *testing.T) {
+	lu := ParseListUnsubscribe(
+		"<mailto:leave@list.example.com?subject=unsub>, <https://list.example.com/u/123>",
+		"List-Unsubscribe=One-Click")
+	assert.Equal(t, []string{"mailto:leave@list.example.com?subject=unsub"}, lu.Mailto)
+	assert.Equal(t, []string{"https://list.example.com/u/123"}, lu.HTTP)
+	assert.True(t, lu.OneClick)
+
+	assert.Equal(t, ListUnsubscribe{}, ParseListUnsubscribe("", ""))
+
+	lu = ParseListUnsubscribe("<https://x.com/u?a=1,2&b=3>,<mailto:a@x.com>", "")
+	assert.Equal(t, []string{"https://x.com/u?a=1,2&b=3"}, lu.HTTP)
+	assert.Equal(t, []string{"mailto:a@x.com"}, lu.Mailto)
+}
+
+func TestPlanUnsubscribe(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		method  string
+		target  string
+	}{
+		{
+			"one-click preferred",
+			map[string]string{"List-Unsubscribe": "<mailto:a@x.com>, <https://x.com/u>", "List-Unsubscribe-Post": "List-Unsubscribe=One-Click"},
+			UnsubscribeOneClick, "https://x.com/u",
+		},
+		{
+			"one-click needs https",
+			map[string]string{"List-Unsubscribe": "<http://x.com/u>", "List-Unsubscribe-Post": "List-Unsubscribe=One-Click"},
+			UnsubscribeLink, "http://x.com/u",
+		},
+		{
+			"mailto without one-click",
+			map[string]string{"List-Unsubscribe": "<https://x.com/u>, <mailto:a@x.com>"},
+			UnsubscribeMailto, "mailto:a@x.com",
+		},
+		{"none", map[string]string{}, UnsubscribeNone, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := planUnsubscribe("m1", tt.headers)
+			assert.Equal(t, tt.method, plan.Method)
+			if tt.method == UnsubscribeMailto {
+				assert.Equal(t, tt.target, plan.Mailto)
+			} else {
+				assert.Equal(t, tt.target, plan.URL)
+			}
+		})
+	}
+}
+
+func TestParseMailto(t *testing.T) {
+	opts, err := parseMailto("mailto:leave%2B123@list.example.com?subject=Remove%20me")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"leave+123@list.example.com"}, opts.To)
+	assert.Equal(t, "Remove me", opts.Subject)
+	assert.Equal(t, "unsubscribe", opts.Body)
+
+	opts, err = parseMailto("mailto:a@x.com?body=Please%20forward%20everything")
+	require.NoError(t, err)
+	assert.Equal(t, "unsubscribe", opts.Subject)
+	assert.Equal(t, "unsubscribe", opts.Body)
+
+	for _, target := range []string{
+		"https://example.com",
+		"mailto:a@x.com,b@x.com",
+		"mailto:a@x.com?subject=x%0D%0ABcc:%20victim@y.com",
+		"mailto:a@x.com?subject=x%0ABcc:%20victim@y.com",
+	} {
+		_, err = parseMailto(target)
+		assert.Error(t, err, target)
+	}
+}
+
+func TestBuildRawMessage_HeaderLineBreaks(t *testing.T) {
+	raw := buildRawMessage([]string{"a@x.com"}, nil, nil, "Hi\r\nBcc: victim@y.com", "body",
+		map[string]string{"In-Reply-To": "<id>\nX-Evil: 1"})
+	assert.NotContains(t, raw, "\r\nBcc:")
+	assert.NotContains(t, raw, "\nX-Evil:")
+	assert.Contains(t, raw, "Subject: Hi Bcc: victim@y.com\r\n")
+}
+
+func TestOneClickUnsubscribe(t *testing.T) {
+	var body string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	orig := unsubscribeClient
+	unsubscribeClient = server.Client()
+	defer func() { unsubscribeClient = orig }()
+
+	require.NoError(t, oneClickUnsubscribe(server.URL+"/u"))
+	assert.Equal(t, "List-Unsubscribe=One-Click", body)
+
+	assert.Error(t, oneClickUnsubscribe("http://example.com/u"))
+}
+
+func TestUnsubscribe_SkipsFilterWithoutMethod(t *testing.T) {
+	// No API calls are made, so a zero Service is enough.
+	target := UnsubscribeTarget{Sender: "news@example.com", Method: UnsubscribeNone}
+	(&Service{}).Unsubscribe(&target, UnsubscribeOptions{Filter: true})
+
+	assert.Equal(t, UnsubscribeStatusManual, target.Status)
+	assert.True(t, target.FilterSkipped)
+	assert.Empty(t, target.FilterID)
+}
@@ -38,30 +38,54 @@ func (s *Service) Send(opts SendOptions) (*SendResult, error) {
 
 // ReplyOptions contains options for replying to a message.
 type ReplyOptions struct {
-	MessageID string
-	Body      string
-	ReplyAll  bool
+	MessageID   string
+	Body        string
+	ReplyAll    bool
+	From        string // Send-as alias to reply from; its signature is appended
+	NoSignature bool   // Do not append the alias signature
 }
 
-// Reply sends a reply to an existing message.
-func (s *Service) Reply(opts ReplyOptions) (*SendResult, error) {
+// ReplyDraft is a fully resolved reply, ready to send.
+type ReplyDraft struct {
+	To       []string `json:"to"`
+	Cc       []string `json:"cc,omitempty"`
+	From     string   `json:"from,omitempty"`
+	Subject  string   `json:"subject"`
+	Body     string   `json:"body"`
+	ThreadID string   `json:"thread_id"`
+	headers  map[string]string
+}
+
+// PrepareReply resolves the recipients, sender alias, subject and body of a
+// reply without sending it. The mailbox's own addresses (primary and
+// send-as aliases) are excluded from the recipients.
+func (s *Service) PrepareReply(opts ReplyOptions) (*ReplyDraft, error) {
 	// Get the original message
 	original, err := s.Get(opts.MessageID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get original message: %w", err)
 	}
 
+	id, err := s.Identity()
+	if err != nil {
+		return nil, err
+	}
+
 	// Build recipients
-	to := []string{original.From}
-	var cc []string
-	if opts.ReplyAll {
-		// Add original recipients (excluding self) to Cc
-		for _, addr := range original.To {
-			if !strings.Contains(strings.ToLower(addr), "me") {
-				cc = append(cc, addr)
-			}
+	to, cc := replyRecipients(original.Headers, id, opts.ReplyAll)
+
+	alias, err := replyAlias(original.Headers, id, opts.From)
+	if err != nil {
+		return nil, err
+	}
+
+	body := opts.Body
+	var from string
+	if alias != nil {
+		from = alias.FromHeader()
+		if opts.From != "" && !opts.NoSignature {
+			body += signatureText(alias.Signature)
 		}
-		cc = append(cc, original.Cc...)
 	}
 
 	// Build subject with Re: prefix
@@ -71,16 +95,36 @@ func (s *Service) Reply(opts ReplyOptions) (*SendResult, error) {
 	}
 
 	// Build headers for threading
+	messageID := headerValue(original.Headers, "Message-ID")
 	headers := map[string]string{
-		"In-Reply-To": original.Headers["Message-ID"],
-		"References":  original.Headers["References"] + " " + original.Headers["Message-ID"],
+		"From":        from,
+		"In-Reply-To": messageID,
+		"References":  headerValue(original.Headers, "References") + " " + messageID,
+	}
+
+	return &ReplyDraft{
+		To:       to,
+		Cc:       cc,
+		From:     from,
+		Subject:  subject,
+		Body:     body,
+		ThreadID: original.ThreadID,
+		headers:  headers,
+	}, nil
+}
+
+// Reply sends a reply to an existing message.
+func (s *Service) Reply(opts ReplyOptions) (*SendResult, error) {
+	draft, err := s.PrepareReply(opts)
+	if err != nil {
+		return nil, err
 	}
 
-	raw := buildRawMessage(to, cc, nil, subject, opts.Body, headers)
+	raw := buildRawMessage(draft.To, draft.Cc, nil, draft.Subject, draft.Body, draft.headers)
 
 	msg := &gmail.Message{
 		Raw:      base64.URLEncoding.EncodeToString([]byte(raw)),
-		ThreadId: original.ThreadID,
+		ThreadId: draft.ThreadID,
 	}
 
 	sent, err := s.svc.Users.Messages.Send("me", msg).Do()
@@ -296,24 +340,25 @@ func (s *Service) SendRaw(raw []byte) (*SendResult, error) {
 	}, nil
 }
 
-// buildRawMessage builds a raw RFC 2822 message.
+// buildRawMessage builds a raw RFC 2822 message. Line breaks in header
+// values are replaced with spaces so a value cannot start a new header.
 func buildRawMessage(to, cc, bcc []string, subject, body string, extraHeaders map[string]string) string {
 	var msg strings.Builder
 
-	msg.WriteString(fmt.Sprintf("To: %s\r\n", strings.Join(to, ", ")))
+	msg.WriteString(fmt.Sprintf("To: %s\r\n", foldHeader(strings.Join(to, ", "))))
 	if len(cc) > 0 {
-		msg.WriteString(fmt.Sprintf("Cc: %s\r\n", strings.Join(cc, ", ")))
+		msg.WriteString(fmt.Sprintf("Cc: %s\r\n", foldHeader(strings.Join(cc, ", "))))
 	}
 	if len(bcc) > 0 {
-		msg.WriteString(fmt.Sprintf("Bcc: %s\r\n", strings.Join(bcc, ", ")))
+		msg.WriteString(fmt.Sprintf("Bcc: %s\r\n", foldHeader(strings.Join(bcc, ", "))))
 	}
-	msg.WriteString(fmt.Sprintf("Subject: %s\r\n", subject))
+	msg.WriteString(fmt.Sprintf("Subject: %s\r\n", foldHeader(subject)))
 	msg.WriteString("Content-Type: text/plain; charset=\"UTF-8\"\r\n")
 	msg.WriteString("MIME-Version: 1.0\r\n")
 
 	for key, value := range extraHeaders {
 		if value != "" {
-			msg.WriteString(fmt.Sprintf("%s: %s\r\n", key, strings.TrimSpace(value)))
+			msg.WriteString(fmt.Sprintf("%s: %s\r\n", key, foldHeader(strings.TrimSpace(value))))
 		}
 	}
 
@@ -322,3 +367,11 @@ func buildRawMessage(to, cc, bcc []string, subject, body string, extraHeaders ma
 
 	return msg.String()
 }
+
+// headerLineBreaks replaces CR and LF in header values.
+var headerLineBreaks = strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ")
+
+// foldHeader puts a header value on a single line.
+func foldHeader(value string) string {
+	return headerLineBreaks.Replace(value)
+}
@@ -0,0 +1,265 @@
+// Package ical provides a minimal iCalendar (RFC 5545) parser.
+package ical
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Property is a content line such as DTSTART;TZID=Europe/Berlin:20261020T153000.
+type Property struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+// Component is a BEGIN/END block such as VCALENDAR, VEVENT or VTIMEZONE.
+type Component struct {
+	Name       string
+	Properties []Property
+	Components []*Component
+}
+
+// Parse parses iCalendar data and returns the outermost component
+// (normally VCALENDAR).
+func Parse(data []byte) (*Component, error) {
+	var stack []*Component
+	var root *Component
+
+	for _, line := range unfold(data) {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		prop, err := parseLine(line)
+		if err != nil {
+			return nil, err
+		}
+
+		switch prop.Name {
+		case "BEGIN":
+			c := &Component{Name: strings.ToUpper(prop.Value)}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.Components = append(parent.Components, c)
+			} else if root == nil {
+				root = c
+			}
+			stack = append(stack, c)
+		case "END":
+			if len(stack) == 0 || stack[len(stack)-1].Name != strings.ToUpper(prop.Value) {
+				return nil, fmt.Errorf("unexpected END:%s", prop.Value)
+			}
+			stack = stack[:len(stack)-1]
+		default:
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("property %s outside of a component", prop.Name)
+			}
+			c := stack[len(stack)-1]
+			c.Properties = append(c.Properties, prop)
+		}
+	}
+
+	if root == nil {
+		return nil, fmt.Errorf("no iCalendar data found")
+	}
+	if len(stack) > 0 {
+		return nil, fmt.Errorf("missing END:%s", stack[len(stack)-1].Name)
+	}
+	return root, nil
+}
+
+// unfold joins folded lines (continuations start with a space or tab).
+func unfold(data []byte) []string {
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// parseLine splits a content line into name, parameters and value.
+// Parameter values may be quoted and contain ':' or ';'.
+func parseLine(line string) (Property, error) {
+	prop := Property{Params: map[string]string{}}
+
+	i := strings.IndexAny(line, ";:")
+	if i <= 0 {
+		return prop, fmt.Errorf("invalid content line: %q", line)
+	}
+	prop.Name = strings.ToUpper(line[:i])
+
+	rest := line[i:]
+	for strings.HasPrefix(rest, ";") {
+		rest = rest[1:]
+		eq := strings.Index(rest, "=")
+		if eq < 0 {
+			return prop, fmt.Errorf("invalid parameter in %q", line)
+		}
+		key := strings.ToUpper(rest[:eq])
+		rest = rest[eq+1:]
+
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.Index(rest[1:], `"`)
+			if end < 0 {
+				return prop, fmt.Errorf("unterminated quote in %q", line)
+			}
+			value = rest[1 : end+1]
+			rest = rest[end+2:]
+		} else {
+			end := strings.IndexAny(rest, ";:")
+			if end < 0 {
+				return prop, fmt.Errorf("invalid content line: %q", line)
+			}
+			value = rest[:end]
+			rest = rest[end:]
+		}
+		prop.Params[key] = value
+	}
+
+	if !strings.HasPrefix(rest, ":") {
+		return prop, fmt.Errorf("invalid content line: %q", line)
+	}
+	prop.Value = rest[1:]
+	return prop, nil
+}
+
+// Prop returns the first property with the given name, or nil.
+func (c *Component) Prop(name string) *Property {
+	name = strings.ToUpper(name)
+	for i := range c.Properties {
+		if c.Properties[i].Name == name {
+			return &c.Properties[i]
+		}
+	}
+	return nil
+}
+
+// Props returns all properties with the given name.
+func (c *Component) Props(name string) []Property {
+	name = strings.ToUpper(name)
+	var props []Property
+	for _, p := range c.Properties {
+		if p.Name == name {
+			props = append(props, p)
+		}
+	}
+	return props
+}
+
+// Text returns the unescaped text value of the named property, or "".
+func (c *Component) Text(name string) string {
+	if p := c.Prop(name); p != nil {
+		return Unescape(p.Value)
+	}
+	return ""
+}
+
+// Find returns all nested components with the given name, depth first.
+func (c *Component) Find(name string) []*Component {
+	name = strings.ToUpper(name)
+	var found []*Component
+	for _, child := range c.Components {
+		if child.Name == name {
+			found = append(found, child)
+		}
+		found = append(found, child.Find(name)...)
+	}
+	return found
+}
+
+// Time parses a DATE or DATE-TIME property value. Times with a TZID
+// parameter are resolved in that zone, which must be an IANA or Windows
+// zone name; UTC times end in Z and floating times use defaultLoc.
+// allDay is true for DATE values. Use Component.Time to also resolve
+// zones defined by the calendar's VTIMEZONE components.
+func (p *Property) Time(defaultLoc *time.Location) (t time.Time, allDay bool, err error) {
+	return p.timeIn(defaultLoc, nil)
+}
+
+// Time parses a DATE or DATE-TIME property of an event in calendar c like
+// Property.Time, resolving TZIDs that are not known zone names from the
+// VTIMEZONE components of c. An unresolvable TZID is an error.
+func (c *Component) Time(p *Property, defaultLoc *time.Location) (t time.Time, allDay bool, err error) {
+	return p.timeIn(defaultLoc, c.Find("VTIMEZONE"))
+}
+
+func (p *Property) timeIn(defaultLoc *time.Location, zones []*Component) (t time.Time, allDay bool, err error) {
+	if defaultLoc == nil {
+		defaultLoc = time.Local
+	}
+	value := strings.TrimSpace(p.Value)
+
+	if strings.EqualFold(p.Params["VALUE"], "DATE") || len(value) == 8 {
+		t, err = time.ParseInLocation("20060102", value, defaultLoc)
+		return t, true, err
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err = time.Parse("20060102T150405Z", value)
+		return t, false, err
+	}
+
+	tzid := p.Params["TZID"]
+	if tzid == "" {
+		t, err = time.ParseInLocation("20060102T150405", value, defaultLoc)
+		return t, false, err
+	}
+
+	wall, err := parseLocal(value)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	loc, err := zoneAt(tzid, wall, zones)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	t = time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), 0, loc)
+	return t, false, nil
+}
+
+// Unescape decodes an iCalendar TEXT value.
+func Unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+			switch s[i] {
+			case 'n', 'N':
+				b.WriteByte('\n')
+			default:
+				b.WriteByte(s[i])
+			}
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// CalAddress returns the email of a CAL-ADDRESS value by stripping its
+// mailto: prefix.
+func CalAddress(value string) string {
+	if len(value) >= 7 && strings.EqualFold(value[:7], "mailto:") {
+		return value[7:]
+	}
+	return value
+}
+
+// Escape encodes a string as an iCalendar TEXT value.
+func Escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
@@ -0,0 +1,82 @@
+package ical
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sample = "BEGIN:VCALENDAR\r\n" +
+	"METHOD:REQUEST\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:abc123@google.com\r\n" +
+	"SUMMARY:Planning\\, Q4\r\n" +
+	"DESCRIPTION:Line one\\nLine two with a long text that is folded onto\r\n" +
+	"  the next line\r\n" +
+	"DTSTART;TZID=Europe/Berlin:20261020T153000\r\n" +
+	"DTEND:20261020T143000Z\r\n" +
+	"ATTENDEE;CN=\"Doe, Jane\";PARTSTAT=ACCEPTED:mailto:jane@example.com\r\n" +
+	"ATTENDEE;PARTSTAT=NEEDS-ACTION:mailto:me@example.com\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParse(t *testing.T) {
+	cal, err := Parse([]byte(sample))
+	require.NoError(t, err)
+	assert.Equal(t, "VCALENDAR", cal.Name)
+	assert.Equal(t, "REQUEST", cal.Text("METHOD"))
+
+	events := cal.Find("VEVENT")
+	require.Len(t, events, 1)
+	ev := events[0]
+
+	assert.Equal(t, "Planning, Q4", ev.Text("SUMMARY"))
+	assert.Equal(t, "Line one\nLine two with a long text that is folded onto the next line", ev.Text("DESCRIPTION"))
+
+	attendees := ev.Props("ATTENDEE")
+	require.Len(t, attendees, 2)
+	assert.Equal(t, "Doe, Jane", attendees[0].Params["CN"])
+	assert.Equal(t, "mailto:jane@example.com", attendees[0].Value)
+
+	start, allDay, err := ev.Prop("DTSTART").Time(time.UTC)
+	require.NoError(t, err)
+	assert.False(t, allDay)
+	assert.Equal(t, "2026-10-20T15:30:00+02:00", start.Format(time.RFC3339))
+
+	end, _, err := ev.Prop("DTEND").Time(time.UTC)
+	require.NoError(t, err)
+	assert.True(t, end.Equal(start.Add(time.Hour)))
+}
+
+func TestParse_Errors(t *testing.T) {
+	_, err := Parse([]byte("BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nEND:VCALENDAR\r\n"))
+	assert.Error(t, err)
+
+	_, err = Parse([]byte("BEGIN:VCALENDAR\r\n"))
+	assert.Error(t, err)
+
+	_, err = Parse([]byte("hello"))
+	assert.Error(t, err)
+}
+
+func TestPropertyTime_Date(t *testing.T) {
+	p := Property{Name: "DTSTART", Params: map[string]string{"VALUE": "DATE"}, Value: "20261024"}
+	d, allDay, err := p.Time(time.UTC)
+	require.NoError(t, err)
+	assert.True(t, allDay)
+	assert.Equal(t, time.Date(2026, 10, 24, 0, 0, 0, 0, time.UTC), d)
+}
+
+func TestCalAddress(t *testing.T) {
+	assert.Equal(t, "jane@example.com", CalAddress("mailto:jane@example.com"))
+	assert.Equal(t, "jane@example.com", CalAddress("MAILTO:jane@example.com"))
+	assert.Equal(t, "jane@example.com", CalAddress("jane@example.com"))
+}
+
+func TestEscape(t *testing.T) {
+	s := "a, b; c\\d\nnext"
+	assert.Equal(t, `a\, b\; c\\d\nnext`, Escape(s))
+	assert.Equal(t, s, Unescape(Escape(s)))
+}
@@ -0,0 +1,297 @@
+package ical
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// windowsZones maps the Windows time zone names Outlook and Exchange put in
+// TZID parameters to IANA zones.
+var windowsZones = map[string]string{
+	"Dateline Standard Time":          "Etc/GMT+12",
+	"Hawaiian Standard Time":          "Pacific/Honolulu",
+	"Alaskan Standard Time":           "America/Anchorage",
+	"Pacific Standard Time":           "America/Los_Angeles",
+	"US Mountain Standard Time":       "America/Phoenix",
+	"Mountain Standard Time":          "America/Denver",
+	"Central America Standard Time":   "America/Guatemala",
+	"Central Standard Time":           "America/Chicago",
+	"Central Standard Time (Mexico)":  "America/Mexico_City",
+	"Canada Central Standard Time":    "America/Regina",
+	"SA Pacific Standard Time":        "America/Bogota",
+	"Eastern Standard Time":           "America/New_York",
+	"US Eastern Standard Time":        "America/Indiana/Indianapolis",
+	"Venezuela Standard Time":         "America/Caracas",
+	"Atlantic Standard Time":          "America/Halifax",
+	"SA Western Standard Time":        "America/La_Paz",
+	"Pacific SA Standard Time":        "America/Santiago",
+	"Newfoundland Standard Time":      "America/St_Johns",
+	"E. South America Standard Time":  "America/Sao_Paulo",
+	"Argentina Standard Time":         "America/Argentina/Buenos_Aires",
+	"Greenland Standard Time":         "America/Nuuk",
+	"Azores Standard Time":            "Atlantic/Azores",
+	"GMT Standard Time":               "Europe/London",
+	"Greenwich Standard Time":         "Atlantic/Reykjavik",
+	"W. Europe Standard Time":         "Europe/Berlin",
+	"Central Europe Standard Time":    "Europe/Budapest",
+	"Romance Standard Time":           "Europe/Paris",
+	"Central European Standard Time":  "Europe/Warsaw",
+	"W. Central Africa Standard Time": "Africa/Lagos",
+	"GTB Standard Time":               "Europe/Bucharest",
+	"E. Europe Standard Time":         "Europe/Chisinau",
+	"FLE Standard Time":               "Europe/Kyiv",
+	"Israel Standard Time":            "Asia/Jerusalem",
+	"Egypt Standard Time":             "Africa/Cairo",
+	"South Africa Standard Time":      "Africa/Johannesburg",
+	"Turkey Standard Time":            "Europe/Istanbul",
+	"Arab Standard Time":              "Asia/Riyadh",
+	"Russian Standard Time":           "Europe/Moscow",
+	"E. Africa Standard Time":         "Africa/Nairobi",
+	"Iran Standard Time":              "Asia/Tehran",
+	"Arabian Standard Time":           "Asia/Dubai",
+	"Pakistan Standard Time":          "Asia/Karachi",
+	"India Standard Time":             "Asia/Kolkata",
+	"Nepal Standard Time":             "Asia/Kathmandu",
+	"Bangladesh Standard Time":        "Asia/Dhaka",
+	"SE Asia Standard Time":           "Asia/Bangkok",
+	"China Standard Time":             "Asia/Shanghai",
+	"Singapore Standard Time":         "Asia/Singapore",
+	"W. Australia Standard Time":      "Australia/Perth",
+	"Taipei Standard Time":            "Asia/Taipei",
+	"Tokyo Standard Time":             "Asia/Tokyo",
+	"Korea Standard Time":             "Asia/Seoul",
+	"Cen. Australia Standard Time":    "Australia/Adelaide",
+	"AUS Eastern Standard Time":       "Australia/Sydney",
+	"E. Australia Standard Time":      "Australia/Brisbane",
+	"Tasmania Standard Time":          "Australia/Hobart",
+	"New Zealand Standard Time":       "Pacific/Auckland",
+}
+
+// weekdays maps iCalendar weekday codes to time.Weekday.
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// zoneAt returns the location for a TZID at the given wall-clock time
+// (whose own location is ignored). IANA names and Windows names are
+// loaded from the time zone database; other TZIDs are resolved from the
+// matching VTIMEZONE in zones, which yields a fixed offset valid at wall.
+func zoneAt(tzid string, wall time.Time, zones []*Component) (*time.Location, error) {
+	name := strings.TrimPrefix(tzid, "/")
+	if name != "" && name != "Local" {
+		if loc, err := time.LoadLocation(name); err == nil {
+			return loc, nil
+		}
+	}
+	if iana, ok := windowsZones[tzid]; ok {
+		if loc, err := time.LoadLocation(iana); err == nil {
+			return loc, nil
+		}
+	}
+
+	for _, tz := range zones {
+		if tz.Text("TZID") != tzid {
+			continue
+		}
+		if lic := tz.Text("X-LIC-LOCATION"); lic != "" {
+			if loc, err := time.LoadLocation(lic); err == nil {
+				return loc, nil
+			}
+		}
+		offset, err := observanceOffset(tz, wall)
+		if err != nil {
+			return nil, fmt.Errorf("time zone %q: %w", tzid, err)
+		}
+		return time.FixedZone(tzid, offset), nil
+	}
+
+	return nil, fmt.Errorf("unknown time zone %q", tzid)
+}
+
+// observanceOffset returns the UTC offset a VTIMEZONE gives the wall-clock
+// time: that of the STANDARD or DAYLIGHT observance with the latest onset
+// at or before it. Onsets come from DTSTART, RDATE and yearly RRULEs using
+// BYMONTH and BYDAY, which covers the definitions calendar clients write.
+func observanceOffset(tz *Component, wall time.Time) (int, error) {
+	wall = naive(wall)
+	var best time.Time
+	var offset, earliestFrom int
+	var earliest time.Time
+	found := false
+
+	for _, obs := range tz.Components {
+		if obs.Name != "STANDARD" && obs.Name != "DAYLIGHT" {
+			continue
+		}
+		start, err := parseLocal(obs.Text("DTSTART"))
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s DTSTART: %w", obs.Name, err)
+		}
+		to, err := parseOffset(obs.Text("TZOFFSETTO"))
+		if err != nil {
+			return 0, err
+		}
+		if earliest.IsZero() || start.Before(earliest) {
+			earliest = start
+			if earliestFrom, err = parseOffset(obs.Text("TZOFFSETFROM")); err != nil {
+				earliestFrom = to
+			}
+		}
+
+		onsets := []time.Time{start}
+		for _, p := range obs.Props("RDATE") {
+			for _, value := range strings.Split(p.Value, ",") {
+				if t, err := parseLocal(value); err == nil {
+					onsets = append(onsets, t)
+				}
+			}
+		}
+		if rule := obs.Text("RRULE"); rule != "" {
+			yearly, err := yearlyOnsets(rule, start, wall.Year())
+			if err != nil {
+				return 0, fmt.Errorf("%s: %w", obs.Name, err)
+			}
+			onsets = append(onsets, yearly...)
+		}
+
+		for _, onset := range onsets {
+			if !onset.After(wall) && (!found || onset.After(best)) {
+				best, offset, found = onset, to, true
+			}
+		}
+	}
+
+	if earliest.IsZero() {
+		return 0, fmt.Errorf("no STANDARD or DAYLIGHT observance")
+	}
+	if !found {
+		// Before the first onset the zone is in its original offset.
+		return earliestFrom, nil
+	}
+	return offset, nil
+}
+
+// yearlyOnsets returns the onsets of a yearly observance rule in the year
+// before and the year of the given year, so the latest one before a time
+// in that year is always among them.
+func yearlyOnsets(rule string, start time.Time, year int) ([]time.Time, error) {
+	parts := map[string]string{}
+	for _, part := range strings.Split(strings.ToUpper(rule), ";") {
+		if k, v, ok := strings.Cut(part, "="); ok {
+			parts[k] = v
+		}
+	}
+	if parts["FREQ"] != "YEARLY" || parts["BYMONTHDAY"] != "" {
+		return nil, fmt.Errorf("unsupported rule %q", rule)
+	}
+
+	month := int(start.Month())
+	if v := parts["BYMONTH"]; v != "" {
+		m, err := strconv.Atoi(v)
+		if err != nil || m < 1 || m > 12 {
+			return nil, fmt.Errorf("unsupported rule %q", rule)
+		}
+		month = m
+	}
+
+	var until time.Time
+	if v := parts["UNTIL"]; v != "" {
+		t, err := parseLocal(strings.TrimSuffix(v, "Z"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid UNTIL in %q", rule)
+		}
+		until = t
+	}
+
+	var onsets []time.Time
+	for y := year - 1; y <= year; y++ {
+		if y < start.Year() {
+			continue
+		}
+		day := start.Day()
+		if v := parts["BYDAY"]; v != "" {
+			d, err := nthWeekday(v, y, time.Month(month))
+			if err != nil {
+				return nil, fmt.Errorf("unsupported rule %q", rule)
+			}
+			day = d
+		}
+		onset := time.Date(y, time.Month(month), day, start.Hour(), start.Minute(), start.Second(), 0, time.UTC)
+		if onset.Before(start) || (!until.IsZero() && onset.After(until)) {
+			continue
+		}
+		onsets = append(onsets, onset)
+	}
+	return onsets, nil
+}
+
+// nthWeekday returns the day of month of a BYDAY value such as 2SU or -1SU.
+func nthWeekday(byday string, year int, month time.Month) (int, error) {
+	if len(byday) < 2 {
+		return 0, fmt.Errorf("invalid BYDAY %q", byday)
+	}
+	wd, ok := weekdays[byday[len(byday)-2:]]
+	if !ok {
+		return 0, fmt.Errorf("invalid BYDAY %q", byday)
+	}
+	n := 1
+	if prefix := byday[:len(byday)-2]; prefix != "" {
+		var err error
+		if n, err = strconv.Atoi(prefix); err != nil || n == 0 || n > 5 || n < -5 {
+			return 0, fmt.Errorf("invalid BYDAY %q", byday)
+		}
+	}
+
+	if n > 0 {
+		first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+		day := 1 + (int(wd)-int(first.Weekday())+7)%7 + (n-1)*7
+		if day > daysIn(year, month) {
+			return 0, fmt.Errorf("invalid BYDAY %q", byday)
+		}
+		return day, nil
+	}
+	lastDay := daysIn(year, month)
+	last := time.Date(year, month, lastDay, 0, 0, 0, 0, time.UTC)
+	day := lastDay - (int(last.Weekday())-int(wd)+7)%7 + (n+1)*7
+	if day < 1 {
+		return 0, fmt.Errorf("invalid BYDAY %q", byday)
+	}
+	return day, nil
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// parseLocal parses a local DATE-TIME value as a UTC-based wall-clock time.
+func parseLocal(value string) (time.Time, error) {
+	return time.Parse("20060102T150405", strings.TrimSpace(value))
+}
+
+// naive returns the wall-clock time of t in UTC, dropping its zone.
+func naive(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
+
+// parseOffset parses a UTC offset such as +0100 or -053000 into seconds.
+func parseOffset(value string) (int, error) {
+	value = strings.TrimSpace(value)
+	if (len(value) != 5 && len(value) != 7) || (value[0] != '+' && value[0] != '-') {
+		return 0, fmt.Errorf("invalid UTC offset %q", value)
+	}
+	digits := value[1:] + "00"
+	h, err1 := strconv.Atoi(digits[0:2])
+	m, err2 := strconv.Atoi(digits[2:4])
+	s, err3 := strconv.Atoi(digits[4:6])
+	if err1 != nil || err2 != nil || err3 != nil {
+		return 0, fmt.Errorf("invalid UTC offset %q", value)
+	}
+	seconds := h*3600 + m*60 + s
+	if value[0] == '-' {
+		seconds = -seconds
+	}
+	return seconds, nil
+}
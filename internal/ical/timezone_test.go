@@ -0,0 +1,104 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// outlookZone is a VTIMEZONE as Outlook writes it, with a TZID that is
+// neither an IANA nor a Windows zone name.
+const outlookZone = "BEGIN:VTIMEZONE\r\n" +
+	"TZID:Customized Time Zone\r\n" +
+	"BEGIN:STANDARD\r\n" +
+	"DTSTART:16010101T030000\r\n" +
+	"TZOFFSETFROM:+0200\r\n" +
+	"TZOFFSETTO:+0100\r\n" +
+	"RRULE:FREQ=YEARLY;BYDAY=-1SU;BYMONTH=10\r\n" +
+	"END:STANDARD\r\n" +
+	"BEGIN:DAYLIGHT\r\n" +
+	"DTSTART:16010101T020000\r\n" +
+	"TZOFFSETFROM:+0100\r\n" +
+	"TZOFFSETTO:+0200\r\n" +
+	"RRULE:FREQ=YEARLY;BYDAY=-1SU;BYMONTH=3\r\n" +
+	"END:DAYLIGHT\r\n" +
+	"END:VTIMEZONE\r\n"
+
+func parseEvent(t *testing.T, props ...string) (*Component, *Component) {
+	t.Helper()
+	data := "BEGIN:VCALENDAR\r\n" + outlookZone + "BEGIN:VEVENT\r\n" +
+		strings.Join(props, "\r\n") + "\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+	cal, err := Parse([]byte(data))
+	require.NoError(t, err)
+	return cal, cal.Find("VEVENT")[0]
+}
+
+func TestComponentTime_Zones(t *testing.T) {
+	tests := []struct {
+		prop string
+		want string
+	}{
+		{"DTSTART;TZID=\"W. Europe Standard Time\":20261020T153000", "2026-10-20T15:30:00+02:00"},
+		{"DTSTART;TZID=/Europe/Berlin:20261020T153000", "2026-10-20T15:30:00+02:00"},
+		{"DTSTART;TZID=Customized Time Zone:20261020T153000", "2026-10-20T15:30:00+02:00"},
+		{"DTSTART;TZID=Customized Time Zone:20261026T153000", "2026-10-26T15:30:00+01:00"},
+		{"DTSTART;TZID=Customized Time Zone:20260115T090000", "2026-01-15T09:00:00+01:00"},
+		{"DTSTART;TZID=Customized Time Zone:20260329T030000", "2026-03-29T03:00:00+02:00"},
+	}
+
+	for _, tt := range tests {
+		cal, ev := parseEvent(t, tt.prop)
+		start, allDay, err := cal.Time(ev.Prop("DTSTART"), time.UTC)
+		require.NoError(t, err, tt.prop)
+		assert.False(t, allDay)
+		assert.Equal(t, tt.want, start.Format(time.RFC3339), tt.prop)
+	}
+}
+
+func TestComponentTime_UnknownZone(t *testing.T) {
+	cal, ev := parseEvent(t, "DTSTART;TZID=Mars Standard Time:20261020T153000")
+	_, _, err := cal.Time(ev.Prop("DTSTART"), time.UTC)
+	assert.ErrorContains(t, err, "unknown time zone")
+
+	// Without the calendar, VTIMEZONE-only zones are unknown too.
+	_, ev = parseEvent(t, "DTSTART;TZID=Customized Time Zone:20261020T153000")
+	_, _, err = ev.Prop("DTSTART").Time(time.UTC)
+	assert.Error(t, err)
+}
+
+func TestComponentTime_ExportedZone(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	tz := VTimezone(berlin,
+		time.Date(2026, 1, 1, 0, 0, 0, 0, berlin),
+		time.Date(2026, 12, 31, 0, 0, 0, 0, berlin))
+	tz.Properties[0].Value = "Berlin (exported)"
+
+	cal := &Component{Name: "VCALENDAR", Components: []*Component{tz}}
+	for _, tt := range []struct{ value, want string }{
+		{"20260215T100000", "2026-02-15T10:00:00+01:00"},
+		{"20260715T100000", "2026-07-15T10:00:00+02:00"},
+		{"20261115T100000", "2026-11-15T10:00:00+01:00"},
+	} {
+		p := Property{Name: "DTSTART", Params: map[string]string{"TZID": "Berlin (exported)"}, Value: tt.value}
+		start, _, err := cal.Time(&p, time.UTC)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, start.Format(time.RFC3339))
+	}
+}
+
+func TestNthWeekday(t *testing.T) {
+	day, err := nthWeekday("-1SU", 2026, time.October)
+	require.NoError(t, err)
+	assert.Equal(t, 25, day)
+
+	day, err = nthWeekday("2SU", 2026, time.March)
+	require.NoError(t, err)
+	assert.Equal(t, 8, day)
+
+	_, err = nthWeekday("5SU", 2026, time.February)
+	assert.Error(t, err)
+}
@@ -0,0 +1,203 @@
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxLineOctets is the maximum length of a content line before folding.
+const maxLineOctets = 75
+
+// Add appends a property. Text values must already be escaped (see Escape).
+func (c *Component) Add(name, value string, params map[string]string) {
+	c.Properties = append(c.Properties, Property{Name: strings.ToUpper(name), Params: params, Value: value})
+}
+
+// String returns the unfolded content line of the property. Parameters are
+// written in name order and quoted when they contain ':', ';' or ','.
+func (p Property) String() string {
+	var b strings.Builder
+	b.WriteString(p.Name)
+
+	keys := make([]string, 0, len(p.Params))
+	for k := range p.Params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		v := strings.ReplaceAll(p.Params[k], `"`, "'")
+		if strings.ContainsAny(v, ":;,") {
+			v = `"` + v + `"`
+		}
+		fmt.Fprintf(&b, ";%s=%s", k, v)
+	}
+
+	b.WriteString(":")
+	b.WriteString(p.Value)
+	return b.String()
+}
+
+// ParseProperty parses a single unfolded content line such as
+// "EXDATE;TZID=Europe/Berlin:20261020T090000".
+func ParseProperty(line string) (Property, error) {
+	return parseLine(line)
+}
+
+// Encode writes a component and its children as iCalendar data with CRLF
+// line endings, folding lines longer than 75 octets.
+func Encode(w io.Writer, c *Component) error {
+	bw := bufio.NewWriter(w)
+	encode(bw, c)
+	return bw.Flush()
+}
+
+func encode(w *bufio.Writer, c *Component) {
+	writeLine(w, "BEGIN:"+c.Name)
+	for _, p := range c.Properties {
+		writeLine(w, p.String())
+	}
+	for _, child := range c.Components {
+		encode(w, child)
+	}
+	writeLine(w, "END:"+c.Name)
+}
+
+// writeLine writes a content line, folding it without splitting UTF-8
+// sequences.
+func writeLine(w *bufio.Writer, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		w.WriteString(line[:cut])
+		w.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space, which counts.
+		limit = maxLineOctets - 1
+	}
+	w.WriteString(line)
+	w.WriteString("\r\n")
+}
+
+// FormatDateTime formats t as a DATE-TIME value. UTC times end in Z; other
+// times are local to their location (for use with a TZID parameter).
+func FormatDateTime(t time.Time) string {
+	if t.Location() == time.UTC {
+		return t.Format("20060102T150405Z")
+	}
+	return t.Format("20060102T150405")
+}
+
+// ParseDuration parses a DURATION value such as PT1H30M, P1D or -PT15M.
+func ParseDuration(value string) (time.Duration, error) {
+	s := strings.ToUpper(strings.TrimSpace(value))
+	sign := time.Duration(1)
+	if strings.HasPrefix(s, "-") {
+		sign, s = -1, s[1:]
+	}
+	s = strings.TrimPrefix(s, "+")
+	if !strings.HasPrefix(s, "P") || len(s) < 3 {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	s = s[1:]
+
+	var d time.Duration
+	inTime := false
+	num := ""
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			num += string(r)
+			continue
+		case r == 'T':
+			inTime = true
+			continue
+		}
+
+		n, err := strconv.Atoi(num)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		num = ""
+
+		switch {
+		case r == 'W' && !inTime:
+			d += time.Duration(n) * 7 * 24 * time.Hour
+		case r == 'D' && !inTime:
+			d += time.Duration(n) * 24 * time.Hour
+		case r == 'H' && inTime:
+			d += time.Duration(n) * time.Hour
+		case r == 'M' && inTime:
+			d += time.Duration(n) * time.Minute
+		case r == 'S' && inTime:
+			d += time.Duration(n) * time.Second
+		default:
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+	}
+	if num != "" {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+
+	return sign * d, nil
+}
+
+// VTimezone builds a VTIMEZONE component for loc with one observance per
+// offset change between from and to, plus the observance in effect at from.
+func VTimezone(loc *time.Location, from, to time.Time) *Component {
+	tz := &Component{Name: "VTIMEZONE"}
+	tz.Add("TZID", loc.String(), nil)
+
+	t := from.In(loc)
+	_, offset := t.Zone()
+	tz.Components = append(tz.Components, observance(t, offset))
+
+	for {
+		_, end := t.ZoneBounds()
+		if end.IsZero() || end.After(to) {
+			break
+		}
+		prev := offset
+		t = end.In(loc)
+		_, offset = t.Zone()
+		if offset != prev {
+			tz.Components = append(tz.Components, observance(t, prev))
+		}
+	}
+
+	return tz
+}
+
+// observance returns the STANDARD or DAYLIGHT component starting at t,
+// whose DTSTART is local time in the previous offset.
+func observance(t time.Time, prevOffset int) *Component {
+	name := "STANDARD"
+	if t.IsDST() {
+		name = "DAYLIGHT"
+	}
+	abbrev, offset := t.Zone()
+
+	c := &Component{Name: name}
+	c.Add("DTSTART", t.In(time.FixedZone("", prevOffset)).Format("20060102T150405"), nil)
+	c.Add("TZOFFSETFROM", formatOffset(prevOffset), nil)
+	c.Add("TZOFFSETTO", formatOffset(offset), nil)
+	c.Add("TZNAME", abbrev, nil)
+	return c
+}
+
+// formatOffset formats a UTC offset in seconds as +HHMM.
+func formatOffset(seconds int) string {
+	sign := "+"
+	if seconds < 0 {
+		sign, seconds = "-", -seconds
+	}
+	return fmt.Sprintf("%s%02d%02d", sign, seconds/3600, seconds%3600/60)
+}
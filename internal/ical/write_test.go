@@ -0,0 +1,93 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncode_RoundTrip(t *testing.T) {
+	ev := &Component{Name: "VEVENT"}
+	ev.Add("UID", "abc@example.com", nil)
+	ev.Add("SUMMARY", Escape("Planning, Q4"), nil)
+	ev.Add("DESCRIPTION", Escape(strings.Repeat("Grüße aus Berlin. ", 10)), nil)
+	ev.Add("ATTENDEE", "mailto:jane@example.com", map[string]string{"CN": "Doe, Jane", "PARTSTAT": "ACCEPTED"})
+	cal := &Component{Name: "VCALENDAR", Components: []*Component{ev}}
+	cal.Add("VERSION", "2.0", nil)
+
+	var buf bytes.Buffer
+	require.NoError(t, Encode(&buf, cal))
+
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), 75, line)
+	}
+	assert.Contains(t, buf.String(), "ATTENDEE;CN=\"Doe, Jane\";PARTSTAT=ACCEPTED:mailto:jane@example.com\r\n")
+
+	parsed, err := Parse(buf.Bytes())
+	require.NoError(t, err)
+	got := parsed.Find("VEVENT")[0]
+	assert.Equal(t, "Planning, Q4", got.Text("SUMMARY"))
+	assert.Equal(t, strings.Repeat("Grüße aus Berlin. ", 10), got.Text("DESCRIPTION"))
+	assert.Equal(t, "Doe, Jane", got.Prop("ATTENDEE").Params["CN"])
+}
+
+func TestParseProperty(t *testing.T) {
+	p, err := ParseProperty("EXDATE;TZID=Europe/Berlin:20261020T090000")
+	require.NoError(t, err)
+	assert.Equal(t, "EXDATE", p.Name)
+	assert.Equal(t, "Europe/Berlin", p.Params["TZID"])
+	assert.Equal(t, "EXDATE;TZID=Europe/Berlin:20261020T090000", p.String())
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := map[string]time.Duration{
+		"PT1H30M": 90 * time.Minute,
+		"P1D":     24 * time.Hour,
+		"P1W":     7 * 24 * time.Hour,
+		"-PT15M":  -15 * time.Minute,
+		"P1DT2H":  26 * time.Hour,
+	}
+	for value, want := range tests {
+		got, err := ParseDuration(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, want, got, value)
+	}
+
+	for _, value := range []string{"1H", "PT", "P1H", "PT5"} {
+		_, err := ParseDuration(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestVTimezone(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	tz := VTimezone(berlin,
+		time.Date(2026, 1, 1, 0, 0, 0, 0, berlin),
+		time.Date(2026, 12, 31, 0, 0, 0, 0, berlin))
+
+	assert.Equal(t, "Europe/Berlin", tz.Text("TZID"))
+	require.Len(t, tz.Components, 3)
+
+	initial, summer, winter := tz.Components[0], tz.Components[1], tz.Components[2]
+	assert.Equal(t, "STANDARD", initial.Name)
+	assert.Equal(t, "+0100", initial.Text("TZOFFSETTO"))
+
+	assert.Equal(t, "DAYLIGHT", summer.Name)
+	assert.Equal(t, "20260329T020000", summer.Text("DTSTART"))
+	assert.Equal(t, "+0100", summer.Text("TZOFFSETFROM"))
+	assert.Equal(t, "+0200", summer.Text("TZOFFSETTO"))
+	assert.Equal(t, "CEST", summer.Text("TZNAME"))
+
+	assert.Equal(t, "STANDARD", winter.Name)
+	assert.Equal(t, "20261025T030000", winter.Text("DTSTART"))
+
+	utc := VTimezone(time.UTC, time.Now(), time.Now().AddDate(1, 0, 0))
+	require.Len(t, utc.Components, 1)
+	assert.Equal(t, "+0000", utc.Components[0].Text("TZOFFSETFROM"))
+}
@@ -30,6 +30,9 @@ const (
 	ErrInternal ErrorCode = "INTERNAL_ERROR"
 	// ErrAPIError indicates a Google API error.
 	ErrAPIError ErrorCode = "API_ERROR"
+	// ErrConflict indicates the request clashes with existing data, such as
+	// an event overlapping busy time.
+	ErrConflict ErrorCode = "CONFLICT"
 )
 
 // Response is the standard JSON response envelope.
@@ -27,4 +27,5 @@ func TestErrorCodes(t *testing.T) {
 	assert.Equal(t, ErrorCode("INVALID_INPUT"), ErrInvalidInput)
 	assert.Equal(t, ErrorCode("INTERNAL_ERROR"), ErrInternal)
 	assert.Equal(t, ErrorCode("API_ERROR"), ErrAPIError)
+	assert.Equal(t, ErrorCode("CONFLICT"), ErrConflict)
 }
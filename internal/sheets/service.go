@@ -78,3 +78,14 @@ type CreateResult struct {
 	Title         string `json:"title"`
 	URL           string `json:"url"`
 }
+
+// ColumnName returns the A1-notation column name for a zero-based column
+// index (0 -> A, 25 -> Z, 26 -> AA).
+func ColumnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}